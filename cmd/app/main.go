@@ -5,12 +5,22 @@ import (
 	"log"
 	"os"
 	"os/signal"
+	"service/internal/auth"
 	"service/internal/config"
 	"service/internal/datasource/database"
 	"service/internal/datasource/repository"
+	"service/internal/events"
+	"service/internal/health"
+	"service/internal/metrics"
+	"service/internal/notify"
+	"service/internal/ratelimit"
 	"service/internal/service"
+	"service/internal/tracing"
 	"service/internal/web"
+	"service/internal/worker"
 	"service/logger"
+	"service/migrations"
+	"sync"
 
 	"syscall"
 
@@ -25,18 +35,132 @@ import (
 // @host localhost:8080
 // @BasePath /
 // @schemes http
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name X-API-Key
 func main() {
 	e := echo.New()
 	cfg := config.LoadConfig()
-	s := web.NewServer(cfg)
+	logs, err := logger.Init(cfg)
+	if err != nil {
+		log.Fatalln("error init logger: %w", err)
+	}
+	if e.IPExtractor, err = web.NewIPExtractor(cfg.GetTrustedProxies()); err != nil {
+		log.Fatalln("error init ip extractor: %w", err)
+	}
+
+	shutdownTracing := func(context.Context) error { return nil }
+	if cfg.GetTracingEnabled() {
+		shutdown, err := tracing.Init(context.Background(), cfg)
+		if err != nil {
+			log.Fatalln("error init tracing: %w", err)
+		}
+		shutdownTracing = shutdown
+	}
 
 	db, err := database.ConnectDB(context.Background(), cfg)
 	if err != nil {
 		log.Fatalln("error connect db: %w", err)
 	}
+	// Background jobs work across tenants, with row-level security they need
+	// their own connections as a role that bypasses it.
+	workerDb := db
+	if cfg.GetTenancyRLS() {
+		if workerDb, err = database.ConnectWorkerDB(context.Background(), cfg); err != nil {
+			log.Fatalln("error connect worker db: %w", err)
+		}
+	}
+	storage := repository.NewDatabase(db)
+	jobs := repository.NewDatabase(workerDb)
+	var hooks []repository.Hook
+	var m *metrics.Metrics
+	if cfg.GetMetricsEnabled() {
+		m = metrics.New()
+		m.Register(
+			metrics.NewPoolCollector("requests", db),
+			metrics.NewSubsCollector(jobs, cfg.GetMetricsScrapeTimeout()),
+		)
+		if workerDb != db {
+			m.Register(metrics.NewPoolCollector("workers", workerDb))
+		}
+		hooks = append(hooks, m.StorageHook)
+	}
+	if cfg.GetTracingEnabled() {
+		hooks = append(hooks, tracing.StorageHook)
+	}
+	if len(hooks) > 0 {
+		storage = repository.NewObserved(storage, hooks...)
+		jobs = repository.NewObserved(jobs, hooks...)
+	}
+	schemaVersion, err := migrations.LatestVersion()
+	if err != nil {
+		log.Fatalln("error read migrations: %w", err)
+	}
+	probe := health.NewProbe(storage, schemaVersion, cfg)
+	s := web.NewServer(cfg, probe)
+	publisher, err := events.NewPublisher(cfg)
+	if err != nil {
+		log.Fatalln("error create events publisher: %w", err)
+	}
+	notifier, err := notify.NewNotifier(cfg, logs)
+	if err != nil {
+		log.Fatalln("error create reminders notifier: %w", err)
+	}
+
+	var authn auth.Authenticator
+	if cfg.GetAuthEnabled() {
+		jwtAuth, err := auth.NewJWTAuthenticator(context.Background(), cfg)
+		if err != nil {
+			log.Fatalln("error create authenticator: %w", err)
+		}
+		authn = jwtAuth
+		if cfg.GetAuthAPIKeys() {
+			authn = auth.NewChain(jwtAuth, auth.NewAPIKeyAuthenticator(jobs))
+		}
+	}
 
-	r := web.NewRouting(service.NewService(repository.NewDatabase(db)), logger.Init(cfg))
+	var limiter *ratelimit.Limiter
+	if cfg.GetRateLimitEnabled() {
+		store, err := ratelimit.NewStore(cfg, jobs)
+		if err != nil {
+			log.Fatalln("error create rate limit store: %w", err)
+		}
+		if limiter, err = ratelimit.NewLimiter(store, cfg); err != nil {
+			log.Fatalln("error create rate limiter: %w", err)
+		}
+	}
+
+	svc, err := service.NewService(storage, cfg, logs)
+	if err != nil {
+		log.Fatalln("error create service: %w", err)
+	}
+	if cfg.GetTracingEnabled() {
+		svc = tracing.NewService(svc)
+	}
+	r := web.NewRouting(svc, authn, limiter, probe, m, cfg, logs)
 	r.RegisterRoutes(e)
+	// Workers are registered with the probe before the server starts, so
+	// readiness never reports an instance whose workers are not running yet.
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	runWorker := func(name string, run func(context.Context)) {
+		stopped := probe.Worker(name)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer stopped()
+			run(ctx)
+		}()
+	}
+	runWorker("renewal", worker.NewRenewal(jobs, cfg, logs).Run)
+	runWorker("relay", worker.NewRelay(jobs, publisher, cfg, logs).Run)
+	runWorker("webhooks", worker.NewWebhooks(jobs, cfg, logs).Run)
+	runWorker("reminders", worker.NewReminders(jobs, notifier, cfg, logs).Run)
+	runWorker("budgets", worker.NewBudgets(jobs, cfg, logs).Run)
+
 	go func() {
 		s.Start(e)
 	}()
@@ -46,5 +170,14 @@ func main() {
 
 	<-quit
 	s.Shutdown(e)
+	cancel()
+	wg.Wait()
+	publisher.Close()
+	if err := shutdownTracing(context.Background()); err != nil {
+		log.Printf("error shutdown tracing: %v", err)
+	}
+	if workerDb != db {
+		workerDb.Close()
+	}
 	db.Close()
 }
@@ -0,0 +1,92 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"service/internal/datasource/repository"
+	"service/internal/dto"
+	"slices"
+	"strconv"
+)
+
+const (
+	HeaderAPIKey = "X-API-Key"
+
+	apiKeyPrefix = "sk_"
+	// apiKeyVisible is the number of leading characters kept in clear text
+	// so that a key can be recognised in listings.
+	apiKeyVisible = len(apiKeyPrefix) + 8
+)
+
+type (
+	APIKeyStore interface {
+		UseAPIKey(ctx context.Context, keyHash string) (dto.APIKeyAuthFromDb, error)
+	}
+
+	// APIKeyAuthenticator accepts keys from the X-API-Key header. The key
+	// owner becomes the subject and the key keeps its scopes; the admin scope
+	// grants the admin role. Keys without an owner get the subject
+	// apikey:<id> and are only accepted with the admin scope, since a regular
+	// caller is scoped to its own user ID.
+	APIKeyAuthenticator struct {
+		store APIKeyStore
+	}
+)
+
+func NewAPIKeyAuthenticator(store APIKeyStore) *APIKeyAuthenticator {
+	return &APIKeyAuthenticator{store: store}
+}
+
+// NewAPIKey returns a random key together with its listing prefix and the
+// hash to store. The key itself is never persisted.
+func NewAPIKey() (key, prefix, hash string, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", "", fmt.Errorf("%w", err)
+	}
+	key = apiKeyPrefix + hex.EncodeToString(b)
+	return key, key[:apiKeyVisible], HashAPIKey(key), nil
+}
+
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func (a *APIKeyAuthenticator) Authenticate(ctx context.Context, r *http.Request) (Principal, error) {
+	key := r.Header.Get(HeaderAPIKey)
+	if key == "" {
+		return Principal{}, ErrNoCredentials
+	}
+	found, err := a.store.UseAPIKey(ctx, HashAPIKey(key))
+	if err != nil {
+		if errors.Is(err, repository.ErrAPIKeyNotFound) {
+			return Principal{}, fmt.Errorf("%w: unknown, revoked or expired api key", ErrInvalidToken)
+		}
+		return Principal{}, fmt.Errorf("%w", err)
+	}
+	scopes := found.Scopes
+	if scopes == nil {
+		scopes = []string{}
+	}
+	// Roles come from the scopes only, so a key can never carry the
+	// operator role.
+	p := Principal{Tenant: found.TenantId, Scopes: scopes}
+	if slices.Contains(scopes, ScopeAdmin) {
+		p.Roles = []string{RoleAdmin}
+	}
+	switch {
+	case found.UserId != nil:
+		p.Subject = *found.UserId
+	case p.IsAdmin():
+		p.Subject = "apikey:" + strconv.Itoa(found.Id)
+	default:
+		return Principal{}, fmt.Errorf("%w: api key has neither an owner nor the admin scope", ErrInvalidToken)
+	}
+	return p, nil
+}
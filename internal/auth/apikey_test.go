@@ -0,0 +1,83 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"service/internal/datasource/repository"
+	"service/internal/dto"
+	"testing"
+)
+
+type testAPIKeyStore map[string]dto.APIKeyAuthFromDb
+
+func (s testAPIKeyStore) UseAPIKey(_ context.Context, keyHash string) (dto.APIKeyAuthFromDb, error) {
+	found, ok := s[keyHash]
+	if !ok {
+		return dto.APIKeyAuthFromDb{}, repository.ErrAPIKeyNotFound
+	}
+	return found, nil
+}
+
+func TestAPIKeyAuthenticate(t *testing.T) {
+	owner := "60601fee-2bf1-4721-ae6f-7636e79a0cba"
+	store := testAPIKeyStore{
+		HashAPIKey("sk_owned"):    {Id: 1, TenantId: "acme", UserId: &owner, Scopes: []string{ScopeWrite}},
+		HashAPIKey("sk_admin"):    {Id: 2, TenantId: "acme", Scopes: []string{ScopeAdmin}},
+		HashAPIKey("sk_orphan"):   {Id: 3, TenantId: "acme", Scopes: []string{ScopeWrite}},
+		HashAPIKey("sk_delegate"): {Id: 4, TenantId: "acme", UserId: &owner, Scopes: []string{ScopeAdmin}},
+		HashAPIKey("sk_operator"): {Id: 5, TenantId: "acme", UserId: &owner, Scopes: []string{RoleOperator}},
+		HashAPIKey("sk_reader"):   {Id: 6, TenantId: "acme", UserId: &owner, Scopes: []string{ScopeRead}},
+		HashAPIKey("sk_empty"):    {Id: 7, TenantId: "acme", UserId: &owner},
+	}
+	tests := []struct {
+		name        string
+		key         string
+		wantErr     error
+		wantSubject string
+		wantAdmin   bool
+		wantWrite   bool
+	}{
+		{name: "missing", wantErr: ErrNoCredentials},
+		{name: "unknown", key: "sk_unknown", wantErr: ErrInvalidToken},
+		{name: "owned", key: "sk_owned", wantSubject: owner, wantWrite: true},
+		{name: "ownerless admin", key: "sk_admin", wantSubject: "apikey:2", wantAdmin: true, wantWrite: true},
+		{name: "ownerless without admin", key: "sk_orphan", wantErr: ErrInvalidToken},
+		{name: "owned admin", key: "sk_delegate", wantSubject: owner, wantAdmin: true, wantWrite: true},
+		{name: "operator is not a scope", key: "sk_operator", wantSubject: owner},
+		{name: "read only", key: "sk_reader", wantSubject: owner},
+		{name: "no scopes", key: "sk_empty", wantSubject: owner},
+	}
+	a := NewAPIKeyAuthenticator(store)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/", nil)
+			if tt.key != "" {
+				r.Header.Set(HeaderAPIKey, tt.key)
+			}
+			p, err := a.Authenticate(context.Background(), r)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Authenticate() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Authenticate() error = %v", err)
+			}
+			if p.Subject != tt.wantSubject || p.Tenant != "acme" || p.IsAdmin() != tt.wantAdmin || p.IsOperator() || p.CanWrite() != tt.wantWrite {
+				t.Fatalf("Authenticate() = %+v, want subject %s admin %v write %v", p, tt.wantSubject, tt.wantAdmin, tt.wantWrite)
+			}
+		})
+	}
+}
+
+func TestNewAPIKey(t *testing.T) {
+	key, prefix, hash, err := NewAPIKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(key) != len(apiKeyPrefix)+64 || prefix != key[:apiKeyVisible] || hash != HashAPIKey(key) {
+		t.Fatalf("NewAPIKey() = %q, %q, %q", key, prefix, hash)
+	}
+}
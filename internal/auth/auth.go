@@ -0,0 +1,74 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+)
+
+const (
+	RoleAdmin = "admin"
+	// RoleOperator is held by whoever runs the service, not by any tenant.
+	// It only comes from the JWT roles claim: API keys never carry it.
+	RoleOperator = "operator"
+
+	// API key scopes: read allows only safe methods (GET, HEAD), write
+	// allows any request and admin additionally grants the admin role.
+	ScopeRead  = "read"
+	ScopeWrite = "write"
+	ScopeAdmin = "admin"
+)
+
+var Scopes = []string{ScopeRead, ScopeWrite, ScopeAdmin}
+
+var (
+	// ErrNoCredentials is returned by an Authenticator when the request
+	// carries none of the credentials it understands.
+	ErrNoCredentials = errors.New("missing credentials")
+	ErrInvalidToken  = errors.New("invalid token")
+)
+
+type (
+	// Principal is the authenticated caller. Tenant is empty when the
+	// credentials do not name one. Scopes are set for API keys only; a nil
+	// Scopes (JWT callers) is not restricted by scope.
+	Principal struct {
+		Subject string   `json:"subject"`
+		Tenant  string   `json:"tenant"`
+		Roles   []string `json:"roles"`
+		Scopes  []string `json:"scopes,omitempty"`
+	}
+
+	Authenticator interface {
+		Authenticate(ctx context.Context, r *http.Request) (Principal, error)
+	}
+
+	principalKey struct{}
+)
+
+func (p Principal) HasRole(role string) bool {
+	return slices.Contains(p.Roles, role)
+}
+
+func (p Principal) IsAdmin() bool {
+	return p.HasRole(RoleAdmin)
+}
+
+func (p Principal) IsOperator() bool {
+	return p.HasRole(RoleOperator)
+}
+
+// CanWrite reports whether the caller may send requests that change data.
+func (p Principal) CanWrite() bool {
+	return p.Scopes == nil || slices.Contains(p.Scopes, ScopeWrite) || slices.Contains(p.Scopes, ScopeAdmin)
+}
+
+func WithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+func FromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(Principal)
+	return p, ok
+}
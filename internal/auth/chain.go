@@ -0,0 +1,32 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+type chain []Authenticator
+
+// NewChain tries each authenticator in order and uses the first one that
+// finds its credentials in the request. Nil authenticators are skipped.
+func NewChain(authns ...Authenticator) Authenticator {
+	var c chain
+	for _, a := range authns {
+		if a != nil {
+			c = append(c, a)
+		}
+	}
+	return c
+}
+
+func (c chain) Authenticate(ctx context.Context, r *http.Request) (Principal, error) {
+	for _, a := range c {
+		p, err := a.Authenticate(ctx, r)
+		if errors.Is(err, ErrNoCredentials) {
+			continue
+		}
+		return p, err
+	}
+	return Principal{}, ErrNoCredentials
+}
@@ -0,0 +1,145 @@
+package auth
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// jwks holds RSA public keys from a JSON Web Key Set loaded from a file or
+// URL. URL sets are reloaded every refresh interval and when a token refers
+// to an unknown key id, but not more often than once a minute.
+const jwksMinRefresh = time.Minute
+
+type jwks struct {
+	mu      sync.RWMutex
+	loadMu  sync.Mutex
+	file    string
+	url     string
+	client  *http.Client
+	refresh time.Duration
+	loaded  time.Time
+	keys    map[string]*rsa.PublicKey
+}
+
+type jwkSet struct {
+	Keys []struct {
+		Kty string `json:"kty"`
+		Kid string `json:"kid"`
+		Use string `json:"use"`
+		N   string `json:"n"`
+		E   string `json:"e"`
+	} `json:"keys"`
+}
+
+func newJWKS(ctx context.Context, file, url string, refresh time.Duration) (*jwks, error) {
+	k := &jwks{
+		file:    file,
+		url:     url,
+		client:  &http.Client{Timeout: 10 * time.Second},
+		refresh: refresh,
+	}
+	if err := k.load(ctx); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+func (k *jwks) key(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	k.mu.RLock()
+	key, ok := k.keys[kid]
+	age := time.Since(k.loaded)
+	k.mu.RUnlock()
+	if k.url != "" && (age > k.refresh || (!ok && age > jwksMinRefresh)) {
+		k.reload(ctx)
+		k.mu.RLock()
+		key, ok = k.keys[kid]
+		k.mu.RUnlock()
+	}
+	if !ok {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+	return key, nil
+}
+
+// reload refetches the set unless another request has just done so. Fetch
+// errors keep the previous keys in place.
+func (k *jwks) reload(ctx context.Context) {
+	k.loadMu.Lock()
+	defer k.loadMu.Unlock()
+	k.mu.RLock()
+	age := time.Since(k.loaded)
+	k.mu.RUnlock()
+	if age < jwksMinRefresh {
+		return
+	}
+	if err := k.load(ctx); err != nil {
+		k.mu.Lock()
+		k.loaded = time.Now()
+		k.mu.Unlock()
+	}
+}
+
+func (k *jwks) load(ctx context.Context) error {
+	var body []byte
+	var err error
+	if k.file != "" {
+		body, err = os.ReadFile(k.file)
+	} else {
+		body, err = k.fetch(ctx)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load jwks: %w", err)
+	}
+	var set jwkSet
+	if err := json.Unmarshal(body, &set); err != nil {
+		return fmt.Errorf("failed to parse jwks: %w", err)
+	}
+	keys := make(map[string]*rsa.PublicKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Kty != "RSA" || (jwk.Use != "" && jwk.Use != "sig") {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+		if err != nil {
+			return fmt.Errorf("invalid modulus of key %q: %w", jwk.Kid, err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(jwk.E)
+		if err != nil {
+			return fmt.Errorf("invalid exponent of key %q: %w", jwk.Kid, err)
+		}
+		keys[jwk.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+	k.mu.Lock()
+	k.keys = keys
+	k.loaded = time.Now()
+	k.mu.Unlock()
+	return nil
+}
+
+func (k *jwks) fetch(ctx context.Context) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, k.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := k.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("jwks url responded with status %d", resp.StatusCode)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+}
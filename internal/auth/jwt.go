@@ -0,0 +1,120 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type (
+	JWTConfig interface {
+		GetAuthHS256Secret() string
+		GetAuthJWKSFile() string
+		GetAuthJWKSURL() string
+		GetAuthJWKSRefresh() time.Duration
+		GetAuthIssuer() string
+		GetAuthAudience() string
+		GetAuthRolesClaim() string
+		GetAuthTenantClaim() string
+	}
+
+	// JWTAuthenticator verifies bearer tokens signed with HS256 using a
+	// shared secret or with RS256 using keys from a JWKS.
+	JWTAuthenticator struct {
+		secret      []byte
+		jwks        *jwks
+		parser      *jwt.Parser
+		rolesClaim  string
+		tenantClaim string
+	}
+)
+
+func NewJWTAuthenticator(ctx context.Context, cfg JWTConfig) (*JWTAuthenticator, error) {
+	a := &JWTAuthenticator{
+		secret:      []byte(cfg.GetAuthHS256Secret()),
+		rolesClaim:  cfg.GetAuthRolesClaim(),
+		tenantClaim: cfg.GetAuthTenantClaim(),
+	}
+	var methods []string
+	if len(a.secret) > 0 {
+		methods = append(methods, jwt.SigningMethodHS256.Alg())
+	}
+	if cfg.GetAuthJWKSFile() != "" || cfg.GetAuthJWKSURL() != "" {
+		keys, err := newJWKS(ctx, cfg.GetAuthJWKSFile(), cfg.GetAuthJWKSURL(), cfg.GetAuthJWKSRefresh())
+		if err != nil {
+			return nil, err
+		}
+		a.jwks = keys
+		methods = append(methods, jwt.SigningMethodRS256.Alg())
+	}
+	if len(methods) == 0 {
+		return nil, fmt.Errorf("auth requires hs256_secret, jwks_file or jwks_url")
+	}
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods(methods),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(30 * time.Second),
+	}
+	if cfg.GetAuthIssuer() != "" {
+		opts = append(opts, jwt.WithIssuer(cfg.GetAuthIssuer()))
+	}
+	if cfg.GetAuthAudience() != "" {
+		opts = append(opts, jwt.WithAudience(cfg.GetAuthAudience()))
+	}
+	a.parser = jwt.NewParser(opts...)
+	return a, nil
+}
+
+func (a *JWTAuthenticator) Authenticate(ctx context.Context, r *http.Request) (Principal, error) {
+	header := r.Header.Get("Authorization")
+	raw, ok := strings.CutPrefix(header, "Bearer ")
+	if !ok || raw == "" {
+		return Principal{}, ErrNoCredentials
+	}
+	claims := jwt.MapClaims{}
+	_, err := a.parser.ParseWithClaims(raw, claims, func(token *jwt.Token) (any, error) {
+		switch token.Method.Alg() {
+		case jwt.SigningMethodHS256.Alg():
+			return a.secret, nil
+		case jwt.SigningMethodRS256.Alg():
+			kid, _ := token.Header["kid"].(string)
+			return a.jwks.key(ctx, kid)
+		}
+		return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+	})
+	if err != nil {
+		return Principal{}, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+	subject, err := claims.GetSubject()
+	if err != nil || subject == "" {
+		return Principal{}, fmt.Errorf("%w: missing sub claim", ErrInvalidToken)
+	}
+	tenant, _ := claims[a.tenantClaim].(string)
+	return Principal{
+		Subject: subject,
+		Tenant:  tenant,
+		Roles:   rolesClaim(claims[a.rolesClaim]),
+	}, nil
+}
+
+// rolesClaim accepts roles as a JSON array or as a space separated string
+// like the OAuth scope claim.
+func rolesClaim(v any) []string {
+	switch roles := v.(type) {
+	case string:
+		return strings.Fields(roles)
+	case []any:
+		out := make([]string, 0, len(roles))
+		for _, role := range roles {
+			if s, ok := role.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
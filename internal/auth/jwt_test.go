@@ -0,0 +1,272 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type testJWTConfig struct {
+	secret   string
+	jwksURL  string
+	issuer   string
+	audience string
+}
+
+func (c testJWTConfig) GetAuthHS256Secret() string        { return c.secret }
+func (c testJWTConfig) GetAuthJWKSFile() string           { return "" }
+func (c testJWTConfig) GetAuthJWKSURL() string            { return c.jwksURL }
+func (c testJWTConfig) GetAuthJWKSRefresh() time.Duration { return time.Hour }
+func (c testJWTConfig) GetAuthIssuer() string             { return c.issuer }
+func (c testJWTConfig) GetAuthAudience() string           { return c.audience }
+func (c testJWTConfig) GetAuthRolesClaim() string         { return "roles" }
+func (c testJWTConfig) GetAuthTenantClaim() string        { return "tenant" }
+
+// testJWKSServer serves the public halves of keys as a JWKS and counts the
+// fetches.
+type testJWKSServer struct {
+	*httptest.Server
+	mu      sync.Mutex
+	keys    map[string]*rsa.PrivateKey
+	fetches atomic.Int32
+}
+
+func newTestJWKSServer(t *testing.T, keys map[string]*rsa.PrivateKey) *testJWKSServer {
+	t.Helper()
+	s := &testJWKSServer{keys: keys}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.fetches.Add(1)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		var set jwkSet
+		for kid, key := range s.keys {
+			set.Keys = append(set.Keys, struct {
+				Kty string `json:"kty"`
+				Kid string `json:"kid"`
+				Use string `json:"use"`
+				N   string `json:"n"`
+				E   string `json:"e"`
+			}{
+				Kty: "RSA",
+				Kid: kid,
+				Use: "sig",
+				N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			})
+		}
+		json.NewEncoder(w).Encode(set)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *testJWKSServer) addKey(kid string, key *rsa.PrivateKey) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys[kid] = key
+}
+
+func newRSAKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	return key
+}
+
+func sign(t *testing.T, method jwt.SigningMethod, kid string, key any, claims jwt.MapClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	raw, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+	return raw
+}
+
+func authenticate(a *JWTAuthenticator, raw string) (Principal, error) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Authorization", "Bearer "+raw)
+	return a.Authenticate(context.Background(), r)
+}
+
+func validClaims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"sub":    "60601fee-2bf1-4721-ae6f-7636e79a0cba",
+		"exp":    time.Now().Add(time.Hour).Unix(),
+		"roles":  "admin reader",
+		"tenant": "acme",
+		"iss":    "issuer",
+		"aud":    "subs",
+	}
+}
+
+func TestJWTAuthenticatorHS256(t *testing.T) {
+	const secret = "hs256-secret"
+	a, err := NewJWTAuthenticator(context.Background(), testJWTConfig{secret: secret, issuer: "issuer", audience: "subs"})
+	if err != nil {
+		t.Fatalf("failed to create authenticator: %v", err)
+	}
+	with := func(edit func(jwt.MapClaims)) jwt.MapClaims {
+		claims := validClaims()
+		edit(claims)
+		return claims
+	}
+	now := time.Now()
+	tests := []struct {
+		name    string
+		token   string
+		wantErr bool
+	}{
+		{name: "valid", token: sign(t, jwt.SigningMethodHS256, "", []byte(secret), validClaims())},
+		{name: "expired within leeway", token: sign(t, jwt.SigningMethodHS256, "", []byte(secret), with(func(c jwt.MapClaims) {
+			c["exp"] = now.Add(-10 * time.Second).Unix()
+		}))},
+		{name: "expired", wantErr: true, token: sign(t, jwt.SigningMethodHS256, "", []byte(secret), with(func(c jwt.MapClaims) {
+			c["exp"] = now.Add(-time.Minute).Unix()
+		}))},
+		{name: "missing exp", wantErr: true, token: sign(t, jwt.SigningMethodHS256, "", []byte(secret), with(func(c jwt.MapClaims) {
+			delete(c, "exp")
+		}))},
+		{name: "not yet valid", wantErr: true, token: sign(t, jwt.SigningMethodHS256, "", []byte(secret), with(func(c jwt.MapClaims) {
+			c["nbf"] = now.Add(time.Minute).Unix()
+		}))},
+		{name: "nbf within leeway", token: sign(t, jwt.SigningMethodHS256, "", []byte(secret), with(func(c jwt.MapClaims) {
+			c["nbf"] = now.Add(10 * time.Second).Unix()
+		}))},
+		{name: "wrong issuer", wantErr: true, token: sign(t, jwt.SigningMethodHS256, "", []byte(secret), with(func(c jwt.MapClaims) {
+			c["iss"] = "other"
+		}))},
+		{name: "wrong audience", wantErr: true, token: sign(t, jwt.SigningMethodHS256, "", []byte(secret), with(func(c jwt.MapClaims) {
+			c["aud"] = "other"
+		}))},
+		{name: "missing sub", wantErr: true, token: sign(t, jwt.SigningMethodHS256, "", []byte(secret), with(func(c jwt.MapClaims) {
+			delete(c, "sub")
+		}))},
+		{name: "wrong secret", wantErr: true, token: sign(t, jwt.SigningMethodHS256, "", []byte("other"), validClaims())},
+		{name: "HS512", wantErr: true, token: sign(t, jwt.SigningMethodHS512, "", []byte(secret), validClaims())},
+		{name: "alg none", wantErr: true, token: sign(t, jwt.SigningMethodNone, "", jwt.UnsafeAllowNoneSignatureType, validClaims())},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := authenticate(a, tt.token)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidToken) {
+					t.Fatalf("error = %v, want %v", err, ErrInvalidToken)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if p.Subject != "60601fee-2bf1-4721-ae6f-7636e79a0cba" || p.Tenant != "acme" || !p.IsAdmin() {
+				t.Fatalf("unexpected principal: %+v", p)
+			}
+		})
+	}
+}
+
+func TestJWTAuthenticatorAlgConfusion(t *testing.T) {
+	key := newRSAKey(t)
+	srv := newTestJWKSServer(t, map[string]*rsa.PrivateKey{"k1": key})
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatalf("failed to encode public key: %v", err)
+	}
+	publicPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+
+	tests := []struct {
+		name   string
+		secret string
+	}{
+		{name: "jwks only"},
+		{name: "jwks and secret", secret: "hs256-secret"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := NewJWTAuthenticator(context.Background(), testJWTConfig{secret: tt.secret, jwksURL: srv.URL})
+			if err != nil {
+				t.Fatalf("failed to create authenticator: %v", err)
+			}
+			claims := validClaims()
+			delete(claims, "iss")
+			delete(claims, "aud")
+			if _, err := authenticate(a, sign(t, jwt.SigningMethodRS256, "k1", key, claims)); err != nil {
+				t.Fatalf("valid RS256 token rejected: %v", err)
+			}
+			// A token HMAC-signed with the public key must not verify.
+			forged := sign(t, jwt.SigningMethodHS256, "k1", publicPEM, claims)
+			if _, err := authenticate(a, forged); !errors.Is(err, ErrInvalidToken) {
+				t.Fatalf("forged HS256 token: error = %v, want %v", err, ErrInvalidToken)
+			}
+			forged = sign(t, jwt.SigningMethodHS256, "k1", der, claims)
+			if _, err := authenticate(a, forged); !errors.Is(err, ErrInvalidToken) {
+				t.Fatalf("forged HS256 token: error = %v, want %v", err, ErrInvalidToken)
+			}
+		})
+	}
+}
+
+func TestJWTAuthenticatorUnknownKid(t *testing.T) {
+	key := newRSAKey(t)
+	srv := newTestJWKSServer(t, map[string]*rsa.PrivateKey{"k1": key})
+	a, err := NewJWTAuthenticator(context.Background(), testJWTConfig{jwksURL: srv.URL})
+	if err != nil {
+		t.Fatalf("failed to create authenticator: %v", err)
+	}
+	if n := srv.fetches.Load(); n != 1 {
+		t.Fatalf("%d fetches on start, want 1", n)
+	}
+	claims := validClaims()
+	delete(claims, "iss")
+	delete(claims, "aud")
+
+	rotated := newRSAKey(t)
+	srv.addKey("k2", rotated)
+	token := sign(t, jwt.SigningMethodRS256, "k2", rotated, claims)
+
+	// The set was just loaded, an unknown kid must not trigger a refetch.
+	if _, err := authenticate(a, token); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("error = %v, want %v", err, ErrInvalidToken)
+	}
+	if n := srv.fetches.Load(); n != 1 {
+		t.Fatalf("%d fetches after unknown kid, want 1", n)
+	}
+
+	// Once jwksMinRefresh has passed the set is refetched and picks up k2.
+	a.jwks.mu.Lock()
+	a.jwks.loaded = time.Now().Add(-2 * jwksMinRefresh)
+	a.jwks.mu.Unlock()
+	if _, err := authenticate(a, token); err != nil {
+		t.Fatalf("token of rotated key rejected: %v", err)
+	}
+	if n := srv.fetches.Load(); n != 2 {
+		t.Fatalf("%d fetches after rotation, want 2", n)
+	}
+
+	// Still unknown kids do not hammer the JWKS endpoint.
+	for i := 0; i < 5; i++ {
+		authenticate(a, sign(t, jwt.SigningMethodRS256, "k3", rotated, claims))
+	}
+	if n := srv.fetches.Load(); n != 2 {
+		t.Fatalf("%d fetches after repeated unknown kid, want 2", n)
+	}
+}
@@ -18,16 +18,150 @@ type (
 		ServerHTTP   `yaml:"server_http"`
 		DatabasePG   `yaml:"database"`
 		LoggerConfig `yaml:"logger"`
+		Renewal      `yaml:"renewal"`
+		Events       `yaml:"events"`
+		Webhooks     `yaml:"webhooks"`
+		Reminders    `yaml:"reminders"`
+		SMTP         `yaml:"smtp"`
+		Budgets      `yaml:"budgets"`
+		Subs         `yaml:"subs"`
+		Auth         `yaml:"auth"`
+		RateLimit    `yaml:"rate_limit"`
+		Tenancy      `yaml:"tenancy"`
+		Health       `yaml:"health"`
+		Metrics      `yaml:"metrics"`
+		Tracing      `yaml:"tracing"`
 	}
 
 	LoggerConfig struct {
-		LogLevel string `yaml:"log_level"`
-		LogOut   string `yaml:"log_out"`
+		LogLevel         string `yaml:"log_level"`
+		LogOut           string `yaml:"log_out"`
+		LogFormat        string `yaml:"log_format" env-default:"json_compact"`
+		LogMaxSize       int    `yaml:"log_max_size_mb" env-default:"100"`
+		LogMaxAge        int    `yaml:"log_max_age_days" env-default:"7"`
+		LogMaxBackups    int    `yaml:"log_max_backups" env-default:"5"`
+		LogCompress      bool   `yaml:"log_compress" env-default:"false"`
+		LogSyslogNetwork string `yaml:"log_syslog_network"`
+		LogSyslogAddress string `yaml:"log_syslog_address"`
+		LogSyslogTag     string `yaml:"log_syslog_tag" env-default:"sub_service"`
 	}
 
 	ServerHTTP struct {
-		Address     string        `yaml:"address"`
-		IdleTimeout time.Duration `yaml:"idle_timeout"`
+		Address        string        `yaml:"address"`
+		IdleTimeout    time.Duration `yaml:"idle_timeout"`
+		TrustedProxies []string      `yaml:"trusted_proxies"`
+	}
+
+	Renewal struct {
+		Interval  time.Duration `yaml:"interval" env-default:"1m"`
+		Window    time.Duration `yaml:"window" env-default:"24h"`
+		BatchSize int           `yaml:"batch_size" env-default:"100"`
+	}
+
+	Events struct {
+		Publisher   string        `yaml:"publisher" env-default:"stdout"`
+		Source      string        `yaml:"source" env-default:"/sub_service"`
+		FilePath    string        `yaml:"file_path"`
+		WebhookURL  string        `yaml:"webhook_url"`
+		Timeout     time.Duration `yaml:"timeout" env-default:"5s"`
+		Interval    time.Duration `yaml:"interval" env-default:"5s"`
+		BatchSize   int           `yaml:"batch_size" env-default:"100"`
+		MaxAttempts int           `yaml:"max_attempts" env-default:"10"`
+		BackoffBase time.Duration `yaml:"backoff_base" env-default:"10s"`
+		BackoffMax  time.Duration `yaml:"backoff_max" env-default:"1h"`
+	}
+
+	Webhooks struct {
+		Interval    time.Duration `yaml:"interval" env-default:"5s"`
+		BatchSize   int           `yaml:"batch_size" env-default:"50"`
+		Timeout     time.Duration `yaml:"timeout" env-default:"10s"`
+		MaxAttempts int           `yaml:"max_attempts" env-default:"8"`
+		BackoffBase time.Duration `yaml:"backoff_base" env-default:"30s"`
+		BackoffMax  time.Duration `yaml:"backoff_max" env-default:"6h"`
+	}
+
+	Reminders struct {
+		Interval    time.Duration `yaml:"interval" env-default:"1h"`
+		DaysBefore  int           `yaml:"days_before" env-default:"3"`
+		BatchSize   int           `yaml:"batch_size" env-default:"100"`
+		MaxAttempts int           `yaml:"max_attempts" env-default:"3"`
+		Notifiers   []string      `yaml:"notifiers" env-default:"log"`
+		WebhookURL  string        `yaml:"webhook_url"`
+		Timeout     time.Duration `yaml:"timeout" env-default:"5s"`
+	}
+
+	SMTP struct {
+		Host     string `yaml:"host"`
+		Port     string `yaml:"port" env-default:"587"`
+		Username string `yaml:"username"`
+		Password string `yaml:"password"`
+		From     string `yaml:"from"`
+	}
+
+	Budgets struct {
+		Interval      time.Duration `yaml:"interval" env-default:"1h"`
+		DirtyInterval time.Duration `yaml:"dirty_interval" env-default:"10s"`
+		BatchSize     int           `yaml:"batch_size" env-default:"100"`
+	}
+
+	Subs struct {
+		OverlapPolicy string `yaml:"overlap_policy" env-default:"warn"`
+	}
+
+	Auth struct {
+		Enabled     bool          `yaml:"enabled" env-default:"false"`
+		HS256Secret string        `yaml:"hs256_secret"`
+		JWKSFile    string        `yaml:"jwks_file"`
+		JWKSURL     string        `yaml:"jwks_url"`
+		JWKSRefresh time.Duration `yaml:"jwks_refresh" env-default:"10m"`
+		Issuer      string        `yaml:"issuer"`
+		Audience    string        `yaml:"audience"`
+		RolesClaim  string        `yaml:"roles_claim" env-default:"roles"`
+		TenantClaim string        `yaml:"tenant_claim" env-default:"tenant"`
+		APIKeys     bool          `yaml:"api_keys" env-default:"true"`
+		Exempt      []string      `yaml:"exempt" env-default:"/,/swagger/*,/healthz,/readyz,/metrics"`
+	}
+
+	Tenancy struct {
+		Enabled bool   `yaml:"enabled" env-default:"false"`
+		Header  string `yaml:"header" env-default:"X-Tenant-ID"`
+		RLS     bool   `yaml:"rls" env-default:"false"`
+	}
+
+	Health struct {
+		Timeout    time.Duration `yaml:"timeout" env-default:"2s"`
+		DrainDelay time.Duration `yaml:"drain_delay" env-default:"5s"`
+	}
+
+	Metrics struct {
+		Enabled       bool          `yaml:"enabled" env-default:"true"`
+		Path          string        `yaml:"path" env-default:"/metrics"`
+		ScrapeTimeout time.Duration `yaml:"scrape_timeout" env-default:"5s"`
+	}
+
+	Tracing struct {
+		Enabled     bool    `yaml:"enabled" env-default:"false"`
+		Exporter    string  `yaml:"exporter" env-default:"stdout"`
+		Endpoint    string  `yaml:"endpoint" env-default:"localhost:4318"`
+		Insecure    bool    `yaml:"insecure" env-default:"true"`
+		ServiceName string  `yaml:"service_name" env-default:"sub_service"`
+		SampleRatio float64 `yaml:"sample_ratio" env-default:"1"`
+	}
+
+	RateLimit struct {
+		Enabled  bool                      `yaml:"enabled" env-default:"false"`
+		Store    string                    `yaml:"store" env-default:"memory"`
+		Requests int                       `yaml:"requests" env-default:"600"`
+		Period   time.Duration             `yaml:"period" env-default:"1m"`
+		Routes   map[string]RateLimitRoute `yaml:"routes"`
+
+		AuthRequests int           `yaml:"auth_requests" env-default:"60"`
+		AuthPeriod   time.Duration `yaml:"auth_period" env-default:"1m"`
+	}
+
+	RateLimitRoute struct {
+		Requests int           `yaml:"requests"`
+		Period   time.Duration `yaml:"period"`
 	}
 
 	DatabasePG struct {
@@ -37,14 +171,26 @@ type (
 		Database string `yaml:"database_name"`
 		Username string `yaml:"username"`
 		Password string `yaml:"password"`
+
+		WorkerUsername string `yaml:"worker_username"`
+		WorkerPassword string `yaml:"worker_password"`
 	}
 
 	Config interface {
 		GetLogLevel() string
 		GetLogOut() string
+		GetLogFormat() string
+		GetLogMaxSize() int
+		GetLogMaxAge() int
+		GetLogMaxBackups() int
+		GetLogCompress() bool
+		GetLogSyslogNetwork() string
+		GetLogSyslogAddress() string
+		GetLogSyslogTag() string
 
 		GetAddress() string
 		GetIdleTime() time.Duration
+		GetTrustedProxies() []string
 
 		GetDBEnv() string
 		GetDBPort() string
@@ -52,6 +198,83 @@ type (
 		GetDBDatabase() string
 		GetDBUsername() string
 		GetDBPassword() string
+		GetDBWorkerUsername() string
+		GetDBWorkerPassword() string
+
+		GetRenewalInterval() time.Duration
+		GetRenewalWindow() time.Duration
+		GetRenewalBatchSize() int
+
+		GetEventsPublisher() string
+		GetEventsSource() string
+		GetEventsFilePath() string
+		GetEventsWebhookURL() string
+		GetEventsTimeout() time.Duration
+		GetEventsInterval() time.Duration
+		GetEventsBatchSize() int
+		GetEventsMaxAttempts() int
+		GetEventsBackoffBase() time.Duration
+		GetEventsBackoffMax() time.Duration
+
+		GetWebhooksInterval() time.Duration
+		GetWebhooksBatchSize() int
+		GetWebhooksTimeout() time.Duration
+		GetWebhooksMaxAttempts() int
+		GetWebhooksBackoffBase() time.Duration
+		GetWebhooksBackoffMax() time.Duration
+
+		GetRemindersInterval() time.Duration
+		GetRemindersDaysBefore() int
+		GetRemindersBatchSize() int
+		GetRemindersMaxAttempts() int
+		GetRemindersNotifiers() []string
+		GetRemindersWebhookURL() string
+		GetRemindersTimeout() time.Duration
+
+		GetSMTPHost() string
+		GetSMTPPort() string
+		GetSMTPUsername() string
+		GetSMTPPassword() string
+		GetSMTPFrom() string
+
+		GetBudgetsInterval() time.Duration
+		GetBudgetsDirtyInterval() time.Duration
+		GetBudgetsBatchSize() int
+
+		GetSubsOverlapPolicy() string
+
+		GetAuthEnabled() bool
+		GetAuthHS256Secret() string
+		GetAuthJWKSFile() string
+		GetAuthJWKSURL() string
+		GetAuthJWKSRefresh() time.Duration
+		GetAuthIssuer() string
+		GetAuthAudience() string
+		GetAuthRolesClaim() string
+		GetAuthTenantClaim() string
+		GetAuthAPIKeys() bool
+		GetAuthExempt() []string
+		GetTenancyEnabled() bool
+		GetTenancyHeader() string
+		GetTenancyRLS() bool
+		GetRateLimitEnabled() bool
+		GetRateLimitStore() string
+		GetRateLimitRequests() int
+		GetRateLimitPeriod() time.Duration
+		GetRateLimitRoutes() map[string]RateLimitRoute
+		GetRateLimitAuthRequests() int
+		GetRateLimitAuthPeriod() time.Duration
+		GetHealthTimeout() time.Duration
+		GetHealthDrainDelay() time.Duration
+		GetMetricsEnabled() bool
+		GetMetricsPath() string
+		GetMetricsScrapeTimeout() time.Duration
+		GetTracingEnabled() bool
+		GetTracingExporter() string
+		GetTracingEndpoint() string
+		GetTracingInsecure() bool
+		GetTracingServiceName() string
+		GetTracingSampleRatio() float64
 	}
 )
 
@@ -105,3 +328,315 @@ func (s *ServerConfig) GetLogLevel() string {
 func (s *ServerConfig) GetLogOut() string {
 	return s.LoggerConfig.LogOut
 }
+
+func (s *ServerConfig) GetLogFormat() string {
+	return s.LoggerConfig.LogFormat
+}
+
+func (s *ServerConfig) GetLogMaxSize() int {
+	return s.LoggerConfig.LogMaxSize
+}
+
+func (s *ServerConfig) GetLogMaxAge() int {
+	return s.LoggerConfig.LogMaxAge
+}
+
+func (s *ServerConfig) GetLogMaxBackups() int {
+	return s.LoggerConfig.LogMaxBackups
+}
+
+func (s *ServerConfig) GetLogCompress() bool {
+	return s.LoggerConfig.LogCompress
+}
+
+func (s *ServerConfig) GetLogSyslogNetwork() string {
+	return s.LoggerConfig.LogSyslogNetwork
+}
+
+func (s *ServerConfig) GetLogSyslogAddress() string {
+	return s.LoggerConfig.LogSyslogAddress
+}
+
+func (s *ServerConfig) GetLogSyslogTag() string {
+	return s.LoggerConfig.LogSyslogTag
+}
+
+func (s *ServerConfig) GetRenewalInterval() time.Duration {
+	return s.Renewal.Interval
+}
+
+func (s *ServerConfig) GetRenewalWindow() time.Duration {
+	return s.Renewal.Window
+}
+
+func (s *ServerConfig) GetRenewalBatchSize() int {
+	return s.Renewal.BatchSize
+}
+
+func (s *ServerConfig) GetEventsPublisher() string {
+	return s.Events.Publisher
+}
+
+func (s *ServerConfig) GetEventsSource() string {
+	return s.Events.Source
+}
+
+func (s *ServerConfig) GetEventsFilePath() string {
+	return s.Events.FilePath
+}
+
+func (s *ServerConfig) GetEventsWebhookURL() string {
+	return s.Events.WebhookURL
+}
+
+func (s *ServerConfig) GetEventsTimeout() time.Duration {
+	return s.Events.Timeout
+}
+
+func (s *ServerConfig) GetEventsInterval() time.Duration {
+	return s.Events.Interval
+}
+
+func (s *ServerConfig) GetEventsBatchSize() int {
+	return s.Events.BatchSize
+}
+
+func (s *ServerConfig) GetEventsMaxAttempts() int {
+	return s.Events.MaxAttempts
+}
+
+func (s *ServerConfig) GetEventsBackoffBase() time.Duration {
+	return s.Events.BackoffBase
+}
+
+func (s *ServerConfig) GetEventsBackoffMax() time.Duration {
+	return s.Events.BackoffMax
+}
+
+func (s *ServerConfig) GetWebhooksInterval() time.Duration {
+	return s.Webhooks.Interval
+}
+
+func (s *ServerConfig) GetWebhooksBatchSize() int {
+	return s.Webhooks.BatchSize
+}
+
+func (s *ServerConfig) GetWebhooksTimeout() time.Duration {
+	return s.Webhooks.Timeout
+}
+
+func (s *ServerConfig) GetWebhooksMaxAttempts() int {
+	return s.Webhooks.MaxAttempts
+}
+
+func (s *ServerConfig) GetWebhooksBackoffBase() time.Duration {
+	return s.Webhooks.BackoffBase
+}
+
+func (s *ServerConfig) GetWebhooksBackoffMax() time.Duration {
+	return s.Webhooks.BackoffMax
+}
+
+func (s *ServerConfig) GetRemindersInterval() time.Duration {
+	return s.Reminders.Interval
+}
+
+func (s *ServerConfig) GetRemindersDaysBefore() int {
+	return s.Reminders.DaysBefore
+}
+
+func (s *ServerConfig) GetRemindersBatchSize() int {
+	return s.Reminders.BatchSize
+}
+
+func (s *ServerConfig) GetRemindersMaxAttempts() int {
+	return s.Reminders.MaxAttempts
+}
+
+func (s *ServerConfig) GetRemindersNotifiers() []string {
+	return s.Reminders.Notifiers
+}
+
+func (s *ServerConfig) GetRemindersWebhookURL() string {
+	return s.Reminders.WebhookURL
+}
+
+func (s *ServerConfig) GetRemindersTimeout() time.Duration {
+	return s.Reminders.Timeout
+}
+
+func (s *ServerConfig) GetSMTPHost() string {
+	return s.SMTP.Host
+}
+
+func (s *ServerConfig) GetSMTPPort() string {
+	return s.SMTP.Port
+}
+
+func (s *ServerConfig) GetSMTPUsername() string {
+	return s.SMTP.Username
+}
+
+func (s *ServerConfig) GetSMTPPassword() string {
+	return s.SMTP.Password
+}
+
+func (s *ServerConfig) GetSMTPFrom() string {
+	return s.SMTP.From
+}
+
+func (s *ServerConfig) GetBudgetsInterval() time.Duration {
+	return s.Budgets.Interval
+}
+
+func (s *ServerConfig) GetBudgetsDirtyInterval() time.Duration {
+	return s.Budgets.DirtyInterval
+}
+
+func (s *ServerConfig) GetBudgetsBatchSize() int {
+	return s.Budgets.BatchSize
+}
+
+func (s *ServerConfig) GetSubsOverlapPolicy() string {
+	return s.Subs.OverlapPolicy
+}
+
+func (s *ServerConfig) GetAuthEnabled() bool {
+	return s.Auth.Enabled
+}
+
+func (s *ServerConfig) GetAuthHS256Secret() string {
+	return s.Auth.HS256Secret
+}
+
+func (s *ServerConfig) GetAuthJWKSFile() string {
+	return s.Auth.JWKSFile
+}
+
+func (s *ServerConfig) GetAuthJWKSURL() string {
+	return s.Auth.JWKSURL
+}
+
+func (s *ServerConfig) GetAuthJWKSRefresh() time.Duration {
+	return s.Auth.JWKSRefresh
+}
+
+func (s *ServerConfig) GetAuthIssuer() string {
+	return s.Auth.Issuer
+}
+
+func (s *ServerConfig) GetAuthAudience() string {
+	return s.Auth.Audience
+}
+
+func (s *ServerConfig) GetAuthRolesClaim() string {
+	return s.Auth.RolesClaim
+}
+
+func (s *ServerConfig) GetAuthExempt() []string {
+	return s.Auth.Exempt
+}
+
+func (s *ServerConfig) GetAuthAPIKeys() bool {
+	return s.Auth.APIKeys
+}
+
+func (s *ServerConfig) GetRateLimitEnabled() bool {
+	return s.RateLimit.Enabled
+}
+
+func (s *ServerConfig) GetRateLimitStore() string {
+	return s.RateLimit.Store
+}
+
+func (s *ServerConfig) GetRateLimitRequests() int {
+	return s.RateLimit.Requests
+}
+
+func (s *ServerConfig) GetRateLimitPeriod() time.Duration {
+	return s.RateLimit.Period
+}
+
+func (s *ServerConfig) GetRateLimitRoutes() map[string]RateLimitRoute {
+	return s.RateLimit.Routes
+}
+
+func (s *ServerConfig) GetAuthTenantClaim() string {
+	return s.Auth.TenantClaim
+}
+
+func (s *ServerConfig) GetTenancyEnabled() bool {
+	return s.Tenancy.Enabled
+}
+
+func (s *ServerConfig) GetTenancyHeader() string {
+	return s.Tenancy.Header
+}
+
+func (s *ServerConfig) GetTenancyRLS() bool {
+	return s.Tenancy.RLS
+}
+
+func (s *ServerConfig) GetHealthTimeout() time.Duration {
+	return s.Health.Timeout
+}
+
+func (s *ServerConfig) GetHealthDrainDelay() time.Duration {
+	return s.Health.DrainDelay
+}
+
+func (s *ServerConfig) GetMetricsEnabled() bool {
+	return s.Metrics.Enabled
+}
+
+func (s *ServerConfig) GetMetricsPath() string {
+	return s.Metrics.Path
+}
+
+func (s *ServerConfig) GetMetricsScrapeTimeout() time.Duration {
+	return s.Metrics.ScrapeTimeout
+}
+
+func (s *ServerConfig) GetTracingEnabled() bool {
+	return s.Tracing.Enabled
+}
+
+func (s *ServerConfig) GetTracingExporter() string {
+	return s.Tracing.Exporter
+}
+
+func (s *ServerConfig) GetTracingEndpoint() string {
+	return s.Tracing.Endpoint
+}
+
+func (s *ServerConfig) GetTracingInsecure() bool {
+	return s.Tracing.Insecure
+}
+
+func (s *ServerConfig) GetTracingServiceName() string {
+	return s.Tracing.ServiceName
+}
+
+func (s *ServerConfig) GetTracingSampleRatio() float64 {
+	return s.Tracing.SampleRatio
+}
+
+func (s *ServerConfig) GetTrustedProxies() []string {
+	return s.ServerHTTP.TrustedProxies
+}
+
+func (s *ServerConfig) GetRateLimitAuthRequests() int {
+	return s.RateLimit.AuthRequests
+}
+
+func (s *ServerConfig) GetRateLimitAuthPeriod() time.Duration {
+	return s.RateLimit.AuthPeriod
+}
+
+func (s *ServerConfig) GetDBWorkerUsername() string {
+	return s.DatabasePG.WorkerUsername
+}
+
+func (s *ServerConfig) GetDBWorkerPassword() string {
+	return s.DatabasePG.WorkerPassword
+}
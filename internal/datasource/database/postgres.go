@@ -3,6 +3,8 @@ package database
 import (
 	"context"
 	"fmt"
+	"service/internal/tenant"
+	"service/internal/tracing"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 )
@@ -14,17 +16,61 @@ type Config interface {
 	GetDBDatabase() string
 	GetDBUsername() string
 	GetDBPassword() string
+	GetDBWorkerUsername() string
+	GetDBWorkerPassword() string
+	GetTenancyRLS() bool
+	GetTracingEnabled() bool
 }
 
-func ConnectDB(ctx context.Context, cfg Config) (pool *pgxpool.Pool, err error) {
+// ConnectDB connects as the service user. With tenancy.rls enabled its
+// connections are bound to the tenant of the request.
+func ConnectDB(ctx context.Context, cfg Config) (*pgxpool.Pool, error) {
+	return connect(ctx, cfg, cfg.GetDBUsername(), cfg.GetDBPassword(), cfg.GetTenancyRLS())
+}
+
+// ConnectWorkerDB connects as the worker user for background jobs, API key
+// lookups and metrics, which work across tenants. The role must bypass
+// row-level security, otherwise the jobs would silently see no rows.
+func ConnectWorkerDB(ctx context.Context, cfg Config) (*pgxpool.Pool, error) {
+	if cfg.GetDBWorkerUsername() == "" {
+		return nil, fmt.Errorf("tenancy.rls requires database.worker_username")
+	}
+	pool, err := connect(ctx, cfg, cfg.GetDBWorkerUsername(), cfg.GetDBWorkerPassword(), false)
+	if err != nil {
+		return nil, err
+	}
+	var bypass bool
+	query := `SELECT rolbypassrls OR rolsuper FROM pg_roles WHERE rolname = current_user`
+	if err := pool.QueryRow(ctx, query).Scan(&bypass); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("database role check failed: %v", err)
+	}
+	if !bypass {
+		pool.Close()
+		return nil, fmt.Errorf("database user %s must have BYPASSRLS", cfg.GetDBWorkerUsername())
+	}
+	return pool, nil
+}
+
+func connect(ctx context.Context, cfg Config, username, password string, rls bool) (pool *pgxpool.Pool, err error) {
 	dns := fmt.Sprintf("postgres://%s:%s@%s:%s/%s",
-		cfg.GetDBUsername(),
-		cfg.GetDBPassword(),
+		username,
+		password,
 		cfg.GetDBHost(),
 		cfg.GetDBPort(),
 		cfg.GetDBDatabase(),
 	)
-	pool, err = pgxpool.New(ctx, dns)
+	poolCfg, err := pgxpool.ParseConfig(dns)
+	if err != nil {
+		return nil, fmt.Errorf("database connection failed: %v", err)
+	}
+	if rls {
+		tenant.BindPool(poolCfg)
+	}
+	if cfg.GetTracingEnabled() {
+		poolCfg.ConnConfig.Tracer = tracing.NewQueryTracer()
+	}
+	pool, err = pgxpool.NewWithConfig(ctx, poolCfg)
 	if err != nil {
 		return nil, fmt.Errorf("database connection failed: %v", err)
 	}
@@ -32,5 +78,4 @@ func ConnectDB(ctx context.Context, cfg Config) (pool *pgxpool.Pool, err error)
 		return nil, fmt.Errorf("database ping failed: %v", err)
 	}
 	return pool, nil
-	// return nil, nil
 }
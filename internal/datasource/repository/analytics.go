@@ -0,0 +1,199 @@
+package repository
+
+import (
+	"fmt"
+	"service/internal/dto"
+
+	"github.com/labstack/echo/v4"
+)
+
+var spendGroups = map[string]string{
+	"":         "''",
+	"service":  "c.service_name",
+	"user":     "c.user_id::TEXT",
+	"category": "c.category",
+}
+
+func (r *Repository) GetSpendByMonth(ctx echo.Context, data dto.GetSpendAnalyticsToDb) ([]dto.SpendByMonthFromDb, error) {
+	group, ok := spendGroups[data.GroupBy]
+	if !ok {
+		return nil, fmt.Errorf("invalid group_by: %s", data.GroupBy)
+	}
+	query := `WITH costs AS (` + subMonthlyCosts + `)
+	SELECT
+	m.month,
+	COALESCE(` + group + `, '') AS spend_group,
+	ROUND(COALESCE(SUM(c.cost), 0))::BIGINT
+	FROM generate_series(
+	date_trunc('month', @start_date::TIMESTAMPTZ),
+	@end_date::TIMESTAMPTZ,
+	INTERVAL '1 month') AS m(month)
+	LEFT JOIN costs c ON c.month = m.month
+	AND (@service_name = '' OR c.service_name = @service_name)
+	AND (@user_id = '' OR c.user_id::TEXT = @user_id)
+	AND (@category = '' OR c.category = @category)
+	GROUP BY m.month, spend_group
+	ORDER BY m.month, spend_group`
+	args, err := StructToNamedArgs(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+	args["tenant_id"] = tenantOf(ctx)
+	rows, err := r.Client.Query(ctx.Request().Context(), query, args)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+	defer rows.Close()
+	var out []dto.SpendByMonthFromDb
+	for rows.Next() {
+		var data dto.SpendByMonthFromDb
+		if err := rows.Scan(
+			&data.Month,
+			&data.Group,
+			&data.Total); err != nil {
+			return nil, fmt.Errorf("%w", err)
+		}
+		out = append(out, data)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+	return out, nil
+}
+
+var topServicesOrder = map[string]string{
+	"":        "active_count DESC, revenue DESC",
+	"count":   "active_count DESC, revenue DESC",
+	"revenue": "revenue DESC, active_count DESC",
+}
+
+// GetTopServices counts the trial and active subs whose period overlaps the
+// requested months.
+func (r *Repository) GetTopServices(ctx echo.Context, data dto.GetTopServicesToDb) ([]dto.TopServiceFromDb, error) {
+	order, ok := topServicesOrder[data.OrderBy]
+	if !ok {
+		return nil, fmt.Errorf("invalid order_by: %s", data.OrderBy)
+	}
+	query := `WITH costs AS (` + subMonthlyCosts + `), revenue AS (
+	SELECT
+	service_name,
+	SUM(cost) AS revenue
+	FROM costs
+	GROUP BY service_name
+	), active AS (
+	SELECT
+	service_name,
+	COUNT(*) AS active_count
+	FROM subs
+	WHERE tenant_id = @tenant_id AND status IN ('trial', 'active')
+	AND start_date < date_trunc('month', @end_date::TIMESTAMPTZ) + INTERVAL '1 month'
+	AND end_date > date_trunc('month', @start_date::TIMESTAMPTZ)
+	GROUP BY service_name
+	)
+	SELECT
+	COALESCE(a.service_name, r.service_name) AS service_name,
+	COALESCE(a.active_count, 0) AS active_count,
+	ROUND(COALESCE(r.revenue, 0))::BIGINT AS revenue
+	FROM active a
+	FULL JOIN revenue r ON r.service_name = a.service_name
+	ORDER BY ` + order + `, service_name
+	LIMIT @limit`
+	args, err := StructToNamedArgs(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+	args["tenant_id"] = tenantOf(ctx)
+	rows, err := r.Client.Query(ctx.Request().Context(), query, args)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+	defer rows.Close()
+	var out []dto.TopServiceFromDb
+	for rows.Next() {
+		var data dto.TopServiceFromDb
+		if err := rows.Scan(
+			&data.ServiceName,
+			&data.ActiveCount,
+			&data.Revenue); err != nil {
+			return nil, fmt.Errorf("%w", err)
+		}
+		out = append(out, data)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+	return out, nil
+}
+
+// GetChurnByMonth counts as active at the start of a month the subs that
+// were in trial or active at that moment according to sub_status_history.
+// A sub ends in the month it was cancelled or expired, taken from the
+// history; subs expired before the history existed fall back to end_date.
+func (r *Repository) GetChurnByMonth(ctx echo.Context, data dto.GetChurnToDb) ([]dto.ChurnByMonthFromDb, error) {
+	query := `WITH ended AS (
+	SELECT COALESCE((
+	SELECT h.changed_at
+	FROM sub_status_history h
+	WHERE h.sub_id = s.id AND h.to_status = s.status
+	ORDER BY h.changed_at DESC, h.id DESC
+	LIMIT 1
+	), s.end_date) AS ended_at
+	FROM subs s
+	WHERE s.tenant_id = @tenant_id AND s.status IN ('cancelled', 'expired')
+	)
+	SELECT
+	m.month,
+	(SELECT COUNT(*)
+	FROM subs s
+	WHERE s.tenant_id = @tenant_id AND date_trunc('month', s.start_date) = m.month) AS new_subs,
+	(SELECT COUNT(*)
+	FROM ended e
+	WHERE e.ended_at >= m.month AND e.ended_at < m.month + INTERVAL '1 month') AS ended_subs,
+	(SELECT COUNT(*)
+	FROM subs s
+	WHERE s.tenant_id = @tenant_id AND s.start_date < m.month AND s.end_date >= m.month
+	AND COALESCE((
+	SELECT h.to_status
+	FROM sub_status_history h
+	WHERE h.sub_id = s.id AND h.changed_at <= m.month
+	ORDER BY h.changed_at DESC, h.id DESC
+	LIMIT 1
+	), (
+	SELECT h.from_status
+	FROM sub_status_history h
+	WHERE h.sub_id = s.id
+	ORDER BY h.changed_at, h.id
+	LIMIT 1
+	), s.status) IN ('trial', 'active')) AS active_at_start
+	FROM generate_series(
+	date_trunc('month', @start_date::TIMESTAMPTZ),
+	@end_date::TIMESTAMPTZ,
+	INTERVAL '1 month') AS m(month)
+	ORDER BY m.month`
+	args, err := StructToNamedArgs(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+	args["tenant_id"] = tenantOf(ctx)
+	rows, err := r.Client.Query(ctx.Request().Context(), query, args)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+	defer rows.Close()
+	var out []dto.ChurnByMonthFromDb
+	for rows.Next() {
+		var data dto.ChurnByMonthFromDb
+		if err := rows.Scan(
+			&data.Month,
+			&data.NewSubs,
+			&data.EndedSubs,
+			&data.ActiveAtStart); err != nil {
+			return nil, fmt.Errorf("%w", err)
+		}
+		out = append(out, data)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+	return out, nil
+}
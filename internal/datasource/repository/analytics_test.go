@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"service/internal/dto"
+	"service/internal/tenant"
+	"testing"
+	"time"
+)
+
+func TestChurnCountsCancellationMonth(t *testing.T) {
+	pool := newTestPool(t)
+	repo := NewDatabase(pool)
+	ctx := newTestContext(tenant.Default)
+	userId := addTestUser(t, pool, tenant.Default)
+
+	now := time.Now().UTC()
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if err := repo.AddNewSubs(ctx, dto.AddSubToDb{
+		ServiceName:   "Yandex Plus",
+		Price:         400,
+		UserId:        userId,
+		StartDate:     month.AddDate(0, -2, 0),
+		EndDate:       month.AddDate(0, 2, 0),
+		BillingPeriod: "month",
+		Status:        "active",
+	}); err != nil {
+		t.Fatalf("failed to add sub: %v", err)
+	}
+	var id int
+	if err := pool.QueryRow(context.Background(), `SELECT id FROM subs WHERE user_id = $1`, userId).Scan(&id); err != nil {
+		t.Fatalf("failed to find sub: %v", err)
+	}
+	if err := repo.UpdateSubStatus(ctx, dto.UpdateSubStatusToDb{Id: id, FromStatus: "active", ToStatus: "cancelled"}); err != nil {
+		t.Fatalf("failed to cancel sub: %v", err)
+	}
+
+	churn, err := repo.GetChurnByMonth(ctx, dto.GetChurnToDb{StartDate: month.AddDate(0, -1, 0), EndDate: month.AddDate(0, 2, 0)})
+	if err != nil {
+		t.Fatalf("failed to get churn: %v", err)
+	}
+	if len(churn) != 4 {
+		t.Fatalf("churn has %d months, want 4", len(churn))
+	}
+	// Cancelled this month although the paid period runs for two more.
+	want := map[time.Time]int{month: 1}
+	for _, row := range churn {
+		if row.EndedSubs != want[row.Month.UTC()] {
+			t.Fatalf("month %s: %d ended subs, want %d", row.Month.Format("01-2006"), row.EndedSubs, want[row.Month.UTC()])
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"service/internal/dto"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/labstack/echo/v4"
+)
+
+// ErrAPIKeyNotFound is returned by UseAPIKey for unknown, revoked and expired
+// keys alike.
+var ErrAPIKeyNotFound = errors.New("api key not found")
+
+const apiKeyColumns = `id,
+	name,
+	prefix,
+	user_id,
+	scopes,
+	expires_at,
+	revoked_at,
+	last_used_at,
+	created_at`
+
+func scanAPIKey(row pgx.Row) (dto.GetAPIKeyFromDb, error) {
+	var out dto.GetAPIKeyFromDb
+	err := row.Scan(
+		&out.Id,
+		&out.Name,
+		&out.Prefix,
+		&out.UserId,
+		&out.Scopes,
+		&out.ExpiresAt,
+		&out.RevokedAt,
+		&out.LastUsedAt,
+		&out.CreatedAt)
+	return out, err
+}
+
+func (r *Repository) AddAPIKey(ctx echo.Context, data dto.AddAPIKeyToDb) (dto.GetAPIKeyFromDb, error) {
+	query := `INSERT INTO api_keys (
+	name,
+	prefix,
+	key_hash,
+	user_id,
+	scopes,
+	expires_at,
+	tenant_id) VALUES (
+	@name,
+	@prefix,
+	@key_hash,
+	@user_id,
+	@scopes,
+	@expires_at,
+	@tenant_id)
+	RETURNING ` + apiKeyColumns
+
+	args, err := StructToNamedArgs(data)
+	if err != nil {
+		return dto.GetAPIKeyFromDb{}, fmt.Errorf("%w", err)
+	}
+	args["tenant_id"] = tenantOf(ctx)
+	out, err := scanAPIKey(r.Client.QueryRow(ctx.Request().Context(), query, args))
+	if err != nil {
+		if isPgError(err, pgForeignKeyViolation) {
+			return dto.GetAPIKeyFromDb{}, fmt.Errorf("user with id %s not found", *data.UserId)
+		}
+		return dto.GetAPIKeyFromDb{}, fmt.Errorf("%w", err)
+	}
+	return out, nil
+}
+
+func (r *Repository) GetListAPIKeys(ctx echo.Context) ([]dto.GetAPIKeyFromDb, error) {
+	query := `SELECT ` + apiKeyColumns + `
+	FROM api_keys
+	WHERE tenant_id = $1
+	ORDER BY id`
+	rows, err := r.Client.Query(ctx.Request().Context(), query, tenantOf(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+	defer rows.Close()
+	var out []dto.GetAPIKeyFromDb
+	for rows.Next() {
+		data, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%w", err)
+		}
+		out = append(out, data)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+	return out, nil
+}
+
+func (r *Repository) RevokeAPIKey(ctx echo.Context, data dto.GetAPIKeyFromWeb) error {
+	query := `UPDATE api_keys SET
+	revoked_at = COALESCE(revoked_at, now())
+	WHERE id = $1 AND tenant_id = $2`
+	res, err := r.Client.Exec(ctx.Request().Context(), query, data.Id, tenantOf(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to revoke api key: %w", err)
+	}
+	if res.RowsAffected() == 0 {
+		return fmt.Errorf("api key with id %d not found", data.Id)
+	}
+	return nil
+}
+
+// RotateAPIKey replaces the hash of an active key, so the previous secret
+// stops working immediately while name, owner, scopes and expiry are kept.
+func (r *Repository) RotateAPIKey(ctx echo.Context, data dto.RotateAPIKeyToDb) (dto.GetAPIKeyFromDb, error) {
+	query := `UPDATE api_keys SET
+	prefix = @prefix,
+	key_hash = @key_hash,
+	last_used_at = NULL
+	WHERE id = @id AND tenant_id = @tenant_id AND revoked_at IS NULL
+	RETURNING ` + apiKeyColumns
+
+	args, err := StructToNamedArgs(data)
+	if err != nil {
+		return dto.GetAPIKeyFromDb{}, fmt.Errorf("%w", err)
+	}
+	args["tenant_id"] = tenantOf(ctx)
+	out, err := scanAPIKey(r.Client.QueryRow(ctx.Request().Context(), query, args))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return dto.GetAPIKeyFromDb{}, fmt.Errorf("active api key with id %d not found", data.Id)
+		}
+		return dto.GetAPIKeyFromDb{}, fmt.Errorf("%w", err)
+	}
+	return out, nil
+}
+
+// UseAPIKey looks up a usable key by its hash and records the time it was
+// last used. Keys are looked up across tenants, the key decides the tenant.
+// last_used_at is refreshed at most once a minute so that busy keys do not
+// turn every request into a write.
+func (r *Repository) UseAPIKey(ctx context.Context, keyHash string) (dto.APIKeyAuthFromDb, error) {
+	query := `SELECT id, tenant_id, user_id, scopes
+	FROM api_keys
+	WHERE key_hash = $1 AND revoked_at IS NULL
+	AND (expires_at IS NULL OR expires_at > now())`
+	var out dto.APIKeyAuthFromDb
+	if err := r.Client.QueryRow(ctx, query, keyHash).Scan(
+		&out.Id,
+		&out.TenantId,
+		&out.UserId,
+		&out.Scopes); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return dto.APIKeyAuthFromDb{}, ErrAPIKeyNotFound
+		}
+		return dto.APIKeyAuthFromDb{}, fmt.Errorf("%w", err)
+	}
+
+	query = `UPDATE api_keys SET
+	last_used_at = now()
+	WHERE id = $1
+	AND (last_used_at IS NULL OR last_used_at < now() - interval '1 minute')`
+	if _, err := r.Client.Exec(ctx, query, out.Id); err != nil {
+		return dto.APIKeyAuthFromDb{}, fmt.Errorf("%w", err)
+	}
+	return out, nil
+}
@@ -0,0 +1,322 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"service/internal/dto"
+	"service/internal/tenant"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/labstack/echo/v4"
+)
+
+// budgetSpend projects the spend of every budget of @user_id (all users when
+// empty) in @tenant_id (all tenants when empty) for the month of @start_date
+// using subMonthlyCosts. A budget with an empty category covers all
+// categories of the user.
+const budgetSpend = `costs AS (` + subMonthlyCosts + `), budget_spend AS (
+	SELECT
+	b.id,
+	b.tenant_id,
+	b.user_id,
+	b.category,
+	b.amount,
+	b.created_at,
+	ROUND(COALESCE(SUM(c.cost), 0))::BIGINT AS spend
+	FROM budgets b
+	LEFT JOIN costs c ON c.user_id = b.user_id AND (b.category = '' OR c.category = b.category)
+	WHERE (@tenant_id = '' OR b.tenant_id = @tenant_id)
+	AND (@user_id = '' OR b.user_id::TEXT = @user_id)
+	GROUP BY b.id
+	)`
+
+func budgetArgs(ctx context.Context, userId string) pgx.NamedArgs {
+	now := time.Now().UTC()
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return pgx.NamedArgs{
+		"start_date": month,
+		"end_date":   month,
+		"user_id":    userId,
+		"tenant_id":  tenant.FromContext(ctx),
+		"event_type": EventBudgetExceeded,
+	}
+}
+
+// evaluateBudgets records an alert and a budget.exceeded event for every
+// budget whose projected spend for the current month is over its amount.
+// Alerts are unique per budget, month and amount, so each overspend is
+// reported once.
+func evaluateBudgets(ctx context.Context, tx pgx.Tx, userId string) (int, error) {
+	query := `WITH ` + budgetSpend + `, alerts AS (
+	INSERT INTO budget_alerts (budget_id, month, amount, spend)
+	SELECT id, @start_date::TIMESTAMPTZ, amount, spend
+	FROM budget_spend
+	WHERE spend > amount
+	ON CONFLICT (budget_id, month, amount) DO NOTHING
+	RETURNING budget_id, month, amount, spend
+	)
+	INSERT INTO outbox (tenant_id, event_type, subject, data)
+	SELECT s.tenant_id, @event_type, 'budgets/' || a.budget_id, jsonb_build_object(
+	'budget_id', a.budget_id,
+	'user_id', s.user_id,
+	'category', s.category,
+	'month', a.month,
+	'amount', a.amount,
+	'spend', a.spend)
+	FROM alerts a
+	JOIN budget_spend s ON s.id = a.budget_id`
+	res, err := tx.Exec(ctx, query, budgetArgs(ctx, userId))
+	if err != nil {
+		return 0, fmt.Errorf("failed to evaluate budgets: %w", err)
+	}
+	return int(res.RowsAffected()), nil
+}
+
+// markBudgetsDirty queues the budgets of userId for evaluation by the
+// budgets worker, so writes do not pay for the spend projection. Users
+// without budgets are not queued.
+func markBudgetsDirty(ctx context.Context, tx pgx.Tx, userId string) error {
+	query := `INSERT INTO budget_dirty_users (tenant_id, user_id)
+	SELECT tenant_id, user_id FROM budgets
+	WHERE user_id = $1::UUID
+	LIMIT 1
+	ON CONFLICT (user_id) DO NOTHING`
+	if _, err := tx.Exec(ctx, query, userId); err != nil {
+		return fmt.Errorf("failed to mark budgets for evaluation: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) AddBudget(ctx echo.Context, data dto.AddBudgetToDb) (dto.GetBudgetFromDb, error) {
+	query := `INSERT INTO budgets (
+	user_id,
+	category,
+	amount,
+	tenant_id) VALUES (
+	@user_id,
+	@category,
+	@amount,
+	@tenant_id)
+	RETURNING id, user_id, category, amount, created_at`
+
+	args, err := StructToNamedArgs(data)
+	if err != nil {
+		return dto.GetBudgetFromDb{}, fmt.Errorf("%w", err)
+	}
+	args["tenant_id"] = tenantOf(ctx)
+	var out dto.GetBudgetFromDb
+	err = r.inTx(ctx.Request().Context(), func(tx pgx.Tx) error {
+		if err := tx.QueryRow(ctx.Request().Context(), query, args).Scan(
+			&out.Id,
+			&out.UserId,
+			&out.Category,
+			&out.Amount,
+			&out.CreatedAt); err != nil {
+			if isPgError(err, pgUniqueViolation) {
+				return fmt.Errorf("budget for user %s and category %q already exists", data.UserId, data.Category)
+			}
+			if isPgError(err, pgForeignKeyViolation) {
+				return fmt.Errorf("user with id %s not found", data.UserId)
+			}
+			return fmt.Errorf("%w", err)
+		}
+		return markBudgetsDirty(ctx.Request().Context(), tx, data.UserId)
+	})
+	if err != nil {
+		return dto.GetBudgetFromDb{}, err
+	}
+	return out, nil
+}
+
+func (r *Repository) GetBudgetById(ctx echo.Context, data dto.GetBudgetFromWeb) (dto.GetBudgetFromDb, error) {
+	query := `SELECT
+	id,
+	user_id,
+	category,
+	amount,
+	created_at
+	FROM budgets
+	WHERE id = $1 AND tenant_id = $2`
+	var out dto.GetBudgetFromDb
+	if err := r.Client.QueryRow(ctx.Request().Context(), query, data.Id, tenantOf(ctx)).Scan(
+		&out.Id,
+		&out.UserId,
+		&out.Category,
+		&out.Amount,
+		&out.CreatedAt); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return dto.GetBudgetFromDb{}, fmt.Errorf("budget with id %d not found", data.Id)
+		}
+		return dto.GetBudgetFromDb{}, fmt.Errorf("%w", err)
+	}
+	return out, nil
+}
+
+func (r *Repository) GetBudgetsByUser(ctx echo.Context, data dto.GetUserFromWeb) ([]dto.GetBudgetFromDb, error) {
+	query := `WITH ` + budgetSpend + `
+	SELECT
+	id,
+	user_id,
+	category,
+	amount,
+	spend,
+	spend > amount,
+	created_at
+	FROM budget_spend
+	ORDER BY category`
+	rows, err := r.Client.Query(ctx.Request().Context(), query, budgetArgs(ctx.Request().Context(), data.Id))
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+	defer rows.Close()
+	var out []dto.GetBudgetFromDb
+	for rows.Next() {
+		var data dto.GetBudgetFromDb
+		if err := rows.Scan(
+			&data.Id,
+			&data.UserId,
+			&data.Category,
+			&data.Amount,
+			&data.Spend,
+			&data.Exceeded,
+			&data.CreatedAt); err != nil {
+			return nil, fmt.Errorf("%w", err)
+		}
+		out = append(out, data)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+	return out, nil
+}
+
+func (r *Repository) UpdateBudgetById(ctx echo.Context, data dto.UpdateBudgetToDb) error {
+	query := `UPDATE budgets SET
+	amount = $1
+	WHERE id = $2 AND tenant_id = $3
+	RETURNING user_id`
+	return r.inTx(ctx.Request().Context(), func(tx pgx.Tx) error {
+		var userId string
+		if err := tx.QueryRow(ctx.Request().Context(), query, data.Amount, data.Id, tenantOf(ctx)).Scan(&userId); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return fmt.Errorf("budget with id %d not found", data.Id)
+			}
+			return fmt.Errorf("failed to update budget: %w", err)
+		}
+		return markBudgetsDirty(ctx.Request().Context(), tx, userId)
+	})
+}
+
+// DeleteBudget needs neither a transaction nor markBudgetsDirty: the alerts
+// of the budget are deleted with it and the other budgets of the user do not
+// depend on it.
+func (r *Repository) DeleteBudget(ctx echo.Context, data dto.GetBudgetFromWeb) error {
+	res, err := r.Client.Exec(ctx.Request().Context(), `DELETE FROM budgets WHERE id = $1 AND tenant_id = $2`, data.Id, tenantOf(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to delete budget: %w", err)
+	}
+	if res.RowsAffected() == 0 {
+		return fmt.Errorf("budget with id %d not found", data.Id)
+	}
+	return nil
+}
+
+func (r *Repository) GetBudgetAlerts(ctx echo.Context, data dto.GetUserFromWeb) ([]dto.BudgetAlertFromDb, error) {
+	query := `SELECT
+	a.id,
+	a.budget_id,
+	b.category,
+	a.month,
+	a.amount,
+	a.spend,
+	a.created_at
+	FROM budget_alerts a
+	JOIN budgets b ON b.id = a.budget_id
+	WHERE b.user_id = $1 AND b.tenant_id = $2
+	ORDER BY a.created_at DESC`
+	rows, err := r.Client.Query(ctx.Request().Context(), query, data.Id, tenantOf(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+	defer rows.Close()
+	var out []dto.BudgetAlertFromDb
+	for rows.Next() {
+		var data dto.BudgetAlertFromDb
+		if err := rows.Scan(
+			&data.Id,
+			&data.BudgetId,
+			&data.Category,
+			&data.Month,
+			&data.Amount,
+			&data.Spend,
+			&data.CreatedAt); err != nil {
+			return nil, fmt.Errorf("%w", err)
+		}
+		out = append(out, data)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+	return out, nil
+}
+
+func (r *Repository) EvaluateBudgets(ctx context.Context) (int, error) {
+	var alerts int
+	err := r.inTx(ctx, func(tx pgx.Tx) error {
+		var err error
+		alerts, err = evaluateBudgets(ctx, tx, "")
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return alerts, nil
+}
+
+// EvaluateDirtyBudgets evaluates the budgets of up to limit users queued by
+// markBudgetsDirty and returns the number of users. The queue entries are
+// removed in the same transaction, so they survive a failed evaluation.
+func (r *Repository) EvaluateDirtyBudgets(ctx context.Context, limit int) (int, error) {
+	var users int
+	err := r.inTx(ctx, func(tx pgx.Tx) error {
+		query := `DELETE FROM budget_dirty_users
+	WHERE user_id IN (
+	SELECT user_id
+	FROM budget_dirty_users
+	ORDER BY marked_at
+	LIMIT $1
+	FOR UPDATE SKIP LOCKED
+	)
+	RETURNING user_id::TEXT`
+		rows, err := tx.Query(ctx, query, limit)
+		if err != nil {
+			return fmt.Errorf("%w", err)
+		}
+		var userIds []string
+		for rows.Next() {
+			var userId string
+			if err := rows.Scan(&userId); err != nil {
+				rows.Close()
+				return fmt.Errorf("%w", err)
+			}
+			userIds = append(userIds, userId)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("%w", err)
+		}
+		for _, userId := range userIds {
+			if _, err := evaluateBudgets(ctx, tx, userId); err != nil {
+				return err
+			}
+		}
+		users = len(userIds)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return users, nil
+}
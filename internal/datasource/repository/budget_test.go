@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"service/internal/dto"
+	"service/internal/tenant"
+	"testing"
+	"time"
+)
+
+func TestDirtyBudgetsEvaluatedByWorker(t *testing.T) {
+	pool := newTestPool(t)
+	repo := NewDatabase(pool)
+	ctx := newTestContext(tenant.Default)
+	userId := addTestUser(t, pool, tenant.Default)
+
+	if _, err := repo.AddBudget(ctx, dto.AddBudgetToDb{UserId: userId, Amount: 100}); err != nil {
+		t.Fatalf("failed to add budget: %v", err)
+	}
+	if _, err := repo.EvaluateDirtyBudgets(context.Background(), 10); err != nil {
+		t.Fatalf("failed to evaluate budgets: %v", err)
+	}
+
+	now := time.Now().UTC()
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if err := repo.AddNewSubs(ctx, dto.AddSubToDb{
+		ServiceName:   "Yandex Plus",
+		Price:         500,
+		UserId:        userId,
+		StartDate:     month,
+		EndDate:       month.AddDate(0, 1, 0),
+		BillingPeriod: "month",
+		Status:        "active",
+	}); err != nil {
+		t.Fatalf("failed to add sub: %v", err)
+	}
+
+	alerts, err := repo.GetBudgetAlerts(ctx, dto.GetUserFromWeb{Id: userId})
+	if err != nil {
+		t.Fatalf("failed to get alerts: %v", err)
+	}
+	if len(alerts) != 0 {
+		t.Fatalf("sub write evaluated budgets itself: %d alerts", len(alerts))
+	}
+
+	users, err := repo.EvaluateDirtyBudgets(context.Background(), 10)
+	if err != nil {
+		t.Fatalf("failed to evaluate budgets: %v", err)
+	}
+	if users != 1 {
+		t.Fatalf("%d users evaluated, want 1", users)
+	}
+	alerts, err = repo.GetBudgetAlerts(ctx, dto.GetUserFromWeb{Id: userId})
+	if err != nil {
+		t.Fatalf("failed to get alerts: %v", err)
+	}
+	if len(alerts) != 1 || alerts[0].Spend != 500 {
+		t.Fatalf("unexpected alerts: %+v", alerts)
+	}
+
+	users, err = repo.EvaluateDirtyBudgets(context.Background(), 10)
+	if err != nil || users != 0 {
+		t.Fatalf("queue not drained: %d users, %v", users, err)
+	}
+}
+
+func TestSubWritesMarkBudgetsDirty(t *testing.T) {
+	pool := newTestPool(t)
+	repo := NewDatabase(pool)
+	ctx := newTestContext(tenant.Default)
+	first := addTestUser(t, pool, tenant.Default)
+	second := addTestUser(t, pool, tenant.Default)
+	for _, userId := range []string{first, second} {
+		if _, err := repo.AddBudget(ctx, dto.AddBudgetToDb{UserId: userId, Amount: 100}); err != nil {
+			t.Fatalf("failed to add budget: %v", err)
+		}
+	}
+	drain := func() {
+		t.Helper()
+		if _, err := pool.Exec(context.Background(), `DELETE FROM budget_dirty_users`); err != nil {
+			t.Fatalf("failed to drain queue: %v", err)
+		}
+	}
+	dirty := func(want ...string) {
+		t.Helper()
+		rows, err := pool.Query(context.Background(), `SELECT user_id::TEXT FROM budget_dirty_users`)
+		if err != nil {
+			t.Fatalf("failed to read queue: %v", err)
+		}
+		defer rows.Close()
+		got := make(map[string]bool)
+		for rows.Next() {
+			var userId string
+			if err := rows.Scan(&userId); err != nil {
+				t.Fatalf("failed to read queue: %v", err)
+			}
+			got[userId] = true
+		}
+		if len(got) != len(want) {
+			t.Fatalf("dirty users %v, want %v", got, want)
+		}
+		for _, userId := range want {
+			if !got[userId] {
+				t.Fatalf("dirty users %v, want %v", got, want)
+			}
+		}
+	}
+
+	month := time.Date(time.Now().Year()-1, time.January, 1, 0, 0, 0, 0, time.UTC)
+	if err := repo.AddNewSubs(ctx, dto.AddSubToDb{
+		ServiceName:   "Yandex Plus",
+		Price:         500,
+		UserId:        first,
+		StartDate:     month,
+		EndDate:       month.AddDate(0, 1, 0),
+		BillingPeriod: "month",
+		Status:        "active",
+	}); err != nil {
+		t.Fatalf("failed to add sub: %v", err)
+	}
+	subs, err := repo.GetListSubByUser(ctx, dto.GetSubByUserFromWeb{UserId: first})
+	if err != nil || len(subs) != 1 {
+		t.Fatalf("failed to get sub: %v, %d subs", err, len(subs))
+	}
+	subId := subs[0].Id
+
+	drain()
+	if err := repo.UpdateSubById(ctx, dto.UpdateSubToDb{Id: subId, UserId: second}); err != nil {
+		t.Fatalf("failed to move sub: %v", err)
+	}
+	dirty(first, second)
+
+	drain()
+	if n, err := repo.ExpireSubs(context.Background(), dto.ExpireSubsToDb{Before: time.Now(), Limit: 10}); err != nil || n != 1 {
+		t.Fatalf("failed to expire sub: %d, %v", n, err)
+	}
+	dirty(second)
+
+	drain()
+	if err := repo.DeleteSub(ctx, dto.GetSubFromWeb{Id: subId}); err != nil {
+		t.Fatalf("failed to delete sub: %v", err)
+	}
+	dirty(second)
+}
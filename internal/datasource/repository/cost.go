@@ -0,0 +1,68 @@
+package repository
+
+// subMonthlyCosts expands subs into one row per billed month between
+// @start_date and @end_date. A month is billed when it falls inside the
+// subscription period and is not covered by a pause interval, at the price
+// that was in effect for that month. Trial months cost nothing and the
+// discount applies to the discount_months that follow the trial. Prices of
+// non-monthly plans are per billing period and are prorated to the part of
+// the month the subscription covers. Subs are limited to @tenant_id unless it
+// is empty.
+const subMonthlyCosts = `SELECT
+	sub_id,
+	user_id,
+	service_name,
+	category,
+	month,
+	CASE
+	WHEN month_index < trial_months THEN 0
+	WHEN month_index >= trial_months + discount_months THEN price
+	WHEN discount_type = 'percent' THEN price * (100 - LEAST(discount_value, 100)) / 100
+	WHEN discount_type = 'fixed' THEN GREATEST(price - discount_value, 0)
+	ELSE price
+	END * CASE billing_period
+	WHEN 'month' THEN 1
+	ELSE CASE billing_period
+	WHEN 'week' THEN 52.0 / 12
+	WHEN 'quarter' THEN 1.0 / 3
+	ELSE 1.0 / 12
+	END
+	* EXTRACT(EPOCH FROM LEAST(end_date, month + INTERVAL '1 month') - GREATEST(start_date, month))::NUMERIC
+	/ EXTRACT(EPOCH FROM (month + INTERVAL '1 month') - month)::NUMERIC
+	END AS cost
+	FROM (
+	SELECT
+	s.id AS sub_id,
+	s.user_id,
+	s.service_name,
+	s.category,
+	m.month,
+	s.start_date,
+	s.end_date,
+	s.billing_period,
+	s.trial_months,
+	s.discount_type,
+	s.discount_value,
+	s.discount_months,
+	(EXTRACT(YEAR FROM age(m.month, date_trunc('month', s.start_date))) * 12
+	+ EXTRACT(MONTH FROM age(m.month, date_trunc('month', s.start_date))))::INTEGER AS month_index,
+	COALESCE((
+	SELECT sp.price
+	FROM subscription_prices sp
+	WHERE sp.sub_id = s.id AND sp.effective_from <= m.month
+	ORDER BY sp.effective_from DESC, sp.id DESC
+	LIMIT 1
+	), s.price) AS price
+	FROM subs s
+	CROSS JOIN generate_series(
+	date_trunc('month', @start_date::TIMESTAMPTZ),
+	@end_date::TIMESTAMPTZ,
+	INTERVAL '1 month') AS m(month)
+	WHERE (@tenant_id = '' OR s.tenant_id = @tenant_id)
+	AND date_trunc('month', s.start_date) <= m.month AND s.end_date > m.month
+	AND NOT EXISTS (
+	SELECT 1
+	FROM sub_pauses p
+	WHERE p.sub_id = s.id AND p.paused_at <= m.month AND (p.resumed_at IS NULL OR p.resumed_at > m.month)
+	)
+	) billed`
@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+)
+
+func (r *Repository) Ping(ctx context.Context) error {
+	if err := r.Client.Ping(ctx); err != nil {
+		return fmt.Errorf("failed to ping database: %w", err)
+	}
+	return nil
+}
+
+// GetSchemaVersion returns the latest migration applied by goose.
+func (r *Repository) GetSchemaVersion(ctx context.Context) (int64, error) {
+	var version int64
+	query := `SELECT COALESCE(MAX(version_id), 0) FROM goose_db_version WHERE is_applied`
+	if err := r.Client.QueryRow(ctx, query).Scan(&version); err != nil {
+		return 0, fmt.Errorf("failed to get schema version: %w", err)
+	}
+	return version, nil
+}
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"service/internal/tenant"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/labstack/echo/v4"
+)
+
+// testDatabaseEnv names the Postgres URL the integration tests run against.
+// They are skipped when it is not set.
+const testDatabaseEnv = "TEST_DATABASE_URL"
+
+// newTestPool migrates a fresh schema of the test database and returns a
+// pool whose connections use it. The schema is dropped when the test ends.
+func newTestPool(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+	url := os.Getenv(testDatabaseEnv)
+	if url == "" {
+		t.Skipf("%s is not set", testDatabaseEnv)
+	}
+	ctx := context.Background()
+	schema := fmt.Sprintf("test_%d", time.Now().UnixNano())
+
+	admin, err := pgxpool.New(ctx, url)
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	t.Cleanup(admin.Close)
+	if _, err := admin.Exec(ctx, `CREATE SCHEMA `+schema); err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+	t.Cleanup(func() {
+		admin.Exec(context.Background(), `DROP SCHEMA `+schema+` CASCADE`)
+	})
+
+	cfg, err := pgxpool.ParseConfig(url)
+	if err != nil {
+		t.Fatalf("failed to parse %s: %v", testDatabaseEnv, err)
+	}
+	cfg.ConnConfig.RuntimeParams["search_path"] = schema + ",public"
+	pool, err := pgxpool.NewWithConfig(ctx, cfg)
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	t.Cleanup(pool.Close)
+	migrate(t, pool, "../../../migrations")
+	return pool
+}
+
+// migrate applies the goose Up sections of the migrations in dir.
+func migrate(t *testing.T, pool *pgxpool.Pool, dir string) {
+	t.Helper()
+	files, err := filepath.Glob(filepath.Join(dir, "*.sql"))
+	if err != nil {
+		t.Fatalf("failed to list migrations: %v", err)
+	}
+	sort.Strings(files)
+	for _, file := range files {
+		raw, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", file, err)
+		}
+		up, _, _ := strings.Cut(string(raw), "-- +goose Down")
+		if _, err := pool.Exec(context.Background(), up); err != nil {
+			t.Fatalf("failed to apply %s: %v", file, err)
+		}
+	}
+}
+
+// newTestContext returns an echo context of a request in tenantId.
+func newTestContext(tenantId string) echo.Context {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req = req.WithContext(tenant.WithTenant(req.Context(), tenantId))
+	return echo.New().NewContext(req, httptest.NewRecorder())
+}
+
+func addTestUser(t *testing.T, pool *pgxpool.Pool, tenantId string) string {
+	t.Helper()
+	var id string
+	err := pool.QueryRow(context.Background(),
+		`INSERT INTO users (display_name, tenant_id) VALUES ('test', $1) RETURNING id::TEXT`, tenantId).Scan(&id)
+	if err != nil {
+		t.Fatalf("failed to add user: %v", err)
+	}
+	return id
+}
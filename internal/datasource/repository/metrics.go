@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"service/internal/dto"
+)
+
+// CountSubsByStatus counts subscriptions of all tenants per status.
+func (r *Repository) CountSubsByStatus(ctx context.Context) ([]dto.SubStatusCountFromDb, error) {
+	rows, err := r.Client.Query(ctx, `SELECT status, COUNT(*) FROM subs GROUP BY status`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count subs: %w", err)
+	}
+	defer rows.Close()
+	var out []dto.SubStatusCountFromDb
+	for rows.Next() {
+		var c dto.SubStatusCountFromDb
+		if err := rows.Scan(&c.Status, &c.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan sub count: %w", err)
+		}
+		out = append(out, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to count subs: %w", err)
+	}
+	return out, nil
+}
@@ -0,0 +1,395 @@
+package repository
+
+import (
+	"context"
+	"service/internal/dto"
+
+	"github.com/labstack/echo/v4"
+)
+
+type (
+	// Hook runs around every Storage call. It may derive the context the call
+	// runs with and returns the function that receives the call's error.
+	Hook func(ctx context.Context, method string) (context.Context, func(error))
+
+	observed struct {
+		next  Storage
+		hooks []Hook
+	}
+)
+
+var _ Storage = (*observed)(nil)
+
+// NewObserved wraps next so every call goes through hooks, e.g. to record
+// metrics or trace spans.
+func NewObserved(next Storage, hooks ...Hook) Storage {
+	return &observed{next: next, hooks: hooks}
+}
+
+func (s *observed) start(ctx context.Context, method string) (context.Context, func(error)) {
+	dones := make([]func(error), 0, len(s.hooks))
+	for _, hook := range s.hooks {
+		var done func(error)
+		ctx, done = hook(ctx, method)
+		dones = append(dones, done)
+	}
+	return ctx, func(err error) {
+		for i := len(dones) - 1; i >= 0; i-- {
+			dones[i](err)
+		}
+	}
+}
+
+// startRequest runs the hooks on the request context and restores it once
+// the call is done.
+func (s *observed) startRequest(ctx echo.Context, method string) func(error) {
+	req := ctx.Request()
+	c, done := s.start(req.Context(), method)
+	ctx.SetRequest(req.WithContext(c))
+	return func(err error) {
+		ctx.SetRequest(req)
+		done(err)
+	}
+}
+
+func (s *observed) AddNewSubs(ctx echo.Context, data dto.AddSubToDb) (err error) {
+	done := s.startRequest(ctx, "AddNewSubs")
+	defer func() { done(err) }()
+	return s.next.AddNewSubs(ctx, data)
+}
+
+func (s *observed) GetSubById(ctx echo.Context, data dto.GetSubFromWeb) (out dto.GetSubFromDb, err error) {
+	done := s.startRequest(ctx, "GetSubById")
+	defer func() { done(err) }()
+	return s.next.GetSubById(ctx, data)
+}
+
+func (s *observed) GetListSub(ctx echo.Context, data dto.GetSubListFromWeb) (out []dto.GetSubFromDb, err error) {
+	done := s.startRequest(ctx, "GetListSub")
+	defer func() { done(err) }()
+	return s.next.GetListSub(ctx, data)
+}
+
+func (s *observed) GetListSubByUser(ctx echo.Context, data dto.GetSubByUserFromWeb) (out []dto.GetSubFromDb, err error) {
+	done := s.startRequest(ctx, "GetListSubByUser")
+	defer func() { done(err) }()
+	return s.next.GetListSubByUser(ctx, data)
+}
+
+func (s *observed) GetPriceSubByFilter(ctx echo.Context, data dto.GetSubPriceByFilterToDb) (out dto.GetSubPriceByFilterFromDb, err error) {
+	done := s.startRequest(ctx, "GetPriceSubByFilter")
+	defer func() { done(err) }()
+	return s.next.GetPriceSubByFilter(ctx, data)
+}
+
+func (s *observed) UpdateSubById(ctx echo.Context, data dto.UpdateSubToDb) (err error) {
+	done := s.startRequest(ctx, "UpdateSubById")
+	defer func() { done(err) }()
+	return s.next.UpdateSubById(ctx, data)
+}
+
+func (s *observed) DeleteSub(ctx echo.Context, data dto.GetSubFromWeb) (err error) {
+	done := s.startRequest(ctx, "DeleteSub")
+	defer func() { done(err) }()
+	return s.next.DeleteSub(ctx, data)
+}
+
+func (s *observed) AddNewUser(ctx echo.Context, data dto.AddUserToDb) (out dto.GetUserFromDb, err error) {
+	done := s.startRequest(ctx, "AddNewUser")
+	defer func() { done(err) }()
+	return s.next.AddNewUser(ctx, data)
+}
+
+func (s *observed) GetUserById(ctx echo.Context, data dto.GetUserFromWeb) (out dto.GetUserFromDb, err error) {
+	done := s.startRequest(ctx, "GetUserById")
+	defer func() { done(err) }()
+	return s.next.GetUserById(ctx, data)
+}
+
+func (s *observed) GetListUsers(ctx echo.Context) (out []dto.GetUserFromDb, err error) {
+	done := s.startRequest(ctx, "GetListUsers")
+	defer func() { done(err) }()
+	return s.next.GetListUsers(ctx)
+}
+
+func (s *observed) UpdateUserById(ctx echo.Context, data dto.UpdateUserToDb) (err error) {
+	done := s.startRequest(ctx, "UpdateUserById")
+	defer func() { done(err) }()
+	return s.next.UpdateUserById(ctx, data)
+}
+
+func (s *observed) DeleteUser(ctx echo.Context, data dto.GetUserFromWeb) (err error) {
+	done := s.startRequest(ctx, "DeleteUser")
+	defer func() { done(err) }()
+	return s.next.DeleteUser(ctx, data)
+}
+
+func (s *observed) GetActiveSubsByUser(ctx echo.Context, data dto.GetSubByUserFromWeb) (out []dto.GetSubFromDb, err error) {
+	done := s.startRequest(ctx, "GetActiveSubsByUser")
+	defer func() { done(err) }()
+	return s.next.GetActiveSubsByUser(ctx, data)
+}
+
+func (s *observed) GetUserReminders(ctx echo.Context, data dto.GetUserFromWeb) (out []dto.SubReminderFromDb, err error) {
+	done := s.startRequest(ctx, "GetUserReminders")
+	defer func() { done(err) }()
+	return s.next.GetUserReminders(ctx, data)
+}
+
+func (s *observed) AddBudget(ctx echo.Context, data dto.AddBudgetToDb) (out dto.GetBudgetFromDb, err error) {
+	done := s.startRequest(ctx, "AddBudget")
+	defer func() { done(err) }()
+	return s.next.AddBudget(ctx, data)
+}
+
+func (s *observed) GetBudgetById(ctx echo.Context, data dto.GetBudgetFromWeb) (out dto.GetBudgetFromDb, err error) {
+	done := s.startRequest(ctx, "GetBudgetById")
+	defer func() { done(err) }()
+	return s.next.GetBudgetById(ctx, data)
+}
+
+func (s *observed) GetBudgetsByUser(ctx echo.Context, data dto.GetUserFromWeb) (out []dto.GetBudgetFromDb, err error) {
+	done := s.startRequest(ctx, "GetBudgetsByUser")
+	defer func() { done(err) }()
+	return s.next.GetBudgetsByUser(ctx, data)
+}
+
+func (s *observed) UpdateBudgetById(ctx echo.Context, data dto.UpdateBudgetToDb) (err error) {
+	done := s.startRequest(ctx, "UpdateBudgetById")
+	defer func() { done(err) }()
+	return s.next.UpdateBudgetById(ctx, data)
+}
+
+func (s *observed) DeleteBudget(ctx echo.Context, data dto.GetBudgetFromWeb) (err error) {
+	done := s.startRequest(ctx, "DeleteBudget")
+	defer func() { done(err) }()
+	return s.next.DeleteBudget(ctx, data)
+}
+
+func (s *observed) GetBudgetAlerts(ctx echo.Context, data dto.GetUserFromWeb) (out []dto.BudgetAlertFromDb, err error) {
+	done := s.startRequest(ctx, "GetBudgetAlerts")
+	defer func() { done(err) }()
+	return s.next.GetBudgetAlerts(ctx, data)
+}
+
+func (s *observed) UpdateSubStatus(ctx echo.Context, data dto.UpdateSubStatusToDb) (err error) {
+	done := s.startRequest(ctx, "UpdateSubStatus")
+	defer func() { done(err) }()
+	return s.next.UpdateSubStatus(ctx, data)
+}
+
+func (s *observed) GetSubStatusHistory(ctx echo.Context, data dto.GetSubFromWeb) (out []dto.SubStatusHistoryFromDb, err error) {
+	done := s.startRequest(ctx, "GetSubStatusHistory")
+	defer func() { done(err) }()
+	return s.next.GetSubStatusHistory(ctx, data)
+}
+
+func (s *observed) GetSubPrices(ctx echo.Context, data dto.GetSubFromWeb) (out []dto.SubPriceFromDb, err error) {
+	done := s.startRequest(ctx, "GetSubPrices")
+	defer func() { done(err) }()
+	return s.next.GetSubPrices(ctx, data)
+}
+
+func (s *observed) GetUpcomingSubs(ctx echo.Context, data dto.GetUpcomingSubsToDb) (out dto.GetUpcomingSubsFromDb, err error) {
+	done := s.startRequest(ctx, "GetUpcomingSubs")
+	defer func() { done(err) }()
+	return s.next.GetUpcomingSubs(ctx, data)
+}
+
+func (s *observed) FindSubOverlaps(ctx echo.Context, data dto.FindSubOverlapsToDb) (out []dto.GetSubFromDb, err error) {
+	done := s.startRequest(ctx, "FindSubOverlaps")
+	defer func() { done(err) }()
+	return s.next.FindSubOverlaps(ctx, data)
+}
+
+func (s *observed) GetSubOverlaps(ctx echo.Context, data dto.GetSubOverlapsFromWeb) (out []dto.SubOverlapFromDb, err error) {
+	done := s.startRequest(ctx, "GetSubOverlaps")
+	defer func() { done(err) }()
+	return s.next.GetSubOverlaps(ctx, data)
+}
+
+func (s *observed) GetSpendByMonth(ctx echo.Context, data dto.GetSpendAnalyticsToDb) (out []dto.SpendByMonthFromDb, err error) {
+	done := s.startRequest(ctx, "GetSpendByMonth")
+	defer func() { done(err) }()
+	return s.next.GetSpendByMonth(ctx, data)
+}
+
+func (s *observed) GetTopServices(ctx echo.Context, data dto.GetTopServicesToDb) (out []dto.TopServiceFromDb, err error) {
+	done := s.startRequest(ctx, "GetTopServices")
+	defer func() { done(err) }()
+	return s.next.GetTopServices(ctx, data)
+}
+
+func (s *observed) GetChurnByMonth(ctx echo.Context, data dto.GetChurnToDb) (out []dto.ChurnByMonthFromDb, err error) {
+	done := s.startRequest(ctx, "GetChurnByMonth")
+	defer func() { done(err) }()
+	return s.next.GetChurnByMonth(ctx, data)
+}
+
+func (s *observed) AddWebhook(ctx echo.Context, data dto.AddWebhookToDb) (out dto.GetWebhookFromDb, err error) {
+	done := s.startRequest(ctx, "AddWebhook")
+	defer func() { done(err) }()
+	return s.next.AddWebhook(ctx, data)
+}
+
+func (s *observed) GetWebhookById(ctx echo.Context, data dto.GetWebhookFromWeb) (out dto.GetWebhookFromDb, err error) {
+	done := s.startRequest(ctx, "GetWebhookById")
+	defer func() { done(err) }()
+	return s.next.GetWebhookById(ctx, data)
+}
+
+func (s *observed) GetListWebhooks(ctx echo.Context) (out []dto.GetWebhookFromDb, err error) {
+	done := s.startRequest(ctx, "GetListWebhooks")
+	defer func() { done(err) }()
+	return s.next.GetListWebhooks(ctx)
+}
+
+func (s *observed) UpdateWebhookById(ctx echo.Context, data dto.UpdateWebhookToDb) (err error) {
+	done := s.startRequest(ctx, "UpdateWebhookById")
+	defer func() { done(err) }()
+	return s.next.UpdateWebhookById(ctx, data)
+}
+
+func (s *observed) DeleteWebhook(ctx echo.Context, data dto.GetWebhookFromWeb) (err error) {
+	done := s.startRequest(ctx, "DeleteWebhook")
+	defer func() { done(err) }()
+	return s.next.DeleteWebhook(ctx, data)
+}
+
+func (s *observed) GetWebhookDeliveries(ctx echo.Context, data dto.GetWebhookDeliveriesFromWeb) (out []dto.WebhookDeliveryFromDb, err error) {
+	done := s.startRequest(ctx, "GetWebhookDeliveries")
+	defer func() { done(err) }()
+	return s.next.GetWebhookDeliveries(ctx, data)
+}
+
+func (s *observed) GetWebhookAttempts(ctx echo.Context, data dto.GetWebhookDeliveryFromWeb) (out []dto.WebhookAttemptFromDb, err error) {
+	done := s.startRequest(ctx, "GetWebhookAttempts")
+	defer func() { done(err) }()
+	return s.next.GetWebhookAttempts(ctx, data)
+}
+
+func (s *observed) RedeliverWebhook(ctx echo.Context, data dto.GetWebhookDeliveryFromWeb) (err error) {
+	done := s.startRequest(ctx, "RedeliverWebhook")
+	defer func() { done(err) }()
+	return s.next.RedeliverWebhook(ctx, data)
+}
+
+func (s *observed) AddAPIKey(ctx echo.Context, data dto.AddAPIKeyToDb) (out dto.GetAPIKeyFromDb, err error) {
+	done := s.startRequest(ctx, "AddAPIKey")
+	defer func() { done(err) }()
+	return s.next.AddAPIKey(ctx, data)
+}
+
+func (s *observed) GetListAPIKeys(ctx echo.Context) (out []dto.GetAPIKeyFromDb, err error) {
+	done := s.startRequest(ctx, "GetListAPIKeys")
+	defer func() { done(err) }()
+	return s.next.GetListAPIKeys(ctx)
+}
+
+func (s *observed) RevokeAPIKey(ctx echo.Context, data dto.GetAPIKeyFromWeb) (err error) {
+	done := s.startRequest(ctx, "RevokeAPIKey")
+	defer func() { done(err) }()
+	return s.next.RevokeAPIKey(ctx, data)
+}
+
+func (s *observed) RotateAPIKey(ctx echo.Context, data dto.RotateAPIKeyToDb) (out dto.GetAPIKeyFromDb, err error) {
+	done := s.startRequest(ctx, "RotateAPIKey")
+	defer func() { done(err) }()
+	return s.next.RotateAPIKey(ctx, data)
+}
+
+func (s *observed) UseAPIKey(ctx context.Context, keyHash string) (out dto.APIKeyAuthFromDb, err error) {
+	ctx, done := s.start(ctx, "UseAPIKey")
+	defer func() { done(err) }()
+	return s.next.UseAPIKey(ctx, keyHash)
+}
+
+func (s *observed) TakeRateLimit(ctx context.Context, data dto.RateLimitToDb) (out dto.RateLimitFromDb, err error) {
+	ctx, done := s.start(ctx, "TakeRateLimit")
+	defer func() { done(err) }()
+	return s.next.TakeRateLimit(ctx, data)
+}
+
+func (s *observed) PeekRateLimit(ctx context.Context, data dto.RateLimitToDb) (out dto.RateLimitFromDb, err error) {
+	ctx, done := s.start(ctx, "PeekRateLimit")
+	defer func() { done(err) }()
+	return s.next.PeekRateLimit(ctx, data)
+}
+
+func (s *observed) PurgeRateLimits(ctx context.Context) (out int, err error) {
+	ctx, done := s.start(ctx, "PurgeRateLimits")
+	defer func() { done(err) }()
+	return s.next.PurgeRateLimits(ctx)
+}
+
+func (s *observed) Ping(ctx context.Context) (err error) {
+	ctx, done := s.start(ctx, "Ping")
+	defer func() { done(err) }()
+	return s.next.Ping(ctx)
+}
+
+func (s *observed) GetSchemaVersion(ctx context.Context) (out int64, err error) {
+	ctx, done := s.start(ctx, "GetSchemaVersion")
+	defer func() { done(err) }()
+	return s.next.GetSchemaVersion(ctx)
+}
+
+func (s *observed) CountSubsByStatus(ctx context.Context) (out []dto.SubStatusCountFromDb, err error) {
+	ctx, done := s.start(ctx, "CountSubsByStatus")
+	defer func() { done(err) }()
+	return s.next.CountSubsByStatus(ctx)
+}
+
+func (s *observed) RenewSubs(ctx context.Context, data dto.RenewSubsToDb) (out int, err error) {
+	ctx, done := s.start(ctx, "RenewSubs")
+	defer func() { done(err) }()
+	return s.next.RenewSubs(ctx, data)
+}
+
+func (s *observed) ExpireSubs(ctx context.Context, data dto.ExpireSubsToDb) (out int, err error) {
+	ctx, done := s.start(ctx, "ExpireSubs")
+	defer func() { done(err) }()
+	return s.next.ExpireSubs(ctx, data)
+}
+
+func (s *observed) EndTrials(ctx context.Context, data dto.ExpireSubsToDb) (out int, err error) {
+	ctx, done := s.start(ctx, "EndTrials")
+	defer func() { done(err) }()
+	return s.next.EndTrials(ctx, data)
+}
+
+func (s *observed) RelayOutbox(ctx context.Context, limit int, publish func(dto.OutboxEventFromDb) dto.OutboxAttemptToDb) (out int, err error) {
+	ctx, done := s.start(ctx, "RelayOutbox")
+	defer func() { done(err) }()
+	return s.next.RelayOutbox(ctx, limit, publish)
+}
+
+func (s *observed) EnqueueWebhookDeliveries(ctx context.Context, data dto.EnqueueWebhookToDb) (out int, err error) {
+	ctx, done := s.start(ctx, "EnqueueWebhookDeliveries")
+	defer func() { done(err) }()
+	return s.next.EnqueueWebhookDeliveries(ctx, data)
+}
+
+func (s *observed) DeliverWebhooks(ctx context.Context, limit int, deliver func(dto.WebhookDeliveryJob) dto.WebhookAttemptToDb) (out int, err error) {
+	ctx, done := s.start(ctx, "DeliverWebhooks")
+	defer func() { done(err) }()
+	return s.next.DeliverWebhooks(ctx, limit, deliver)
+}
+
+func (s *observed) EvaluateBudgets(ctx context.Context) (out int, err error) {
+	ctx, done := s.start(ctx, "EvaluateBudgets")
+	defer func() { done(err) }()
+	return s.next.EvaluateBudgets(ctx)
+}
+
+func (s *observed) EvaluateDirtyBudgets(ctx context.Context, limit int) (out int, err error) {
+	ctx, done := s.start(ctx, "EvaluateDirtyBudgets")
+	defer func() { done(err) }()
+	return s.next.EvaluateDirtyBudgets(ctx, limit)
+}
+
+func (s *observed) SendReminders(ctx context.Context, data dto.DueRemindersToDb, notify func(dto.SubReminderJob) dto.SubReminderToDb) (out int, err error) {
+	ctx, done := s.start(ctx, "SendReminders")
+	defer func() { done(err) }()
+	return s.next.SendReminders(ctx, data, notify)
+}
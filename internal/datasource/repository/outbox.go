@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"service/internal/dto"
+	"service/internal/tenant"
+	"slices"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	EventSubCreated       = "subscription.created"
+	EventSubUpdated       = "subscription.updated"
+	EventSubDeleted       = "subscription.deleted"
+	EventSubStatusChanged = "subscription.status_changed"
+	EventSubRenewed       = "subscription.renewed"
+	EventSubExpired       = "subscription.expired"
+	EventBudgetExceeded   = "budget.exceeded"
+)
+
+// leaseDuration is how long a worker owns the rows it leased. Rows of a
+// worker that died mid-batch become due again once it passes.
+const leaseDuration = 5 * time.Minute
+
+func subSubject(id int) string {
+	return fmt.Sprintf("subs/%d", id)
+}
+
+func writeOutbox(ctx context.Context, tx pgx.Tx, eventType, subject string, data any) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s event: %w", eventType, err)
+	}
+	query := `INSERT INTO outbox (
+	tenant_id,
+	event_type,
+	subject,
+	data) VALUES ($1, $2, $3, $4)`
+	if _, err := tx.Exec(ctx, query, tenant.FromContext(ctx), eventType, subject, payload); err != nil {
+		return fmt.Errorf("failed to write %s event: %w", eventType, err)
+	}
+	return nil
+}
+
+// RelayOutbox leases up to limit due events by moving their next_attempt_at
+// past leaseDuration, publishes them outside of the leasing transaction and
+// records every result on its own. An event that fails max attempts times
+// is dead-lettered by the caller through GiveUp.
+func (r *Repository) RelayOutbox(ctx context.Context, limit int, publish func(dto.OutboxEventFromDb) dto.OutboxAttemptToDb) (int, error) {
+	query := `WITH due AS (
+	SELECT id
+	FROM outbox
+	WHERE published_at IS NULL AND dead_at IS NULL AND next_attempt_at <= now()
+	ORDER BY next_attempt_at, id
+	LIMIT $1
+	FOR UPDATE SKIP LOCKED
+	)
+	UPDATE outbox o SET
+	next_attempt_at = now() + make_interval(secs => $2)
+	FROM due
+	WHERE o.id = due.id
+	RETURNING
+	o.id,
+	o.event_id,
+	o.tenant_id,
+	o.event_type,
+	o.subject,
+	o.data,
+	o.created_at,
+	o.attempts,
+	o.fanned_out_at`
+	rows, err := r.Client.Query(ctx, query, limit, leaseDuration.Seconds())
+	if err != nil {
+		return 0, fmt.Errorf("failed to lease outbox events: %w", err)
+	}
+	defer rows.Close()
+	var events []dto.OutboxEventFromDb
+	for rows.Next() {
+		var event dto.OutboxEventFromDb
+		if err := rows.Scan(
+			&event.Id,
+			&event.EventId,
+			&event.TenantId,
+			&event.EventType,
+			&event.Subject,
+			&event.Data,
+			&event.CreatedAt,
+			&event.Attempts,
+			&event.FannedOutAt); err != nil {
+			return 0, fmt.Errorf("%w", err)
+		}
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("%w", err)
+	}
+	rows.Close()
+	slices.SortFunc(events, func(a, b dto.OutboxEventFromDb) int {
+		return cmp.Compare(a.Id, b.Id)
+	})
+
+	var processed int
+	for _, event := range events {
+		attempt := publish(event)
+		attempt.Id = event.Id
+		if err := r.recordOutboxAttempt(ctx, attempt); err != nil {
+			return processed, err
+		}
+		processed++
+	}
+	return processed, nil
+}
+
+func (r *Repository) recordOutboxAttempt(ctx context.Context, data dto.OutboxAttemptToDb) error {
+	if data.Published {
+		if _, err := r.Client.Exec(ctx, `UPDATE outbox SET published_at = now() WHERE id = $1`, data.Id); err != nil {
+			return fmt.Errorf("failed to mark outbox event published: %w", err)
+		}
+		return nil
+	}
+	query := `UPDATE outbox SET
+	attempts = attempts + 1,
+	last_error = $1,
+	next_attempt_at = $2,
+	dead_at = CASE WHEN $3 THEN now() END
+	WHERE id = $4`
+	if _, err := r.Client.Exec(ctx, query, data.Error, data.NextAttempt, data.GiveUp, data.Id); err != nil {
+		return fmt.Errorf("failed to record outbox failure: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"service/internal/dto"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/labstack/echo/v4"
+)
+
+const pgExclusionViolation = "23P01"
+
+// overlapStatuses are the statuses a subscription can overlap in, they match
+// the predicate of the optional subs_no_overlap exclusion constraint.
+const overlapStatuses = `('trial', 'active', 'paused')`
+
+// lockSubUsers locks the row of userId and of the current owner of sub subId
+// for the rest of tx. Sub writes of a user are serialized this way, so
+// rejectSubOverlaps cannot miss a sub inserted concurrently.
+func lockSubUsers(ctx context.Context, tx pgx.Tx, subId int, userId string) error {
+	query := `SELECT 1
+	FROM users
+	WHERE id = NULLIF($2, '')::UUID OR id = (SELECT user_id FROM subs WHERE id = $1)
+	ORDER BY id
+	FOR UPDATE`
+	if _, err := tx.Exec(ctx, query, subId, userId); err != nil {
+		return fmt.Errorf("failed to lock user: %w", err)
+	}
+	return nil
+}
+
+// rejectSubOverlaps fails when sub subId, as written in tx, overlaps another
+// trial, active or paused sub of the same user and service.
+func rejectSubOverlaps(ctx context.Context, tx pgx.Tx, subId int) error {
+	query := `SELECT
+	o.id,
+	s.service_name
+	FROM subs s
+	JOIN subs o ON o.user_id = s.user_id AND lower(o.service_name) = lower(s.service_name)
+	AND o.id <> s.id AND o.status IN ` + overlapStatuses + `
+	AND o.start_date < s.end_date AND o.end_date > s.start_date
+	WHERE s.id = $1 AND s.status IN ` + overlapStatuses + `
+	ORDER BY o.id`
+	rows, err := tx.Query(ctx, query, subId)
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+	defer rows.Close()
+	var ids []string
+	var serviceName string
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id, &serviceName); err != nil {
+			return fmt.Errorf("%w", err)
+		}
+		ids = append(ids, fmt.Sprint(id))
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+	if len(ids) > 0 {
+		return fmt.Errorf("sub overlaps with %s sub(s) %s of the same user", serviceName, strings.Join(ids, ", "))
+	}
+	return nil
+}
+
+func (r *Repository) FindSubOverlaps(ctx echo.Context, data dto.FindSubOverlapsToDb) ([]dto.GetSubFromDb, error) {
+	query := `SELECT ` + subColumns + `
+	FROM subs
+	WHERE tenant_id = @tenant_id AND user_id = @user_id AND lower(service_name) = lower(@service_name)
+	AND status IN ` + overlapStatuses + `
+	AND start_date < @end_date AND end_date > @start_date
+	AND id <> @id
+	ORDER BY start_date`
+	args, err := StructToNamedArgs(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+	args["tenant_id"] = tenantOf(ctx)
+	rows, err := r.Client.Query(ctx.Request().Context(), query, args)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+	return collectSubs(rows)
+}
+
+func (r *Repository) GetSubOverlaps(ctx echo.Context, data dto.GetSubOverlapsFromWeb) ([]dto.SubOverlapFromDb, error) {
+	query := `SELECT
+	a.user_id,
+	a.service_name,
+	a.id,
+	b.id,
+	GREATEST(a.start_date, b.start_date),
+	LEAST(a.end_date, b.end_date)
+	FROM subs a
+	JOIN subs b ON b.user_id = a.user_id AND lower(b.service_name) = lower(a.service_name)
+	AND b.id > a.id AND b.start_date < a.end_date AND b.end_date > a.start_date
+	WHERE a.tenant_id = @tenant_id AND a.status IN ` + overlapStatuses + ` AND b.status IN ` + overlapStatuses + `
+	AND (@user_id = '' OR a.user_id::TEXT = @user_id)
+	AND (@service_name = '' OR lower(a.service_name) = lower(@service_name))
+	ORDER BY a.user_id, a.service_name, a.id, b.id`
+	args, err := StructToNamedArgs(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+	args["tenant_id"] = tenantOf(ctx)
+	rows, err := r.Client.Query(ctx.Request().Context(), query, args)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+	defer rows.Close()
+	var out []dto.SubOverlapFromDb
+	for rows.Next() {
+		var data dto.SubOverlapFromDb
+		if err := rows.Scan(
+			&data.UserId,
+			&data.ServiceName,
+			&data.FirstId,
+			&data.SecondId,
+			&data.OverlapStart,
+			&data.OverlapEnd); err != nil {
+			return nil, fmt.Errorf("%w", err)
+		}
+		out = append(out, data)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+	return out, nil
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"service/internal/dto"
+	"service/internal/tenant"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAddNewSubsRejectsConcurrentOverlaps(t *testing.T) {
+	pool := newTestPool(t)
+	repo := NewDatabase(pool)
+	userId := addTestUser(t, pool, tenant.Default)
+
+	const writers = 8
+	start := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	var wg sync.WaitGroup
+	errs := make(chan error, writers)
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- repo.AddNewSubs(newTestContext(tenant.Default), dto.AddSubToDb{
+				ServiceName:    "Yandex Plus",
+				Price:          400,
+				UserId:         userId,
+				StartDate:      start,
+				EndDate:        start.AddDate(0, 3, 0),
+				BillingPeriod:  "month",
+				Status:         "active",
+				RejectOverlaps: true,
+			})
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	var created int
+	for err := range errs {
+		if err == nil {
+			created++
+		}
+	}
+	if created != 1 {
+		t.Fatalf("%d overlapping subs created, want 1", created)
+	}
+}
+
+func TestUpdateSubByIdRejectsOverlap(t *testing.T) {
+	pool := newTestPool(t)
+	repo := NewDatabase(pool)
+	ctx := newTestContext(tenant.Default)
+	userId := addTestUser(t, pool, tenant.Default)
+
+	start := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	for _, sdate := range []time.Time{start, start.AddDate(0, 6, 0)} {
+		if err := repo.AddNewSubs(ctx, dto.AddSubToDb{
+			ServiceName:    "Yandex Plus",
+			Price:          400,
+			UserId:         userId,
+			StartDate:      sdate,
+			EndDate:        sdate.AddDate(0, 3, 0),
+			BillingPeriod:  "month",
+			Status:         "active",
+			RejectOverlaps: true,
+		}); err != nil {
+			t.Fatalf("failed to add sub: %v", err)
+		}
+	}
+	subs, err := repo.GetListSubByUser(ctx, dto.GetSubByUserFromWeb{UserId: userId})
+	if err != nil || len(subs) != 2 {
+		t.Fatalf("failed to list subs: %v (%d subs)", err, len(subs))
+	}
+	later := subs[0]
+	if subs[1].StartDate.After(later.StartDate) {
+		later = subs[1]
+	}
+	err = repo.UpdateSubById(ctx, dto.UpdateSubToDb{
+		Id:             later.Id,
+		StartDate:      start.AddDate(0, 1, 0),
+		EndDate:        start.AddDate(0, 4, 0),
+		RejectOverlaps: true,
+	})
+	if err == nil {
+		t.Fatal("overlapping update was not rejected")
+	}
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"service/internal/dto"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/labstack/echo/v4"
+)
+
+func addSubPrice(ctx context.Context, tx pgx.Tx, subId, price int, effectiveFrom time.Time) error {
+	query := `INSERT INTO subscription_prices (
+	sub_id,
+	price,
+	effective_from) VALUES ($1, $2, date_trunc('month', $3::TIMESTAMPTZ))`
+	if _, err := tx.Exec(ctx, query, subId, price, effectiveFrom); err != nil {
+		return fmt.Errorf("failed to record sub price: %w", err)
+	}
+	return nil
+}
+
+// moveFirstSubPrice moves the initial price of a sub to its new start date so
+// the months before the old start are billed at it. It is left alone once a
+// later price already applies from the new start.
+func moveFirstSubPrice(ctx context.Context, tx pgx.Tx, subId int, startDate time.Time) error {
+	query := `UPDATE subscription_prices sp
+	SET effective_from = date_trunc('month', $2::TIMESTAMPTZ)
+	WHERE sp.id = (
+	SELECT id FROM subscription_prices
+	WHERE sub_id = $1
+	ORDER BY effective_from, id
+	LIMIT 1
+	) AND NOT EXISTS (
+	SELECT 1 FROM subscription_prices o
+	WHERE o.sub_id = $1 AND o.id <> sp.id AND o.effective_from <= date_trunc('month', $2::TIMESTAMPTZ)
+	)`
+	if _, err := tx.Exec(ctx, query, subId, startDate); err != nil {
+		return fmt.Errorf("failed to move sub price: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) GetSubPrices(ctx echo.Context, data dto.GetSubFromWeb) ([]dto.SubPriceFromDb, error) {
+	if data.Id <= 0 {
+		return nil, fmt.Errorf("invalid sub ID: %d", data.Id)
+	}
+	query := `SELECT
+	sp.id,
+	sp.sub_id,
+	sp.price,
+	sp.effective_from,
+	sp.created_at
+	FROM subscription_prices sp
+	JOIN subs s ON s.id = sp.sub_id
+	WHERE sp.sub_id = $1 AND s.tenant_id = $2
+	ORDER BY sp.effective_from, sp.id`
+	rows, err := r.Client.Query(ctx.Request().Context(), query, data.Id, tenantOf(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+	defer rows.Close()
+	var out []dto.SubPriceFromDb
+	for rows.Next() {
+		var data dto.SubPriceFromDb
+		if err := rows.Scan(
+			&data.Id,
+			&data.SubId,
+			&data.Price,
+			&data.EffectiveFrom,
+			&data.CreatedAt); err != nil {
+			return nil, fmt.Errorf("%w", err)
+		}
+		out = append(out, data)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+	return out, nil
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"service/internal/dto"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// TakeRateLimit refills the bucket of @key and takes a token if one is
+// available. A missing bucket is first seeded full, so concurrent first
+// requests all go through the locked update below and none of them is
+// granted a token from a stale full bucket.
+func (r *Repository) TakeRateLimit(ctx context.Context, data dto.RateLimitToDb) (dto.RateLimitFromDb, error) {
+	args, err := StructToNamedArgs(data)
+	if err != nil {
+		return dto.RateLimitFromDb{}, fmt.Errorf("%w", err)
+	}
+	var out dto.RateLimitFromDb
+	err = r.inTx(ctx, func(tx pgx.Tx) error {
+		query := `INSERT INTO rate_limits (key, tokens, updated_at, full_at)
+	VALUES (@key, @capacity::FLOAT8, now(), now())
+	ON CONFLICT (key) DO NOTHING`
+		if _, err := tx.Exec(ctx, query, args); err != nil {
+			return fmt.Errorf("%w", err)
+		}
+
+		query = `UPDATE rate_limits r SET
+	tokens = next.tokens,
+	updated_at = now(),
+	full_at = now() + make_interval(secs => (@capacity::FLOAT8 - next.tokens) / @rate::FLOAT8)
+	FROM (
+	SELECT
+	CASE WHEN tokens >= 1 THEN tokens - 1 ELSE tokens END AS tokens,
+	tokens >= 1 AS allowed
+	FROM (
+	SELECT LEAST(@capacity::FLOAT8, tokens + EXTRACT(EPOCH FROM now() - updated_at) * @rate::FLOAT8) AS tokens
+	FROM rate_limits
+	WHERE key = @key
+	FOR UPDATE
+	) cur
+	) next
+	WHERE r.key = @key
+	RETURNING r.tokens, next.allowed`
+		return tx.QueryRow(ctx, query, args).Scan(&out.Tokens, &out.Allowed)
+	})
+	if err != nil {
+		return dto.RateLimitFromDb{}, fmt.Errorf("failed to take rate limit: %w", err)
+	}
+	return out, nil
+}
+
+// PeekRateLimit returns the refilled bucket of @key without taking a token.
+// A missing bucket is full.
+func (r *Repository) PeekRateLimit(ctx context.Context, data dto.RateLimitToDb) (dto.RateLimitFromDb, error) {
+	query := `SELECT COALESCE((
+	SELECT LEAST(@capacity::FLOAT8, tokens + EXTRACT(EPOCH FROM now() - updated_at) * @rate::FLOAT8)
+	FROM rate_limits
+	WHERE key = @key), @capacity::FLOAT8)`
+
+	args, err := StructToNamedArgs(data)
+	if err != nil {
+		return dto.RateLimitFromDb{}, fmt.Errorf("%w", err)
+	}
+	var out dto.RateLimitFromDb
+	if err := r.Client.QueryRow(ctx, query, args).Scan(&out.Tokens); err != nil {
+		return dto.RateLimitFromDb{}, fmt.Errorf("failed to peek rate limit: %w", err)
+	}
+	out.Allowed = out.Tokens >= 1
+	return out, nil
+}
+
+func (r *Repository) PurgeRateLimits(ctx context.Context) (int, error) {
+	res, err := r.Client.Exec(ctx, `DELETE FROM rate_limits WHERE full_at < now()`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge rate limits: %w", err)
+	}
+	return int(res.RowsAffected()), nil
+}
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+	"service/internal/dto"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestTakeRateLimitConcurrentFirstRequests(t *testing.T) {
+	pool := newTestPool(t)
+	repo := NewDatabase(pool)
+	data := dto.RateLimitToDb{Key: "ip:192.0.2.1", Capacity: 3, Rate: 0.0001}
+
+	var wg sync.WaitGroup
+	var allowed atomic.Int32
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			out, err := repo.TakeRateLimit(context.Background(), data)
+			if err != nil {
+				t.Errorf("failed to take rate limit: %v", err)
+				return
+			}
+			if out.Allowed {
+				allowed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	if n := allowed.Load(); n != 3 {
+		t.Fatalf("%d requests allowed, want 3", n)
+	}
+}
@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"service/internal/dto"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	ReminderSending = "sending"
+	ReminderSent    = "sent"
+	ReminderSkipped = "skipped"
+	ReminderFailed  = "failed"
+)
+
+// SendReminders claims up to limit due reminders by recording them as
+// sending and commits, so notify runs without holding row locks. Every
+// result is then recorded on its own. A claim left behind by a worker that
+// died mid-batch is taken over once leaseDuration has passed.
+func (r *Repository) SendReminders(ctx context.Context, data dto.DueRemindersToDb, notify func(dto.SubReminderJob) dto.SubReminderToDb) (int, error) {
+	var jobs []dto.SubReminderJob
+	err := r.inTx(ctx, func(tx pgx.Tx) error {
+		query := `SELECT
+	s.id,
+	s.service_name,
+	s.price,
+	s.end_date,
+	s.auto_renew,
+	u.id,
+	u.display_name,
+	u.email,
+	u.timezone
+	FROM subs s
+	JOIN users u ON u.id = s.user_id
+	LEFT JOIN sub_reminders r ON r.sub_id = s.id AND r.period_end = s.end_date
+	WHERE s.status IN ('trial', 'active') AND u.status = 'active' AND u.reminders_enabled
+	AND s.end_date > now() AND s.end_date <= $1
+	AND (r.id IS NULL OR (r.attempts < $4 AND (r.status = $2
+	OR (r.status = $3 AND r.updated_at < now() - make_interval(secs => $5)))))
+	ORDER BY s.end_date
+	LIMIT $6
+	FOR UPDATE OF s SKIP LOCKED`
+		rows, err := tx.Query(ctx, query, data.Before, ReminderFailed, ReminderSending, data.MaxAttempts, leaseDuration.Seconds(), data.Limit)
+		if err != nil {
+			return fmt.Errorf("%w", err)
+		}
+		for rows.Next() {
+			var job dto.SubReminderJob
+			if err := rows.Scan(
+				&job.SubId,
+				&job.ServiceName,
+				&job.Price,
+				&job.EndDate,
+				&job.AutoRenew,
+				&job.UserId,
+				&job.DisplayName,
+				&job.Email,
+				&job.Timezone); err != nil {
+				rows.Close()
+				return fmt.Errorf("%w", err)
+			}
+			jobs = append(jobs, job)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("%w", err)
+		}
+
+		query = `INSERT INTO sub_reminders (
+	tenant_id,
+	sub_id,
+	user_id,
+	period_end,
+	status)
+	SELECT tenant_id, id, $2::UUID, $3::TIMESTAMPTZ, $4::TEXT FROM subs WHERE id = $1
+	ON CONFLICT (sub_id, period_end) DO UPDATE SET
+	status = EXCLUDED.status,
+	attempts = sub_reminders.attempts + 1,
+	updated_at = now()`
+		for _, job := range jobs {
+			if _, err := tx.Exec(ctx, query, job.SubId, job.UserId, job.EndDate, ReminderSending); err != nil {
+				return fmt.Errorf("failed to claim reminder: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	var processed int
+	for _, job := range jobs {
+		result := notify(job)
+		var lastError *string
+		if result.Error != "" {
+			lastError = &result.Error
+		}
+		query := `UPDATE sub_reminders SET
+	status = $1,
+	last_error = $2,
+	updated_at = now()
+	WHERE sub_id = $3 AND period_end = $4`
+		if _, err := r.Client.Exec(ctx, query, result.Status, lastError, job.SubId, job.EndDate); err != nil {
+			return processed, fmt.Errorf("failed to record reminder: %w", err)
+		}
+		processed++
+	}
+	return processed, nil
+}
+
+func (r *Repository) GetUserReminders(ctx echo.Context, data dto.GetUserFromWeb) ([]dto.SubReminderFromDb, error) {
+	query := `SELECT
+	r.id,
+	r.sub_id,
+	r.user_id,
+	r.period_end,
+	r.status,
+	r.attempts,
+	r.last_error,
+	r.created_at,
+	r.updated_at
+	FROM sub_reminders r
+	JOIN users u ON u.id = r.user_id
+	WHERE r.user_id = $1 AND u.tenant_id = $2
+	ORDER BY r.created_at DESC`
+	rows, err := r.Client.Query(ctx.Request().Context(), query, data.Id, tenantOf(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+	defer rows.Close()
+	var out []dto.SubReminderFromDb
+	for rows.Next() {
+		var data dto.SubReminderFromDb
+		if err := rows.Scan(
+			&data.Id,
+			&data.SubId,
+			&data.UserId,
+			&data.PeriodEnd,
+			&data.Status,
+			&data.Attempts,
+			&data.LastError,
+			&data.CreatedAt,
+			&data.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("%w", err)
+		}
+		out = append(out, data)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+	return out, nil
+}
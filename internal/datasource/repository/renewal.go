@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"service/internal/dto"
+	"service/internal/tenant"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func (r *Repository) RenewSubs(ctx context.Context, data dto.RenewSubsToDb) (int, error) {
+	var renewals []dto.SubRenewalFromDb
+	err := r.inTx(ctx, func(tx pgx.Tx) error {
+		query := `SELECT
+	id,
+	COALESCE((
+	SELECT sp.price
+	FROM subscription_prices sp
+	WHERE sp.sub_id = subs.id AND sp.effective_from <= subs.end_date
+	ORDER BY sp.effective_from DESC, sp.id DESC
+	LIMIT 1
+	), price),
+	end_date,
+	end_date + CASE billing_period
+	WHEN 'week' THEN INTERVAL '1 week'
+	WHEN 'quarter' THEN INTERVAL '3 months'
+	WHEN 'year' THEN INTERVAL '1 year'
+	ELSE INTERVAL '1 month'
+	END,
+	status,
+	tenant_id,
+	user_id
+	FROM subs
+	WHERE auto_renew AND status IN ('trial', 'active') AND end_date <= $1
+	ORDER BY end_date
+	LIMIT $2
+	FOR UPDATE SKIP LOCKED`
+		rows, err := tx.Query(ctx, query, data.Before, data.Limit)
+		if err != nil {
+			return fmt.Errorf("%w", err)
+		}
+		var statuses, tenants, users []string
+		for rows.Next() {
+			var renewal dto.SubRenewalFromDb
+			var status, tenantId, userId string
+			if err := rows.Scan(&renewal.SubId, &renewal.Price, &renewal.PeriodStart, &renewal.PeriodEnd, &status, &tenantId, &userId); err != nil {
+				rows.Close()
+				return fmt.Errorf("%w", err)
+			}
+			renewals = append(renewals, renewal)
+			statuses = append(statuses, status)
+			tenants = append(tenants, tenantId)
+			users = append(users, userId)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("%w", err)
+		}
+
+		for i, renewal := range renewals {
+			// Outbox events and status changes are written for the tenant
+			// of the renewed sub.
+			ctx := tenant.WithTenant(ctx, tenants[i])
+			if _, err := tx.Exec(ctx, `INSERT INTO sub_renewals (
+	sub_id,
+	price,
+	period_start,
+	period_end) VALUES ($1, $2, $3, $4)`,
+				renewal.SubId,
+				renewal.Price,
+				renewal.PeriodStart,
+				renewal.PeriodEnd); err != nil {
+				return fmt.Errorf("failed to record renewal of sub %d: %w", renewal.SubId, err)
+			}
+			// Renewal also brings subs.price up to a price change that has
+			// taken effect since it was scheduled.
+			if _, err := tx.Exec(ctx, `UPDATE subs SET
+	end_date = $1,
+	price = COALESCE((
+	SELECT sp.price
+	FROM subscription_prices sp
+	WHERE sp.sub_id = subs.id AND sp.effective_from <= now()
+	ORDER BY sp.effective_from DESC, sp.id DESC
+	LIMIT 1
+	), price)
+	WHERE id = $2`, renewal.PeriodEnd, renewal.SubId); err != nil {
+				return fmt.Errorf("failed to renew sub %d: %w", renewal.SubId, err)
+			}
+			if err := writeOutbox(ctx, tx, EventSubRenewed, subSubject(renewal.SubId), renewal); err != nil {
+				return err
+			}
+			if err := markBudgetsDirty(ctx, tx, users[i]); err != nil {
+				return err
+			}
+			if statuses[i] == "trial" {
+				if err := setSubStatus(ctx, tx, dto.UpdateSubStatusToDb{
+					Id:         renewal.SubId,
+					FromStatus: "trial",
+					ToStatus:   "active",
+				}); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(renewals), nil
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"reflect"
 	"service/internal/dto"
+	"service/internal/tenant"
 	"strings"
 
 	"github.com/jackc/pgx/v5"
@@ -18,6 +19,8 @@ type (
 		Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
 		QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
 		Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error)
+		Begin(ctx context.Context) (pgx.Tx, error)
+		Ping(ctx context.Context) error
 		Close()
 	}
 
@@ -28,11 +31,69 @@ type (
 	Storage interface {
 		AddNewSubs(ctx echo.Context, data dto.AddSubToDb) error
 		GetSubById(ctx echo.Context, data dto.GetSubFromWeb) (dto.GetSubFromDb, error)
-		GetListSub(ctx echo.Context) ([]dto.GetSubFromDb, error)
+		GetListSub(ctx echo.Context, data dto.GetSubListFromWeb) ([]dto.GetSubFromDb, error)
 		GetListSubByUser(ctx echo.Context, data dto.GetSubByUserFromWeb) ([]dto.GetSubFromDb, error)
 		GetPriceSubByFilter(ctx echo.Context, data dto.GetSubPriceByFilterToDb) (dto.GetSubPriceByFilterFromDb, error)
 		UpdateSubById(ctx echo.Context, data dto.UpdateSubToDb) error
 		DeleteSub(ctx echo.Context, data dto.GetSubFromWeb) error
+
+		AddNewUser(ctx echo.Context, data dto.AddUserToDb) (dto.GetUserFromDb, error)
+		GetUserById(ctx echo.Context, data dto.GetUserFromWeb) (dto.GetUserFromDb, error)
+		GetListUsers(ctx echo.Context) ([]dto.GetUserFromDb, error)
+		UpdateUserById(ctx echo.Context, data dto.UpdateUserToDb) error
+		DeleteUser(ctx echo.Context, data dto.GetUserFromWeb) error
+		GetActiveSubsByUser(ctx echo.Context, data dto.GetSubByUserFromWeb) ([]dto.GetSubFromDb, error)
+		GetUserReminders(ctx echo.Context, data dto.GetUserFromWeb) ([]dto.SubReminderFromDb, error)
+
+		AddBudget(ctx echo.Context, data dto.AddBudgetToDb) (dto.GetBudgetFromDb, error)
+		GetBudgetById(ctx echo.Context, data dto.GetBudgetFromWeb) (dto.GetBudgetFromDb, error)
+		GetBudgetsByUser(ctx echo.Context, data dto.GetUserFromWeb) ([]dto.GetBudgetFromDb, error)
+		UpdateBudgetById(ctx echo.Context, data dto.UpdateBudgetToDb) error
+		DeleteBudget(ctx echo.Context, data dto.GetBudgetFromWeb) error
+		GetBudgetAlerts(ctx echo.Context, data dto.GetUserFromWeb) ([]dto.BudgetAlertFromDb, error)
+
+		UpdateSubStatus(ctx echo.Context, data dto.UpdateSubStatusToDb) error
+		GetSubStatusHistory(ctx echo.Context, data dto.GetSubFromWeb) ([]dto.SubStatusHistoryFromDb, error)
+		GetSubPrices(ctx echo.Context, data dto.GetSubFromWeb) ([]dto.SubPriceFromDb, error)
+		GetUpcomingSubs(ctx echo.Context, data dto.GetUpcomingSubsToDb) (dto.GetUpcomingSubsFromDb, error)
+		FindSubOverlaps(ctx echo.Context, data dto.FindSubOverlapsToDb) ([]dto.GetSubFromDb, error)
+		GetSubOverlaps(ctx echo.Context, data dto.GetSubOverlapsFromWeb) ([]dto.SubOverlapFromDb, error)
+
+		GetSpendByMonth(ctx echo.Context, data dto.GetSpendAnalyticsToDb) ([]dto.SpendByMonthFromDb, error)
+		GetTopServices(ctx echo.Context, data dto.GetTopServicesToDb) ([]dto.TopServiceFromDb, error)
+		GetChurnByMonth(ctx echo.Context, data dto.GetChurnToDb) ([]dto.ChurnByMonthFromDb, error)
+
+		AddWebhook(ctx echo.Context, data dto.AddWebhookToDb) (dto.GetWebhookFromDb, error)
+		GetWebhookById(ctx echo.Context, data dto.GetWebhookFromWeb) (dto.GetWebhookFromDb, error)
+		GetListWebhooks(ctx echo.Context) ([]dto.GetWebhookFromDb, error)
+		UpdateWebhookById(ctx echo.Context, data dto.UpdateWebhookToDb) error
+		DeleteWebhook(ctx echo.Context, data dto.GetWebhookFromWeb) error
+		GetWebhookDeliveries(ctx echo.Context, data dto.GetWebhookDeliveriesFromWeb) ([]dto.WebhookDeliveryFromDb, error)
+		GetWebhookAttempts(ctx echo.Context, data dto.GetWebhookDeliveryFromWeb) ([]dto.WebhookAttemptFromDb, error)
+		RedeliverWebhook(ctx echo.Context, data dto.GetWebhookDeliveryFromWeb) error
+
+		AddAPIKey(ctx echo.Context, data dto.AddAPIKeyToDb) (dto.GetAPIKeyFromDb, error)
+		GetListAPIKeys(ctx echo.Context) ([]dto.GetAPIKeyFromDb, error)
+		RevokeAPIKey(ctx echo.Context, data dto.GetAPIKeyFromWeb) error
+		RotateAPIKey(ctx echo.Context, data dto.RotateAPIKeyToDb) (dto.GetAPIKeyFromDb, error)
+		UseAPIKey(ctx context.Context, keyHash string) (dto.APIKeyAuthFromDb, error)
+		TakeRateLimit(ctx context.Context, data dto.RateLimitToDb) (dto.RateLimitFromDb, error)
+		PeekRateLimit(ctx context.Context, data dto.RateLimitToDb) (dto.RateLimitFromDb, error)
+		PurgeRateLimits(ctx context.Context) (int, error)
+
+		Ping(ctx context.Context) error
+		GetSchemaVersion(ctx context.Context) (int64, error)
+		CountSubsByStatus(ctx context.Context) ([]dto.SubStatusCountFromDb, error)
+
+		RenewSubs(ctx context.Context, data dto.RenewSubsToDb) (int, error)
+		ExpireSubs(ctx context.Context, data dto.ExpireSubsToDb) (int, error)
+		EndTrials(ctx context.Context, data dto.ExpireSubsToDb) (int, error)
+		RelayOutbox(ctx context.Context, limit int, publish func(dto.OutboxEventFromDb) dto.OutboxAttemptToDb) (int, error)
+		EnqueueWebhookDeliveries(ctx context.Context, data dto.EnqueueWebhookToDb) (int, error)
+		DeliverWebhooks(ctx context.Context, limit int, deliver func(dto.WebhookDeliveryJob) dto.WebhookAttemptToDb) (int, error)
+		EvaluateBudgets(ctx context.Context) (int, error)
+		EvaluateDirtyBudgets(ctx context.Context, limit int) (int, error)
+		SendReminders(ctx context.Context, data dto.DueRemindersToDb, notify func(dto.SubReminderJob) dto.SubReminderToDb) (int, error)
 	}
 )
 
@@ -42,6 +103,79 @@ func NewDatabase(client Client) Storage {
 	}
 }
 
+const subColumns = `id,
+	service_name,
+	category,
+	price,
+	user_id,
+	start_date,
+	end_date,
+	billing_period,
+	auto_renew,
+	status,
+	status_changed_at,
+	trial_months,
+	discount_type,
+	discount_value,
+	discount_months`
+
+func scanSub(row pgx.Row) (dto.GetSubFromDb, error) {
+	var out dto.GetSubFromDb
+	err := row.Scan(
+		&out.Id,
+		&out.ServiceName,
+		&out.Category,
+		&out.Price,
+		&out.UserId,
+		&out.StartDate,
+		&out.EndDate,
+		&out.BillingPeriod,
+		&out.AutoRenew,
+		&out.Status,
+		&out.StatusChangedAt,
+		&out.TrialMonths,
+		&out.DiscountType,
+		&out.DiscountValue,
+		&out.DiscountMonths)
+	return out, err
+}
+
+func collectSubs(rows pgx.Rows) ([]dto.GetSubFromDb, error) {
+	defer rows.Close()
+	var out []dto.GetSubFromDb
+	for rows.Next() {
+		data, err := scanSub(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%w", err)
+		}
+		out = append(out, data)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+	return out, nil
+}
+
+func (r *Repository) inTx(ctx context.Context, fn func(tx pgx.Tx) error) error {
+	tx, err := r.Client.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+	if err := fn(tx); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// tenantOf returns the tenant every query of a request is scoped to.
+func tenantOf(ctx echo.Context) string {
+	return tenant.FromContext(ctx.Request().Context())
+}
+
 func StructToNamedArgs(s any) (pgx.NamedArgs, error) {
 	args := pgx.NamedArgs{}
 	v := reflect.ValueOf(s)
@@ -63,25 +197,69 @@ func StructToNamedArgs(s any) (pgx.NamedArgs, error) {
 func (r *Repository) AddNewSubs(ctx echo.Context, data dto.AddSubToDb) error {
 	query := `INSERT INTO subs (
 	service_name,
+	category,
 	price,
 	user_id,
 	start_date,
-	end_date) VALUES (
+	end_date,
+	billing_period,
+	auto_renew,
+	status,
+	trial_months,
+	discount_type,
+	discount_value,
+	discount_months,
+	tenant_id) VALUES (
 	@service_name,
+	@category,
 	@price,
 	@user_id,
 	@start_date,
-	@end_date)`
+	@end_date,
+	@billing_period,
+	@auto_renew,
+	@status,
+	@trial_months,
+	@discount_type,
+	@discount_value,
+	@discount_months,
+	@tenant_id)
+	RETURNING ` + subColumns
 
 	args, err := StructToNamedArgs(data)
 	if err != nil {
 		return fmt.Errorf("%w", err)
 	}
-	_, err = r.Client.Exec(ctx.Request().Context(), query, args)
-	if err != nil {
-		return fmt.Errorf("%w", err)
-	}
-	return nil
+	args["tenant_id"] = tenantOf(ctx)
+	return r.inTx(ctx.Request().Context(), func(tx pgx.Tx) error {
+		if data.RejectOverlaps {
+			if err := lockSubUsers(ctx.Request().Context(), tx, 0, data.UserId); err != nil {
+				return err
+			}
+		}
+		sub, err := scanSub(tx.QueryRow(ctx.Request().Context(), query, args))
+		if err != nil {
+			if isPgError(err, pgForeignKeyViolation) {
+				return fmt.Errorf("user with id %s not found", data.UserId)
+			}
+			if isPgError(err, pgExclusionViolation) {
+				return fmt.Errorf("user already has an overlapping %s sub", data.ServiceName)
+			}
+			return fmt.Errorf("%w", err)
+		}
+		if data.RejectOverlaps {
+			if err := rejectSubOverlaps(ctx.Request().Context(), tx, sub.Id); err != nil {
+				return err
+			}
+		}
+		if err := addSubPrice(ctx.Request().Context(), tx, sub.Id, data.Price, data.StartDate); err != nil {
+			return err
+		}
+		if err := writeOutbox(ctx.Request().Context(), tx, EventSubCreated, subSubject(sub.Id), sub); err != nil {
+			return err
+		}
+		return markBudgetsDirty(ctx.Request().Context(), tx, sub.UserId)
+	})
 }
 
 // func (r *Repository) AddNewSubs(ctx echo.Context, data dto.AddSubToDb) error {
@@ -108,107 +286,53 @@ func (r *Repository) GetSubById(ctx echo.Context, data dto.GetSubFromWeb) (dto.G
 	if data.Id <= 0 {
 		return dto.GetSubFromDb{}, fmt.Errorf("invalid sub ID: %d", data.Id)
 	}
-	query := `SELECT
-	id,
-	service_name,
-	price,
-	user_id,
-	start_date,
-	end_date 
+	query := `SELECT ` + subColumns + `
 	FROM subs
-	WHERE id = $1`
-	row := r.Client.QueryRow(ctx.Request().Context(), query, data.Id)
-	var out dto.GetSubFromDb
-	if err := row.Scan(
-		&out.Id,
-		&out.ServiceName,
-		&out.Price,
-		&out.UserId,
-		&out.StartDate,
-		&out.EndDate); err != nil {
+	WHERE id = $1 AND tenant_id = $2`
+	out, err := scanSub(r.Client.QueryRow(ctx.Request().Context(), query, data.Id, tenantOf(ctx)))
+	if err != nil {
 		return dto.GetSubFromDb{}, fmt.Errorf("%w", err)
 	}
 	return out, nil
 }
 
-func (r *Repository) GetListSub(ctx echo.Context) ([]dto.GetSubFromDb, error) {
-	query := `SELECT
-	id,
-	service_name,
-	price,
-	user_id,
-	start_date,
-	end_date 
-	FROM subs`
-	rows, err := r.Client.Query(ctx.Request().Context(), query)
+func (r *Repository) GetListSub(ctx echo.Context, data dto.GetSubListFromWeb) ([]dto.GetSubFromDb, error) {
+	query := `SELECT ` + subColumns + `
+	FROM subs
+	WHERE tenant_id = $1 AND (cardinality($2::TEXT[]) = 0 OR status = ANY($2))`
+	rows, err := r.Client.Query(ctx.Request().Context(), query, tenantOf(ctx), statusFilter(data.Status))
 	if err != nil {
 		return nil, fmt.Errorf("%w", err)
 	}
-	defer rows.Close()
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("%w", err)
-	}
-	var out []dto.GetSubFromDb
-	for rows.Next() {
-		var data dto.GetSubFromDb
-		if err := rows.Scan(
-			&data.Id,
-			&data.ServiceName,
-			&data.Price,
-			&data.UserId,
-			&data.StartDate,
-			&data.EndDate); err != nil {
-			return nil, fmt.Errorf("%w", err)
-		}
-		out = append(out, data)
-	}
-	return out, nil
+	return collectSubs(rows)
 }
 
 func (r *Repository) GetListSubByUser(ctx echo.Context, data dto.GetSubByUserFromWeb) ([]dto.GetSubFromDb, error) {
 	if data.UserId == "" {
 		return nil, fmt.Errorf("invalid user ID: %s", data.UserId)
 	}
-	query := `SELECT
-	id,
-	service_name,
-	price,
-	user_id,
-	start_date,
-	end_date 
+	query := `SELECT ` + subColumns + `
 	FROM subs
-	WHERE user_id = $1`
-	rows, err := r.Client.Query(ctx.Request().Context(), query, data.UserId)
+	WHERE user_id = $1 AND tenant_id = $2 AND (cardinality($3::TEXT[]) = 0 OR status = ANY($3))`
+	rows, err := r.Client.Query(ctx.Request().Context(), query, data.UserId, tenantOf(ctx), statusFilter(data.Status))
 	if err != nil {
 		return nil, fmt.Errorf("%w", err)
 	}
-	defer rows.Close()
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("%w", err)
-	}
-	var out []dto.GetSubFromDb
-	for rows.Next() {
-		var data dto.GetSubFromDb
-		if err := rows.Scan(
-			&data.Id,
-			&data.ServiceName,
-			&data.Price,
-			&data.UserId,
-			&data.StartDate,
-			&data.EndDate); err != nil {
-			return nil, fmt.Errorf("%w", err)
-		}
-		out = append(out, data)
-	}
-	return out, nil
+	return collectSubs(rows)
 }
 
 func (r *Repository) GetPriceSubByFilter(ctx echo.Context, data dto.GetSubPriceByFilterToDb) (dto.GetSubPriceByFilterFromDb, error) {
-	query := `SELECT
-	SUM(price)
-	FROM subs
-	WHERE service_name = $1 AND user_id = $2 AND start_date BETWEEN $3 AND $4`
-	row := r.Client.QueryRow(ctx.Request().Context(), query, data.ServiceName, data.UserId, data.StartDate, data.EndDate)
+	query := `WITH costs AS (` + subMonthlyCosts + `)
+	SELECT
+	ROUND(COALESCE(SUM(cost), 0))::BIGINT
+	FROM costs
+	WHERE service_name = @service_name AND user_id = @user_id`
+	args, err := StructToNamedArgs(data)
+	if err != nil {
+		return dto.GetSubPriceByFilterFromDb{}, fmt.Errorf("%w", err)
+	}
+	args["tenant_id"] = tenantOf(ctx)
+	row := r.Client.QueryRow(ctx.Request().Context(), query, args)
 	var out dto.GetSubPriceByFilterFromDb
 	if err := row.Scan(&out.Price); err != nil {
 		return dto.GetSubPriceByFilterFromDb{}, fmt.Errorf("%w", err)
@@ -228,11 +352,18 @@ func (r *Repository) UpdateSubById(ctx echo.Context, data dto.UpdateSubToDb) err
 		args = append(args, data.ServiceName)
 		argID++
 	}
-	if data.Price != 0 {
-		setClauses = append(setClauses, fmt.Sprintf("price = $%d", argID))
-		args = append(args, data.Price)
+	if data.Category != "" {
+		setClauses = append(setClauses, fmt.Sprintf("category = $%d", argID))
+		args = append(args, data.Category)
 		argID++
 	}
+	if data.Price != 0 {
+		// A price that takes effect in a later month only goes into the
+		// price history; subs.price keeps the price in effect now.
+		setClauses = append(setClauses, fmt.Sprintf("price = CASE WHEN date_trunc('month', $%d::TIMESTAMPTZ) <= now() THEN $%d ELSE price END", argID, argID+1))
+		args = append(args, data.PriceFrom, data.Price)
+		argID += 2
+	}
 	if data.UserId != "" {
 		setClauses = append(setClauses, fmt.Sprintf("user_id = $%d", argID))
 		args = append(args, data.UserId)
@@ -248,33 +379,107 @@ func (r *Repository) UpdateSubById(ctx echo.Context, data dto.UpdateSubToDb) err
 		args = append(args, data.EndDate)
 		argID++
 	}
+	if data.AutoRenew != nil {
+		setClauses = append(setClauses, fmt.Sprintf("auto_renew = $%d", argID))
+		args = append(args, *data.AutoRenew)
+		argID++
+	}
+	if data.TrialMonths != nil {
+		setClauses = append(setClauses, fmt.Sprintf("trial_months = $%d", argID))
+		args = append(args, *data.TrialMonths)
+		argID++
+	}
+	if data.DiscountType != nil {
+		setClauses = append(setClauses, fmt.Sprintf("discount_type = $%d", argID))
+		args = append(args, *data.DiscountType)
+		argID++
+	}
+	if data.DiscountValue != nil {
+		setClauses = append(setClauses, fmt.Sprintf("discount_value = $%d", argID))
+		args = append(args, *data.DiscountValue)
+		argID++
+	}
+	if data.DiscountMonths != nil {
+		setClauses = append(setClauses, fmt.Sprintf("discount_months = $%d", argID))
+		args = append(args, *data.DiscountMonths)
+		argID++
+	}
 	if len(setClauses) == 0 {
 		return fmt.Errorf("no fields to update for sub with id %d", data.Id)
 	}
-	query := fmt.Sprintf("UPDATE subs SET %s WHERE id = $%d RETURNING id", strings.Join(setClauses, ", "), argID)
-	args = append(args, data.Id)
-	var updatedID int
-	err := r.Client.QueryRow(ctx.Request().Context(), query, args...).Scan(&updatedID)
-	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return fmt.Errorf("sub with id %d not found", data.Id)
+	query := fmt.Sprintf("UPDATE subs SET %s WHERE id = $%d AND tenant_id = $%d RETURNING %s", strings.Join(setClauses, ", "), argID, argID+1, subColumns)
+	args = append(args, data.Id, tenantOf(ctx))
+	return r.inTx(ctx.Request().Context(), func(tx pgx.Tx) error {
+		if data.RejectOverlaps {
+			if err := lockSubUsers(ctx.Request().Context(), tx, data.Id, data.UserId); err != nil {
+				return err
+			}
 		}
-		return fmt.Errorf("failed to update sub: %w", err)
-	}
-	return nil
+		// A sub moved to another user changes the spend of both owners.
+		var previousUserId string
+		if data.UserId != "" {
+			err := tx.QueryRow(ctx.Request().Context(), `SELECT user_id FROM subs WHERE id = $1 AND tenant_id = $2 FOR UPDATE`,
+				data.Id, tenantOf(ctx)).Scan(&previousUserId)
+			if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+				return fmt.Errorf("failed to update sub: %w", err)
+			}
+		}
+		sub, err := scanSub(tx.QueryRow(ctx.Request().Context(), query, args...))
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return fmt.Errorf("sub with id %d not found", data.Id)
+			}
+			if isPgError(err, pgForeignKeyViolation) {
+				return fmt.Errorf("user with id %s not found", data.UserId)
+			}
+			if isPgError(err, pgExclusionViolation) {
+				return fmt.Errorf("sub with id %d would overlap another sub of the same service", data.Id)
+			}
+			return fmt.Errorf("failed to update sub: %w", err)
+		}
+		if data.RejectOverlaps {
+			if err := rejectSubOverlaps(ctx.Request().Context(), tx, sub.Id); err != nil {
+				return err
+			}
+		}
+		if !data.StartDate.IsZero() {
+			if err := moveFirstSubPrice(ctx.Request().Context(), tx, data.Id, data.StartDate); err != nil {
+				return err
+			}
+		}
+		if data.Price != 0 {
+			if err := addSubPrice(ctx.Request().Context(), tx, data.Id, data.Price, data.PriceFrom); err != nil {
+				return err
+			}
+		}
+		if err := writeOutbox(ctx.Request().Context(), tx, EventSubUpdated, subSubject(sub.Id), sub); err != nil {
+			return err
+		}
+		if previousUserId != "" && previousUserId != sub.UserId {
+			if err := markBudgetsDirty(ctx.Request().Context(), tx, previousUserId); err != nil {
+				return err
+			}
+		}
+		return markBudgetsDirty(ctx.Request().Context(), tx, sub.UserId)
+	})
 }
 
 func (r *Repository) DeleteSub(ctx echo.Context, data dto.GetSubFromWeb) error {
 	if data.Id <= 0 {
 		return fmt.Errorf("invalid sub ID: %d", data.Id)
 	}
-	query := `DELETE FROM subs WHERE id = $1`
-	res, err := r.Client.Exec(ctx.Request().Context(), query, data.Id)
-	if err != nil {
-		return fmt.Errorf("failed to delete sub: %w", err)
-	}
-	if res.RowsAffected() == 0 {
-		return fmt.Errorf("sub with id %d not found", data.Id)
-	}
-	return nil
+	query := `DELETE FROM subs WHERE id = $1 AND tenant_id = $2 RETURNING ` + subColumns
+	return r.inTx(ctx.Request().Context(), func(tx pgx.Tx) error {
+		sub, err := scanSub(tx.QueryRow(ctx.Request().Context(), query, data.Id, tenantOf(ctx)))
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return fmt.Errorf("sub with id %d not found", data.Id)
+			}
+			return fmt.Errorf("failed to delete sub: %w", err)
+		}
+		if err := writeOutbox(ctx.Request().Context(), tx, EventSubDeleted, subSubject(sub.Id), sub); err != nil {
+			return err
+		}
+		return markBudgetsDirty(ctx.Request().Context(), tx, sub.UserId)
+	})
 }
@@ -0,0 +1,216 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"service/internal/dto"
+	"service/internal/tenant"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/labstack/echo/v4"
+)
+
+func statusFilter(status []string) []string {
+	if status == nil {
+		return []string{}
+	}
+	return status
+}
+
+func setSubStatus(ctx context.Context, tx pgx.Tx, data dto.UpdateSubStatusToDb) error {
+	query := `UPDATE subs SET
+	status = $1,
+	status_changed_at = now()
+	WHERE id = $2 AND status = $3 AND tenant_id = $4`
+	res, err := tx.Exec(ctx, query, data.ToStatus, data.Id, data.FromStatus, tenant.FromContext(ctx))
+	if err != nil {
+		if isPgError(err, pgExclusionViolation) {
+			return fmt.Errorf("sub with id %d would overlap another sub of the same service", data.Id)
+		}
+		return fmt.Errorf("failed to update sub status: %w", err)
+	}
+	if res.RowsAffected() == 0 {
+		return fmt.Errorf("sub with id %d is not %s anymore", data.Id, data.FromStatus)
+	}
+	query = `INSERT INTO sub_status_history (
+	sub_id,
+	from_status,
+	to_status) VALUES ($1, $2, $3)`
+	if _, err := tx.Exec(ctx, query, data.Id, data.FromStatus, data.ToStatus); err != nil {
+		return fmt.Errorf("failed to record sub status: %w", err)
+	}
+	event := dto.SubStatusChangedEvent{
+		Id:         data.Id,
+		FromStatus: data.FromStatus,
+		ToStatus:   data.ToStatus,
+	}
+	if err := writeOutbox(ctx, tx, EventSubStatusChanged, subSubject(data.Id), event); err != nil {
+		return err
+	}
+	switch {
+	case data.ToStatus == "paused":
+		query = `INSERT INTO sub_pauses (sub_id) VALUES ($1)`
+	case data.FromStatus == "paused" && data.ToStatus == "active":
+		query = `WITH closed AS (
+	UPDATE sub_pauses SET
+	resumed_at = now()
+	WHERE sub_id = $1 AND resumed_at IS NULL
+	RETURNING paused_at
+	)
+	UPDATE subs SET
+	end_date = subs.end_date + (now() - closed.paused_at)
+	FROM closed
+	WHERE subs.id = $1`
+	case data.FromStatus == "paused":
+		query = `UPDATE sub_pauses SET
+	resumed_at = now()
+	WHERE sub_id = $1 AND resumed_at IS NULL`
+	default:
+		return nil
+	}
+	if _, err := tx.Exec(ctx, query, data.Id); err != nil {
+		return fmt.Errorf("failed to record sub pause: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) UpdateSubStatus(ctx echo.Context, data dto.UpdateSubStatusToDb) error {
+	if data.Id <= 0 {
+		return fmt.Errorf("invalid sub ID: %d", data.Id)
+	}
+	return r.inTx(ctx.Request().Context(), func(tx pgx.Tx) error {
+		if err := setSubStatus(ctx.Request().Context(), tx, data); err != nil {
+			return err
+		}
+		var userId *string
+		if err := tx.QueryRow(ctx.Request().Context(), `SELECT user_id FROM subs WHERE id = $1 AND tenant_id = $2`, data.Id, tenantOf(ctx)).Scan(&userId); err != nil {
+			return fmt.Errorf("%w", err)
+		}
+		if userId == nil {
+			return nil
+		}
+		return markBudgetsDirty(ctx.Request().Context(), tx, *userId)
+	})
+}
+
+func (r *Repository) GetSubStatusHistory(ctx echo.Context, data dto.GetSubFromWeb) ([]dto.SubStatusHistoryFromDb, error) {
+	if data.Id <= 0 {
+		return nil, fmt.Errorf("invalid sub ID: %d", data.Id)
+	}
+	query := `SELECT
+	h.id,
+	h.sub_id,
+	h.from_status,
+	h.to_status,
+	h.changed_at
+	FROM sub_status_history h
+	JOIN subs s ON s.id = h.sub_id
+	WHERE h.sub_id = $1 AND s.tenant_id = $2
+	ORDER BY h.changed_at`
+	rows, err := r.Client.Query(ctx.Request().Context(), query, data.Id, tenantOf(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+	defer rows.Close()
+	var out []dto.SubStatusHistoryFromDb
+	for rows.Next() {
+		var data dto.SubStatusHistoryFromDb
+		if err := rows.Scan(
+			&data.Id,
+			&data.SubId,
+			&data.FromStatus,
+			&data.ToStatus,
+			&data.ChangedAt); err != nil {
+			return nil, fmt.Errorf("%w", err)
+		}
+		out = append(out, data)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+	return out, nil
+}
+
+func (r *Repository) ExpireSubs(ctx context.Context, data dto.ExpireSubsToDb) (int, error) {
+	var expired int
+	err := r.inTx(ctx, func(tx pgx.Tx) error {
+		query := `WITH due AS (
+	SELECT id, status
+	FROM subs
+	WHERE status IN ('trial', 'active') AND NOT auto_renew AND end_date <= $1
+	ORDER BY end_date
+	LIMIT $2
+	FOR UPDATE SKIP LOCKED
+	), updated AS (
+	UPDATE subs SET
+	status = 'expired',
+	status_changed_at = now()
+	FROM due
+	WHERE subs.id = due.id
+	RETURNING subs.id, subs.tenant_id, subs.user_id, due.status
+	), history AS (
+	INSERT INTO sub_status_history (sub_id, from_status, to_status)
+	SELECT id, status, 'expired' FROM updated
+	), dirty AS (
+	INSERT INTO budget_dirty_users (tenant_id, user_id)
+	SELECT DISTINCT u.tenant_id, u.user_id FROM updated u
+	WHERE EXISTS (SELECT 1 FROM budgets WHERE user_id = u.user_id)
+	ON CONFLICT (user_id) DO NOTHING
+	)
+	INSERT INTO outbox (tenant_id, event_type, subject, data)
+	SELECT tenant_id, $3, 'subs/' || id, jsonb_build_object('id', id, 'from_status', status, 'to_status', 'expired')
+	FROM updated`
+		res, err := tx.Exec(ctx, query, data.Before, data.Limit, EventSubExpired)
+		if err != nil {
+			return fmt.Errorf("failed to expire subs: %w", err)
+		}
+		expired = int(res.RowsAffected())
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return expired, nil
+}
+
+func (r *Repository) EndTrials(ctx context.Context, data dto.ExpireSubsToDb) (int, error) {
+	var ended int
+	err := r.inTx(ctx, func(tx pgx.Tx) error {
+		query := `WITH due AS (
+	SELECT id
+	FROM subs
+	WHERE status = 'trial' AND trial_months > 0
+	AND date_trunc('month', start_date) + make_interval(months => trial_months) <= $1
+	LIMIT $2
+	FOR UPDATE SKIP LOCKED
+	), updated AS (
+	UPDATE subs SET
+	status = 'active',
+	status_changed_at = now()
+	FROM due
+	WHERE subs.id = due.id
+	RETURNING subs.id, subs.tenant_id, subs.user_id
+	), history AS (
+	INSERT INTO sub_status_history (sub_id, from_status, to_status)
+	SELECT id, 'trial', 'active' FROM updated
+	), dirty AS (
+	INSERT INTO budget_dirty_users (tenant_id, user_id)
+	SELECT DISTINCT u.tenant_id, u.user_id FROM updated u
+	WHERE EXISTS (SELECT 1 FROM budgets WHERE user_id = u.user_id)
+	ON CONFLICT (user_id) DO NOTHING
+	)
+	INSERT INTO outbox (tenant_id, event_type, subject, data)
+	SELECT tenant_id, $3, 'subs/' || id, jsonb_build_object('id', id, 'from_status', 'trial', 'to_status', 'active')
+	FROM updated`
+		res, err := tx.Exec(ctx, query, data.Before, data.Limit, EventSubStatusChanged)
+		if err != nil {
+			return fmt.Errorf("failed to end trials: %w", err)
+		}
+		ended = int(res.RowsAffected())
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return ended, nil
+}
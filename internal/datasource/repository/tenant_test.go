@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"service/internal/dto"
+	"service/internal/tenant"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func TestTenantIsolation(t *testing.T) {
+	pool := newTestPool(t)
+	repo := NewDatabase(pool)
+	ownId := addTestUser(t, pool, "a")
+	otherId := addTestUser(t, pool, "b")
+	ctx := newTestContext("a")
+
+	users, err := repo.GetListUsers(ctx)
+	if err != nil {
+		t.Fatalf("failed to list users: %v", err)
+	}
+	if len(users) != 1 || users[0].Id != ownId {
+		t.Fatalf("tenant a lists %+v, want only %s", users, ownId)
+	}
+	if _, err := repo.GetUserById(ctx, dto.GetUserFromWeb{Id: otherId}); err == nil {
+		t.Fatal("tenant a reads a user of tenant b")
+	}
+	if err := repo.DeleteUser(ctx, dto.GetUserFromWeb{Id: otherId}); err == nil {
+		t.Fatal("tenant a deletes a user of tenant b")
+	}
+}
+
+// TestTenantUserReferences checks that rows of one tenant cannot reference a
+// user of another: the foreign keys to users include tenant_id.
+func TestTenantUserReferences(t *testing.T) {
+	pool := newTestPool(t)
+	repo := NewDatabase(pool)
+	addTestUser(t, pool, "a")
+	otherId := addTestUser(t, pool, "b")
+	ctx := newTestContext("a")
+
+	sub := dto.AddSubToDb{
+		ServiceName: "YandexGold",
+		Price:       500,
+		UserId:      otherId,
+		StartDate:   time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:     time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC),
+		Status:      "active",
+	}
+	if err := repo.AddNewSubs(ctx, sub); err == nil {
+		t.Fatal("tenant a adds a sub for a user of tenant b")
+	}
+	if _, err := repo.AddBudget(ctx, dto.AddBudgetToDb{UserId: otherId, Amount: 1000}); err == nil {
+		t.Fatal("tenant a adds a budget for a user of tenant b")
+	}
+	if _, err := repo.AddAPIKey(ctx, dto.AddAPIKeyToDb{Name: "test", Prefix: "sk_test", KeyHash: "test", UserId: &otherId}); err == nil {
+		t.Fatal("tenant a adds an api key for a user of tenant b")
+	}
+	if _, err := pool.Exec(context.Background(),
+		`INSERT INTO budget_dirty_users (tenant_id, user_id) VALUES ('a', $1)`, otherId); err == nil {
+		t.Fatal("a dirty user row of tenant a references a user of tenant b")
+	}
+}
+
+// newTenantPool applies the row-level security migrations to the schema of
+// pool and returns a pool of the same schema whose connections run as a role
+// without BYPASSRLS and are bound to the tenant of the acquiring context.
+func newTenantPool(t *testing.T, pool *pgxpool.Pool) *pgxpool.Pool {
+	t.Helper()
+	ctx := context.Background()
+	migrate(t, pool, "../../../migrations/rls")
+
+	var schema string
+	if err := pool.QueryRow(ctx, `SELECT current_schema()`).Scan(&schema); err != nil {
+		t.Fatalf("failed to get schema: %v", err)
+	}
+	role := schema + "_app"
+	if _, err := pool.Exec(ctx, `CREATE ROLE `+role+` NOLOGIN NOBYPASSRLS`); err != nil {
+		t.Skipf("cannot create a role for row-level security: %v", err)
+	}
+	t.Cleanup(func() {
+		pool.Exec(context.Background(), `DROP OWNED BY `+role)
+		pool.Exec(context.Background(), `DROP ROLE `+role)
+	})
+	var user string
+	if err := pool.QueryRow(ctx, `SELECT current_user`).Scan(&user); err != nil {
+		t.Fatalf("failed to get user: %v", err)
+	}
+	for _, query := range []string{
+		`GRANT ` + role + ` TO "` + user + `"`,
+		`GRANT USAGE ON SCHEMA ` + schema + ` TO ` + role,
+		`GRANT SELECT, INSERT, UPDATE, DELETE ON ALL TABLES IN SCHEMA ` + schema + ` TO ` + role,
+		`GRANT USAGE ON ALL SEQUENCES IN SCHEMA ` + schema + ` TO ` + role,
+	} {
+		if _, err := pool.Exec(ctx, query); err != nil {
+			t.Fatalf("failed to grant %s: %v", role, err)
+		}
+	}
+
+	cfg, err := pgxpool.ParseConfig(os.Getenv(testDatabaseEnv))
+	if err != nil {
+		t.Fatalf("failed to parse %s: %v", testDatabaseEnv, err)
+	}
+	cfg.ConnConfig.RuntimeParams["search_path"] = schema + ",public"
+	cfg.AfterConnect = func(ctx context.Context, conn *pgx.Conn) error {
+		_, err := conn.Exec(ctx, `SET ROLE `+role)
+		return err
+	}
+	tenant.BindPool(cfg)
+	app, err := pgxpool.NewWithConfig(ctx, cfg)
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	t.Cleanup(app.Close)
+	return app
+}
+
+func TestTenantRLS(t *testing.T) {
+	pool := newTestPool(t)
+	ownId := addTestUser(t, pool, "a")
+	addTestUser(t, pool, "b")
+	app := newTenantPool(t, pool)
+
+	tenantA := tenant.WithTenant(context.Background(), "a")
+	count := func(ctx context.Context, table string) int {
+		t.Helper()
+		var n int
+		if err := app.QueryRow(ctx, `SELECT count(*) FROM `+table).Scan(&n); err != nil {
+			t.Fatalf("failed to count %s: %v", table, err)
+		}
+		return n
+	}
+
+	if n := count(tenantA, "users"); n != 1 {
+		t.Fatalf("tenant a sees %d users, want 1", n)
+	}
+	if n := count(context.Background(), "users"); n != 0 {
+		t.Fatalf("connection without a tenant sees %d users, want 0", n)
+	}
+	if n := count(tenant.WithTenant(context.Background(), ""), "users"); n != 0 {
+		t.Fatalf("empty tenant sees %d users, want 0", n)
+	}
+	if _, err := app.Exec(tenantA, `INSERT INTO users (display_name, tenant_id) VALUES ('test', 'b')`); err == nil {
+		t.Fatal("tenant a inserts a user of tenant b")
+	}
+
+	if _, err := app.Exec(tenantA, `INSERT INTO budget_dirty_users (tenant_id, user_id) VALUES ('a', $1)`, ownId); err != nil {
+		t.Fatalf("failed to mark budgets dirty: %v", err)
+	}
+	if n := count(tenant.WithTenant(context.Background(), "b"), "budget_dirty_users"); n != 0 {
+		t.Fatalf("tenant b sees %d dirty users, want 0", n)
+	}
+
+}
@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"fmt"
+	"service/internal/dto"
+
+	"github.com/labstack/echo/v4"
+)
+
+func (r *Repository) GetUpcomingSubs(ctx echo.Context, data dto.GetUpcomingSubsToDb) (dto.GetUpcomingSubsFromDb, error) {
+	filter := `FROM subs
+	WHERE tenant_id = @tenant_id AND end_date >= @from AND end_date < @to
+	AND status = ANY(@status)
+	AND (@user_id = '' OR user_id::TEXT = @user_id)
+	AND (@service_name = '' OR service_name = @service_name)`
+	args, err := StructToNamedArgs(data)
+	if err != nil {
+		return dto.GetUpcomingSubsFromDb{}, fmt.Errorf("%w", err)
+	}
+	args["tenant_id"] = tenantOf(ctx)
+	out := dto.GetUpcomingSubsFromDb{
+		Limit:  data.Limit,
+		Offset: data.Offset,
+	}
+	if err := r.Client.QueryRow(ctx.Request().Context(), `SELECT COUNT(*) `+filter, args).Scan(&out.Total); err != nil {
+		return dto.GetUpcomingSubsFromDb{}, fmt.Errorf("%w", err)
+	}
+	query := `SELECT ` + subColumns + `
+	` + filter + `
+	ORDER BY end_date, id
+	LIMIT @limit OFFSET @offset`
+	rows, err := r.Client.Query(ctx.Request().Context(), query, args)
+	if err != nil {
+		return dto.GetUpcomingSubsFromDb{}, fmt.Errorf("%w", err)
+	}
+	if out.Items, err = collectSubs(rows); err != nil {
+		return dto.GetUpcomingSubsFromDb{}, err
+	}
+	return out, nil
+}
@@ -0,0 +1,200 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"service/internal/dto"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	pgUniqueViolation     = "23505"
+	pgForeignKeyViolation = "23503"
+)
+
+func isPgError(err error, code string) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == code
+}
+
+func (r *Repository) AddNewUser(ctx echo.Context, data dto.AddUserToDb) (dto.GetUserFromDb, error) {
+	query := `INSERT INTO users (
+	display_name,
+	email,
+	timezone,
+	status,
+	tenant_id) VALUES (
+	@display_name,
+	@email,
+	@timezone,
+	@status,
+	@tenant_id)
+	RETURNING id, display_name, email, timezone, status, reminders_enabled, created_at`
+
+	args, err := StructToNamedArgs(data)
+	if err != nil {
+		return dto.GetUserFromDb{}, fmt.Errorf("%w", err)
+	}
+	args["tenant_id"] = tenantOf(ctx)
+	var out dto.GetUserFromDb
+	if err := r.Client.QueryRow(ctx.Request().Context(), query, args).Scan(
+		&out.Id,
+		&out.DisplayName,
+		&out.Email,
+		&out.Timezone,
+		&out.Status,
+		&out.Reminders,
+		&out.CreatedAt); err != nil {
+		if isPgError(err, pgUniqueViolation) {
+			return dto.GetUserFromDb{}, fmt.Errorf("user with email %s already exists", *data.Email)
+		}
+		return dto.GetUserFromDb{}, fmt.Errorf("%w", err)
+	}
+	return out, nil
+}
+
+func (r *Repository) GetUserById(ctx echo.Context, data dto.GetUserFromWeb) (dto.GetUserFromDb, error) {
+	query := `SELECT
+	id,
+	display_name,
+	email,
+	timezone,
+	status,
+	reminders_enabled,
+	created_at
+	FROM users
+	WHERE id = $1 AND tenant_id = $2`
+	row := r.Client.QueryRow(ctx.Request().Context(), query, data.Id, tenantOf(ctx))
+	var out dto.GetUserFromDb
+	if err := row.Scan(
+		&out.Id,
+		&out.DisplayName,
+		&out.Email,
+		&out.Timezone,
+		&out.Status,
+		&out.Reminders,
+		&out.CreatedAt); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return dto.GetUserFromDb{}, fmt.Errorf("user with id %s not found", data.Id)
+		}
+		return dto.GetUserFromDb{}, fmt.Errorf("%w", err)
+	}
+	return out, nil
+}
+
+func (r *Repository) GetListUsers(ctx echo.Context) ([]dto.GetUserFromDb, error) {
+	query := `SELECT
+	id,
+	display_name,
+	email,
+	timezone,
+	status,
+	reminders_enabled,
+	created_at
+	FROM users
+	WHERE tenant_id = $1
+	ORDER BY created_at`
+	rows, err := r.Client.Query(ctx.Request().Context(), query, tenantOf(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+	defer rows.Close()
+	var out []dto.GetUserFromDb
+	for rows.Next() {
+		var data dto.GetUserFromDb
+		if err := rows.Scan(
+			&data.Id,
+			&data.DisplayName,
+			&data.Email,
+			&data.Timezone,
+			&data.Status,
+			&data.Reminders,
+			&data.CreatedAt); err != nil {
+			return nil, fmt.Errorf("%w", err)
+		}
+		out = append(out, data)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+	return out, nil
+}
+
+func (r *Repository) UpdateUserById(ctx echo.Context, data dto.UpdateUserToDb) error {
+	var setClauses []string
+	var args []interface{}
+	argID := 1
+	if data.DisplayName != "" {
+		setClauses = append(setClauses, fmt.Sprintf("display_name = $%d", argID))
+		args = append(args, data.DisplayName)
+		argID++
+	}
+	if data.Email != "" {
+		setClauses = append(setClauses, fmt.Sprintf("email = $%d", argID))
+		args = append(args, data.Email)
+		argID++
+	}
+	if data.Timezone != "" {
+		setClauses = append(setClauses, fmt.Sprintf("timezone = $%d", argID))
+		args = append(args, data.Timezone)
+		argID++
+	}
+	if data.Status != "" {
+		setClauses = append(setClauses, fmt.Sprintf("status = $%d", argID))
+		args = append(args, data.Status)
+		argID++
+	}
+	if data.Reminders != nil {
+		setClauses = append(setClauses, fmt.Sprintf("reminders_enabled = $%d", argID))
+		args = append(args, *data.Reminders)
+		argID++
+	}
+	if len(setClauses) == 0 {
+		return fmt.Errorf("no fields to update for user with id %s", data.Id)
+	}
+	query := fmt.Sprintf("UPDATE users SET %s WHERE id = $%d AND tenant_id = $%d RETURNING id", strings.Join(setClauses, ", "), argID, argID+1)
+	args = append(args, data.Id, tenantOf(ctx))
+	var updatedID string
+	err := r.Client.QueryRow(ctx.Request().Context(), query, args...).Scan(&updatedID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("user with id %s not found", data.Id)
+		}
+		if isPgError(err, pgUniqueViolation) {
+			return fmt.Errorf("user with email %s already exists", data.Email)
+		}
+		return fmt.Errorf("failed to update user: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) DeleteUser(ctx echo.Context, data dto.GetUserFromWeb) error {
+	query := `DELETE FROM users WHERE id = $1 AND tenant_id = $2`
+	res, err := r.Client.Exec(ctx.Request().Context(), query, data.Id, tenantOf(ctx))
+	if err != nil {
+		if isPgError(err, pgForeignKeyViolation) {
+			return fmt.Errorf("user with id %s still has subscriptions", data.Id)
+		}
+		return fmt.Errorf("failed to delete user: %w", err)
+	}
+	if res.RowsAffected() == 0 {
+		return fmt.Errorf("user with id %s not found", data.Id)
+	}
+	return nil
+}
+
+func (r *Repository) GetActiveSubsByUser(ctx echo.Context, data dto.GetSubByUserFromWeb) ([]dto.GetSubFromDb, error) {
+	query := `SELECT ` + subColumns + `
+	FROM subs
+	WHERE user_id = $1 AND tenant_id = $2 AND status IN ('trial', 'active') AND start_date <= now() AND end_date > now()
+	ORDER BY end_date`
+	rows, err := r.Client.Query(ctx.Request().Context(), query, data.UserId, tenantOf(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+	return collectSubs(rows)
+}
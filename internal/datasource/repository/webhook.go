@@ -0,0 +1,388 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"service/internal/dto"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	WebhookPending   = "pending"
+	WebhookDelivered = "delivered"
+	WebhookFailed    = "failed"
+)
+
+const webhookColumns = `id,
+	url,
+	event_types,
+	active,
+	created_at`
+
+func scanWebhook(row pgx.Row) (dto.GetWebhookFromDb, error) {
+	var out dto.GetWebhookFromDb
+	err := row.Scan(
+		&out.Id,
+		&out.Url,
+		&out.EventTypes,
+		&out.Active,
+		&out.CreatedAt)
+	return out, err
+}
+
+func (r *Repository) AddWebhook(ctx echo.Context, data dto.AddWebhookToDb) (dto.GetWebhookFromDb, error) {
+	query := `INSERT INTO webhooks (
+	url,
+	secret,
+	event_types,
+	tenant_id) VALUES (
+	@url,
+	@secret,
+	@event_types,
+	@tenant_id)
+	RETURNING ` + webhookColumns
+
+	args, err := StructToNamedArgs(data)
+	if err != nil {
+		return dto.GetWebhookFromDb{}, fmt.Errorf("%w", err)
+	}
+	args["tenant_id"] = tenantOf(ctx)
+	out, err := scanWebhook(r.Client.QueryRow(ctx.Request().Context(), query, args))
+	if err != nil {
+		return dto.GetWebhookFromDb{}, fmt.Errorf("%w", err)
+	}
+	out.Secret = data.Secret
+	return out, nil
+}
+
+func (r *Repository) GetWebhookById(ctx echo.Context, data dto.GetWebhookFromWeb) (dto.GetWebhookFromDb, error) {
+	query := `SELECT ` + webhookColumns + `
+	FROM webhooks
+	WHERE id = $1 AND tenant_id = $2`
+	out, err := scanWebhook(r.Client.QueryRow(ctx.Request().Context(), query, data.Id, tenantOf(ctx)))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return dto.GetWebhookFromDb{}, fmt.Errorf("webhook with id %d not found", data.Id)
+		}
+		return dto.GetWebhookFromDb{}, fmt.Errorf("%w", err)
+	}
+	return out, nil
+}
+
+func (r *Repository) GetListWebhooks(ctx echo.Context) ([]dto.GetWebhookFromDb, error) {
+	query := `SELECT ` + webhookColumns + `
+	FROM webhooks
+	WHERE tenant_id = $1
+	ORDER BY id`
+	rows, err := r.Client.Query(ctx.Request().Context(), query, tenantOf(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+	defer rows.Close()
+	var out []dto.GetWebhookFromDb
+	for rows.Next() {
+		data, err := scanWebhook(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%w", err)
+		}
+		out = append(out, data)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+	return out, nil
+}
+
+func (r *Repository) UpdateWebhookById(ctx echo.Context, data dto.UpdateWebhookToDb) error {
+	var setClauses []string
+	var args []interface{}
+	argID := 1
+	if data.Url != "" {
+		setClauses = append(setClauses, fmt.Sprintf("url = $%d", argID))
+		args = append(args, data.Url)
+		argID++
+	}
+	if data.Secret != "" {
+		setClauses = append(setClauses, fmt.Sprintf("secret = $%d", argID))
+		args = append(args, data.Secret)
+		argID++
+	}
+	if data.EventTypes != nil {
+		setClauses = append(setClauses, fmt.Sprintf("event_types = $%d", argID))
+		args = append(args, data.EventTypes)
+		argID++
+	}
+	if data.Active != nil {
+		setClauses = append(setClauses, fmt.Sprintf("active = $%d", argID))
+		args = append(args, *data.Active)
+		argID++
+	}
+	if len(setClauses) == 0 {
+		return fmt.Errorf("no fields to update for webhook with id %d", data.Id)
+	}
+	query := fmt.Sprintf("UPDATE webhooks SET %s WHERE id = $%d AND tenant_id = $%d", strings.Join(setClauses, ", "), argID, argID+1)
+	args = append(args, data.Id, tenantOf(ctx))
+	res, err := r.Client.Exec(ctx.Request().Context(), query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to update webhook: %w", err)
+	}
+	if res.RowsAffected() == 0 {
+		return fmt.Errorf("webhook with id %d not found", data.Id)
+	}
+	return nil
+}
+
+func (r *Repository) DeleteWebhook(ctx echo.Context, data dto.GetWebhookFromWeb) error {
+	res, err := r.Client.Exec(ctx.Request().Context(), `DELETE FROM webhooks WHERE id = $1 AND tenant_id = $2`, data.Id, tenantOf(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+	if res.RowsAffected() == 0 {
+		return fmt.Errorf("webhook with id %d not found", data.Id)
+	}
+	return nil
+}
+
+func (r *Repository) GetWebhookDeliveries(ctx echo.Context, data dto.GetWebhookDeliveriesFromWeb) ([]dto.WebhookDeliveryFromDb, error) {
+	query := `SELECT
+	d.id,
+	d.webhook_id,
+	d.event_id,
+	d.event_type,
+	d.status,
+	d.attempts,
+	d.next_attempt_at,
+	d.response_code,
+	d.last_error,
+	d.created_at,
+	d.delivered_at
+	FROM webhook_deliveries d
+	JOIN webhooks w ON w.id = d.webhook_id
+	WHERE d.webhook_id = $1 AND w.tenant_id = $2
+	ORDER BY d.id DESC`
+	rows, err := r.Client.Query(ctx.Request().Context(), query, data.WebhookId, tenantOf(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+	defer rows.Close()
+	var out []dto.WebhookDeliveryFromDb
+	for rows.Next() {
+		var data dto.WebhookDeliveryFromDb
+		if err := rows.Scan(
+			&data.Id,
+			&data.WebhookId,
+			&data.EventId,
+			&data.EventType,
+			&data.Status,
+			&data.Attempts,
+			&data.NextAttempt,
+			&data.ResponseCode,
+			&data.LastError,
+			&data.CreatedAt,
+			&data.DeliveredAt); err != nil {
+			return nil, fmt.Errorf("%w", err)
+		}
+		out = append(out, data)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+	return out, nil
+}
+
+func (r *Repository) GetWebhookAttempts(ctx echo.Context, data dto.GetWebhookDeliveryFromWeb) ([]dto.WebhookAttemptFromDb, error) {
+	query := `SELECT
+	a.id,
+	a.delivery_id,
+	a.response_code,
+	a.error,
+	a.duration_ms,
+	a.created_at
+	FROM webhook_delivery_attempts a
+	JOIN webhook_deliveries d ON d.id = a.delivery_id
+	JOIN webhooks w ON w.id = d.webhook_id
+	WHERE a.delivery_id = $1 AND w.tenant_id = $2
+	ORDER BY a.id`
+	rows, err := r.Client.Query(ctx.Request().Context(), query, data.Id, tenantOf(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+	defer rows.Close()
+	var out []dto.WebhookAttemptFromDb
+	for rows.Next() {
+		var data dto.WebhookAttemptFromDb
+		if err := rows.Scan(
+			&data.Id,
+			&data.DeliveryId,
+			&data.ResponseCode,
+			&data.Error,
+			&data.DurationMs,
+			&data.CreatedAt); err != nil {
+			return nil, fmt.Errorf("%w", err)
+		}
+		out = append(out, data)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+	return out, nil
+}
+
+func (r *Repository) RedeliverWebhook(ctx echo.Context, data dto.GetWebhookDeliveryFromWeb) error {
+	query := `UPDATE webhook_deliveries SET
+	status = $1,
+	attempts = 0,
+	next_attempt_at = now()
+	FROM webhooks w
+	WHERE webhook_deliveries.id = $2 AND w.id = webhook_deliveries.webhook_id AND w.tenant_id = $3`
+	res, err := r.Client.Exec(ctx.Request().Context(), query, WebhookPending, data.Id, tenantOf(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to redeliver webhook: %w", err)
+	}
+	if res.RowsAffected() == 0 {
+		return fmt.Errorf("webhook delivery with id %d not found", data.Id)
+	}
+	return nil
+}
+
+// EnqueueWebhookDeliveries fans an outbox event out to the subscribed
+// webhooks once: the event is marked fanned out in the same transaction, so
+// a relay retry of the event does not enqueue its deliveries again.
+func (r *Repository) EnqueueWebhookDeliveries(ctx context.Context, data dto.EnqueueWebhookToDb) (int, error) {
+	var enqueued int
+	err := r.inTx(ctx, func(tx pgx.Tx) error {
+		query := `UPDATE outbox SET
+	fanned_out_at = now()
+	WHERE event_id = $1 AND fanned_out_at IS NULL`
+		res, err := tx.Exec(ctx, query, data.EventId)
+		if err != nil {
+			return fmt.Errorf("failed to mark outbox event fanned out: %w", err)
+		}
+		if res.RowsAffected() == 0 {
+			return nil
+		}
+		query = `INSERT INTO webhook_deliveries (
+	webhook_id,
+	event_id,
+	event_type,
+	payload)
+	SELECT id, $1, $2, $3
+	FROM webhooks
+	WHERE active AND tenant_id = $4 AND (cardinality(event_types) = 0 OR $2 = ANY(event_types))
+	ON CONFLICT (webhook_id, event_id) DO NOTHING`
+		res, err = tx.Exec(ctx, query, data.EventId, data.EventType, data.Payload, data.TenantId)
+		if err != nil {
+			return fmt.Errorf("failed to enqueue webhook deliveries: %w", err)
+		}
+		enqueued = int(res.RowsAffected())
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return enqueued, nil
+}
+
+// DeliverWebhooks leases up to limit due deliveries by moving their
+// next_attempt_at past leaseDuration, delivers them outside of the leasing
+// transaction and records every attempt in a transaction of its own.
+func (r *Repository) DeliverWebhooks(ctx context.Context, limit int, deliver func(dto.WebhookDeliveryJob) dto.WebhookAttemptToDb) (int, error) {
+	query := `WITH due AS (
+	SELECT d.id
+	FROM webhook_deliveries d
+	JOIN webhooks w ON w.id = d.webhook_id
+	WHERE d.status = $1 AND d.next_attempt_at <= now() AND w.active
+	ORDER BY d.next_attempt_at
+	LIMIT $2
+	FOR UPDATE OF d SKIP LOCKED
+	)
+	UPDATE webhook_deliveries d SET
+	next_attempt_at = now() + make_interval(secs => $3)
+	FROM due, webhooks w
+	WHERE d.id = due.id AND w.id = d.webhook_id
+	RETURNING
+	d.id,
+	w.url,
+	w.secret,
+	d.event_type,
+	d.payload,
+	d.attempts`
+	rows, err := r.Client.Query(ctx, query, WebhookPending, limit, leaseDuration.Seconds())
+	if err != nil {
+		return 0, fmt.Errorf("failed to lease webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+	var jobs []dto.WebhookDeliveryJob
+	for rows.Next() {
+		var job dto.WebhookDeliveryJob
+		if err := rows.Scan(
+			&job.Id,
+			&job.Url,
+			&job.Secret,
+			&job.EventType,
+			&job.Payload,
+			&job.Attempts); err != nil {
+			return 0, fmt.Errorf("%w", err)
+		}
+		jobs = append(jobs, job)
+	}
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("%w", err)
+	}
+	rows.Close()
+
+	var processed int
+	for _, job := range jobs {
+		attempt := deliver(job)
+		attempt.DeliveryId = job.Id
+		if err := r.inTx(ctx, func(tx pgx.Tx) error {
+			return recordWebhookAttempt(ctx, tx, attempt)
+		}); err != nil {
+			return processed, err
+		}
+		processed++
+	}
+	return processed, nil
+}
+
+func recordWebhookAttempt(ctx context.Context, tx pgx.Tx, data dto.WebhookAttemptToDb) error {
+	var code *int
+	if data.ResponseCode != 0 {
+		code = &data.ResponseCode
+	}
+	var lastError *string
+	if data.Error != "" {
+		lastError = &data.Error
+	}
+	query := `INSERT INTO webhook_delivery_attempts (
+	delivery_id,
+	response_code,
+	error,
+	duration_ms) VALUES ($1, $2, $3, $4)`
+	if _, err := tx.Exec(ctx, query, data.DeliveryId, code, lastError, data.Duration.Milliseconds()); err != nil {
+		return fmt.Errorf("failed to record webhook attempt: %w", err)
+	}
+	status := WebhookPending
+	switch {
+	case data.Delivered:
+		status = WebhookDelivered
+	case data.GiveUp:
+		status = WebhookFailed
+	}
+	query = `UPDATE webhook_deliveries SET
+	status = $1,
+	attempts = attempts + 1,
+	response_code = $2,
+	last_error = $3,
+	next_attempt_at = $4,
+	delivered_at = CASE WHEN $1 = 'delivered' THEN now() END
+	WHERE id = $5`
+	if _, err := tx.Exec(ctx, query, status, code, lastError, data.NextAttempt, data.DeliveryId); err != nil {
+		return fmt.Errorf("failed to update webhook delivery: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package dto
+
+import "time"
+
+type (
+	GetSpendAnalyticsFromWeb struct {
+		StartDate   string `json:"start_date" db:"start_date" example:"01-2022"`
+		EndDate     string `json:"end_date" db:"end_date" example:"12-2022"`
+		GroupBy     string `json:"group_by" db:"group_by" example:"service"`
+		ServiceName string `json:"service_name" db:"service_name" example:"YandexGold"`
+		UserId      string `json:"user_id" db:"user_id" example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
+		Category    string `json:"category" db:"category" example:"Entertainment"`
+	}
+
+	GetSpendAnalyticsToDb struct {
+		StartDate   time.Time `json:"start_date" db:"start_date" example:"01-2022"`
+		EndDate     time.Time `json:"end_date" db:"end_date" example:"12-2022"`
+		GroupBy     string    `json:"group_by" db:"group_by" example:"service"`
+		ServiceName string    `json:"service_name" db:"service_name" example:"YandexGold"`
+		UserId      string    `json:"user_id" db:"user_id" example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
+		Category    string    `json:"category" db:"category" example:"Entertainment"`
+	}
+
+	SpendByMonthFromDb struct {
+		Month time.Time `json:"month" db:"month" example:"2022-01-01T00:00:00Z"`
+		Group string    `json:"group,omitempty" db:"group" example:"YandexGold"`
+		Total int       `json:"total" db:"total" example:"1500"`
+	}
+
+	GetTopServicesFromWeb struct {
+		StartDate string `json:"start_date" db:"start_date" example:"01-2022"`
+		EndDate   string `json:"end_date" db:"end_date" example:"12-2022"`
+		Limit     int    `json:"limit" db:"limit" example:"10"`
+		OrderBy   string `json:"order_by" db:"order_by" example:"revenue"`
+	}
+
+	GetTopServicesToDb struct {
+		StartDate time.Time `json:"start_date" db:"start_date" example:"01-2022"`
+		EndDate   time.Time `json:"end_date" db:"end_date" example:"12-2022"`
+		Limit     int       `json:"limit" db:"limit" example:"10"`
+		OrderBy   string    `json:"order_by" db:"order_by" example:"revenue"`
+	}
+
+	TopServiceFromDb struct {
+		ServiceName string `json:"service_name" db:"service_name" example:"YandexGold"`
+		ActiveCount int    `json:"active_count" db:"active_count" example:"42"`
+		Revenue     int    `json:"revenue" db:"revenue" example:"21000"`
+	}
+
+	GetChurnFromWeb struct {
+		StartDate string `json:"start_date" db:"start_date" example:"01-2022"`
+		EndDate   string `json:"end_date" db:"end_date" example:"12-2022"`
+	}
+
+	GetChurnToDb struct {
+		StartDate time.Time `json:"start_date" db:"start_date" example:"01-2022"`
+		EndDate   time.Time `json:"end_date" db:"end_date" example:"12-2022"`
+	}
+
+	ChurnByMonthFromDb struct {
+		Month         time.Time `json:"month" db:"month" example:"2022-01-01T00:00:00Z"`
+		NewSubs       int       `json:"new_subs" db:"new_subs" example:"10"`
+		EndedSubs     int       `json:"ended_subs" db:"ended_subs" example:"3"`
+		ActiveAtStart int       `json:"active_at_start" db:"active_at_start" example:"60"`
+		ChurnRate     float64   `json:"churn_rate" db:"churn_rate" example:"0.05"`
+	}
+)
@@ -0,0 +1,51 @@
+package dto
+
+import "time"
+
+type (
+	AddAPIKeyFromWeb struct {
+		Name      string     `json:"name" db:"name" example:"billing-export"`
+		UserId    string     `json:"user_id" db:"user_id" example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
+		Scopes    []string   `json:"scopes" db:"scopes" example:"read"`
+		ExpiresAt *time.Time `json:"expires_at" db:"expires_at" example:"2026-01-01T00:00:00Z"`
+	}
+
+	AddAPIKeyToDb struct {
+		Name      string     `json:"name" db:"name" example:"billing-export"`
+		Prefix    string     `json:"prefix" db:"prefix" example:"sk_3f9a1c2b"`
+		KeyHash   string     `json:"key_hash" db:"key_hash" example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"`
+		UserId    *string    `json:"user_id" db:"user_id" example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
+		Scopes    []string   `json:"scopes" db:"scopes" example:"read"`
+		ExpiresAt *time.Time `json:"expires_at" db:"expires_at" example:"2026-01-01T00:00:00Z"`
+	}
+
+	GetAPIKeyFromWeb struct {
+		Id int `json:"id" db:"id" example:"1"`
+	}
+
+	GetAPIKeyFromDb struct {
+		Id         int        `json:"id" db:"id" example:"1"`
+		Name       string     `json:"name" db:"name" example:"billing-export"`
+		Key        string     `json:"key,omitempty" db:"-" example:"sk_3f9a1c2b..."`
+		Prefix     string     `json:"prefix" db:"prefix" example:"sk_3f9a1c2b"`
+		UserId     *string    `json:"user_id" db:"user_id" example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
+		Scopes     []string   `json:"scopes" db:"scopes" example:"read"`
+		ExpiresAt  *time.Time `json:"expires_at" db:"expires_at" example:"2026-01-01T00:00:00Z"`
+		RevokedAt  *time.Time `json:"revoked_at" db:"revoked_at" example:"2025-06-01T00:00:00Z"`
+		LastUsedAt *time.Time `json:"last_used_at" db:"last_used_at" example:"2025-05-01T12:00:00Z"`
+		CreatedAt  time.Time  `json:"created_at" db:"created_at" example:"2025-01-01T00:00:00Z"`
+	}
+
+	RotateAPIKeyToDb struct {
+		Id      int    `json:"id" db:"id" example:"1"`
+		Prefix  string `json:"prefix" db:"prefix" example:"sk_3f9a1c2b"`
+		KeyHash string `json:"key_hash" db:"key_hash" example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"`
+	}
+
+	APIKeyAuthFromDb struct {
+		Id       int      `json:"id" db:"id" example:"1"`
+		TenantId string   `json:"tenant_id" db:"tenant_id" example:"default"`
+		UserId   *string  `json:"user_id" db:"user_id" example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
+		Scopes   []string `json:"scopes" db:"scopes" example:"read"`
+	}
+)
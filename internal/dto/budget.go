@@ -0,0 +1,51 @@
+package dto
+
+import "time"
+
+type (
+	AddBudgetFromWeb struct {
+		UserId   string `json:"user_id" db:"user_id" example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
+		Category string `json:"category" db:"category" example:"streaming"`
+		Amount   int    `json:"amount" db:"amount" example:"1500"`
+	}
+
+	AddBudgetToDb struct {
+		UserId   string `json:"user_id" db:"user_id" example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
+		Category string `json:"category" db:"category" example:"streaming"`
+		Amount   int    `json:"amount" db:"amount" example:"1500"`
+	}
+
+	GetBudgetFromWeb struct {
+		Id int `json:"id" db:"id" example:"1"`
+	}
+
+	GetBudgetFromDb struct {
+		Id        int       `json:"id" db:"id" example:"1"`
+		UserId    string    `json:"user_id" db:"user_id" example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
+		Category  string    `json:"category" db:"category" example:"streaming"`
+		Amount    int       `json:"amount" db:"amount" example:"1500"`
+		Spend     int       `json:"spend" db:"spend" example:"1299"`
+		Exceeded  bool      `json:"exceeded" db:"exceeded" example:"false"`
+		CreatedAt time.Time `json:"created_at" db:"created_at" example:"2022-02-01T00:00:00Z"`
+	}
+
+	UpdateBudgetFromWeb struct {
+		Id     int `json:"id" db:"id" example:"1"`
+		Amount int `json:"amount" db:"amount" example:"2000"`
+	}
+
+	UpdateBudgetToDb struct {
+		Id     int `json:"id" db:"id" example:"1"`
+		Amount int `json:"amount" db:"amount" example:"2000"`
+	}
+
+	BudgetAlertFromDb struct {
+		Id        int64     `json:"id" db:"id" example:"1"`
+		BudgetId  int       `json:"budget_id" db:"budget_id" example:"1"`
+		Category  string    `json:"category" db:"category" example:"streaming"`
+		Month     time.Time `json:"month" db:"month" example:"2022-02-01T00:00:00Z"`
+		Amount    int       `json:"amount" db:"amount" example:"1500"`
+		Spend     int       `json:"spend" db:"spend" example:"1798"`
+		CreatedAt time.Time `json:"created_at" db:"created_at" example:"2022-02-01T00:00:00Z"`
+	}
+)
@@ -4,19 +4,37 @@ import "time"
 
 type (
 	AddSubFromWeb struct {
-		ServiceName string `json:"service_name" db:"service_name" example:"YandexGold"`
-		Price       int    `json:"price" db:"price" example:"500"`
-		UserId      string `json:"user_id" db:"user_id" example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
-		StartDate   string `json:"start_date" db:"start_date" example:"02-2022"`
-		Month       int    `json:"month" db:"month" example:"5"`
+		ServiceName    string `json:"service_name" db:"service_name" example:"YandexGold"`
+		Category       string `json:"category" db:"category" example:"Entertainment"`
+		Price          int    `json:"price" db:"price" example:"500"`
+		UserId         string `json:"user_id" db:"user_id" example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
+		StartDate      string `json:"start_date" db:"start_date" example:"02-2022"`
+		Month          int    `json:"month" db:"month" example:"5"`
+		BillingPeriod  string `json:"billing_period" db:"billing_period" example:"month"`
+		Periods        int    `json:"periods" db:"periods" example:"5"`
+		AutoRenew      bool   `json:"auto_renew" db:"auto_renew" example:"true"`
+		Status         string `json:"status" db:"status" example:"active"`
+		TrialMonths    int    `json:"trial_months" db:"trial_months" example:"1"`
+		DiscountType   string `json:"discount_type" db:"discount_type" example:"percent"`
+		DiscountValue  int    `json:"discount_value" db:"discount_value" example:"50"`
+		DiscountMonths int    `json:"discount_months" db:"discount_months" example:"3"`
 	}
 
 	AddSubToDb struct {
-		ServiceName string    `json:"service_name" db:"service_name" example:"YandexGold"`
-		Price       int       `json:"price" db:"price" example:"500"`
-		UserId      string    `json:"user_id" db:"user_id" example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
-		StartDate   time.Time `json:"start_date" db:"start_date" example:"02-2022"`
-		EndDate     time.Time `json:"end_date" db:"end_date" example:"03-2022"`
+		ServiceName    string    `json:"service_name" db:"service_name" example:"YandexGold"`
+		Category       string    `json:"category" db:"category" example:"Entertainment"`
+		Price          int       `json:"price" db:"price" example:"500"`
+		UserId         string    `json:"user_id" db:"user_id" example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
+		StartDate      time.Time `json:"start_date" db:"start_date" example:"02-2022"`
+		EndDate        time.Time `json:"end_date" db:"end_date" example:"03-2022"`
+		BillingPeriod  string    `json:"billing_period" db:"billing_period" example:"month"`
+		AutoRenew      bool      `json:"auto_renew" db:"auto_renew" example:"true"`
+		Status         string    `json:"status" db:"status" example:"active"`
+		TrialMonths    int       `json:"trial_months" db:"trial_months" example:"1"`
+		DiscountType   string    `json:"discount_type" db:"discount_type" example:"percent"`
+		DiscountValue  int       `json:"discount_value" db:"discount_value" example:"50"`
+		DiscountMonths int       `json:"discount_months" db:"discount_months" example:"3"`
+		RejectOverlaps bool      `json:"-" db:"reject_overlaps"`
 	}
 
 	GetSubFromWeb struct {
@@ -24,16 +42,26 @@ type (
 	}
 
 	GetSubFromDb struct {
-		Id          int       `json:"id" db:"id" example:"1"`
-		ServiceName string    `json:"service_name" db:"service_name" example:"YandexGold"`
-		Price       int       `json:"price" db:"price" example:"500"`
-		UserId      string    `json:"user_id" db:"user_id" example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
-		StartDate   time.Time `json:"start_date" db:"start_date" example:"02-2022"`
-		EndDate     time.Time `json:"end_date" db:"end_date" example:"03-2022"`
+		Id              int       `json:"id" db:"id" example:"1"`
+		ServiceName     string    `json:"service_name" db:"service_name" example:"YandexGold"`
+		Category        string    `json:"category" db:"category" example:"Entertainment"`
+		Price           int       `json:"price" db:"price" example:"500"`
+		UserId          string    `json:"user_id" db:"user_id" example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
+		StartDate       time.Time `json:"start_date" db:"start_date" example:"02-2022"`
+		EndDate         time.Time `json:"end_date" db:"end_date" example:"03-2022"`
+		BillingPeriod   string    `json:"billing_period" db:"billing_period" example:"month"`
+		AutoRenew       bool      `json:"auto_renew" db:"auto_renew" example:"true"`
+		Status          string    `json:"status" db:"status" example:"active"`
+		StatusChangedAt time.Time `json:"status_changed_at" db:"status_changed_at" example:"2022-02-01T00:00:00Z"`
+		TrialMonths     int       `json:"trial_months" db:"trial_months" example:"1"`
+		DiscountType    string    `json:"discount_type" db:"discount_type" example:"percent"`
+		DiscountValue   int       `json:"discount_value" db:"discount_value" example:"50"`
+		DiscountMonths  int       `json:"discount_months" db:"discount_months" example:"3"`
 	}
 
 	GetSubByUserFromWeb struct {
-		UserId string `json:"user_id" db:"user_id" example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
+		UserId string   `json:"user_id" db:"user_id" example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
+		Status []string `json:"status" db:"status" example:"active,paused"`
 	}
 
 	GetSubPriceByFilterFromWeb struct {
@@ -55,20 +83,37 @@ type (
 	}
 
 	UpdateSubFromWeb struct {
-		Id          int    `json:"id" db:"id" example:"1"`
-		ServiceName string `json:"service_name" db:"service_name" example:"YandexGold"`
-		Price       int    `json:"price" db:"price" example:"500"`
-		UserId      string `json:"user_id" db:"user_id" example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
-		StartDate   string `json:"start_date" db:"start_date" example:"02-2022"`
-		Month       int    `json:"month" db:"month" example:"5"`
+		Id             int     `json:"id" db:"id" example:"1"`
+		ServiceName    string  `json:"service_name" db:"service_name" example:"YandexGold"`
+		Category       string  `json:"category" db:"category" example:"Entertainment"`
+		Price          int     `json:"price" db:"price" example:"500"`
+		UserId         string  `json:"user_id" db:"user_id" example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
+		StartDate      string  `json:"start_date" db:"start_date" example:"02-2022"`
+		Month          int     `json:"month" db:"month" example:"5"`
+		BillingPeriod  string  `json:"billing_period" db:"billing_period" example:"month"`
+		Periods        int     `json:"periods" db:"periods" example:"5"`
+		AutoRenew      *bool   `json:"auto_renew" db:"auto_renew" example:"true"`
+		PriceFrom      string  `json:"price_from" db:"price_from" example:"04-2022"`
+		TrialMonths    *int    `json:"trial_months" db:"trial_months" example:"1"`
+		DiscountType   *string `json:"discount_type" db:"discount_type" example:"percent"`
+		DiscountValue  *int    `json:"discount_value" db:"discount_value" example:"50"`
+		DiscountMonths *int    `json:"discount_months" db:"discount_months" example:"3"`
 	}
 
 	UpdateSubToDb struct {
-		Id          int       `json:"id" db:"id" example:"1"`
-		ServiceName string    `json:"service_name" db:"service_name" example:"YandexGold"`
-		Price       int       `json:"price" db:"price" example:"500"`
-		UserId      string    `json:"user_id" db:"user_id" example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
-		StartDate   time.Time `json:"start_date" db:"start_date" example:"02-2022"`
-		EndDate     time.Time `json:"end_date" db:"end_date" example:"03-2022"`
+		Id             int       `json:"id" db:"id" example:"1"`
+		ServiceName    string    `json:"service_name" db:"service_name" example:"YandexGold"`
+		Category       string    `json:"category" db:"category" example:"Entertainment"`
+		Price          int       `json:"price" db:"price" example:"500"`
+		UserId         string    `json:"user_id" db:"user_id" example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
+		StartDate      time.Time `json:"start_date" db:"start_date" example:"02-2022"`
+		EndDate        time.Time `json:"end_date" db:"end_date" example:"03-2022"`
+		AutoRenew      *bool     `json:"auto_renew" db:"auto_renew" example:"true"`
+		PriceFrom      time.Time `json:"price_from" db:"price_from" example:"04-2022"`
+		TrialMonths    *int      `json:"trial_months" db:"trial_months" example:"1"`
+		DiscountType   *string   `json:"discount_type" db:"discount_type" example:"percent"`
+		DiscountValue  *int      `json:"discount_value" db:"discount_value" example:"50"`
+		DiscountMonths *int      `json:"discount_months" db:"discount_months" example:"3"`
+		RejectOverlaps bool      `json:"-" db:"reject_overlaps"`
 	}
 )
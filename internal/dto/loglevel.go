@@ -0,0 +1,7 @@
+package dto
+
+type (
+	LogLevelFromWeb struct {
+		Level string `json:"level" example:"debug"`
+	}
+)
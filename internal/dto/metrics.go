@@ -0,0 +1,8 @@
+package dto
+
+type (
+	SubStatusCountFromDb struct {
+		Status string `json:"status" db:"status" example:"active"`
+		Count  int    `json:"count" db:"count" example:"42"`
+	}
+)
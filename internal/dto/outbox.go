@@ -0,0 +1,31 @@
+package dto
+
+import "time"
+
+type (
+	OutboxEventFromDb struct {
+		Id          int64      `json:"id" db:"id" example:"1"`
+		EventId     string     `json:"event_id" db:"event_id" example:"2b7d1c1e-3f0a-4f55-9a8e-0c9f0d7f4a11"`
+		TenantId    string     `json:"tenant_id" db:"tenant_id" example:"default"`
+		EventType   string     `json:"event_type" db:"event_type" example:"subscription.created"`
+		Subject     string     `json:"subject" db:"subject" example:"subs/1"`
+		Data        []byte     `json:"data" db:"data"`
+		CreatedAt   time.Time  `json:"created_at" db:"created_at" example:"2022-02-01T00:00:00Z"`
+		Attempts    int        `json:"attempts" db:"attempts" example:"0"`
+		FannedOutAt *time.Time `json:"fanned_out_at" db:"fanned_out_at" example:"2022-02-01T00:00:00Z"`
+	}
+
+	OutboxAttemptToDb struct {
+		Id          int64     `json:"id" db:"id" example:"1"`
+		Published   bool      `json:"published" db:"published" example:"false"`
+		Error       string    `json:"error" db:"error" example:"timeout"`
+		NextAttempt time.Time `json:"next_attempt_at" db:"next_attempt_at" example:"2022-02-01T00:00:00Z"`
+		GiveUp      bool      `json:"give_up" db:"give_up" example:"false"`
+	}
+
+	SubStatusChangedEvent struct {
+		Id         int    `json:"id" example:"1"`
+		FromStatus string `json:"from_status" example:"active"`
+		ToStatus   string `json:"to_status" example:"paused"`
+	}
+)
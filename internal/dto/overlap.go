@@ -0,0 +1,27 @@
+package dto
+
+import "time"
+
+type (
+	FindSubOverlapsToDb struct {
+		Id          int       `json:"id" db:"id" example:"1"`
+		UserId      string    `json:"user_id" db:"user_id" example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
+		ServiceName string    `json:"service_name" db:"service_name" example:"Yandex Plus"`
+		StartDate   time.Time `json:"start_date" db:"start_date" example:"2022-02-01T00:00:00Z"`
+		EndDate     time.Time `json:"end_date" db:"end_date" example:"2022-03-01T00:00:00Z"`
+	}
+
+	GetSubOverlapsFromWeb struct {
+		UserId      string `json:"user_id" db:"user_id" example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
+		ServiceName string `json:"service_name" db:"service_name" example:"Yandex Plus"`
+	}
+
+	SubOverlapFromDb struct {
+		UserId       string    `json:"user_id" db:"user_id" example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
+		ServiceName  string    `json:"service_name" db:"service_name" example:"Yandex Plus"`
+		FirstId      int       `json:"first_id" db:"first_id" example:"1"`
+		SecondId     int       `json:"second_id" db:"second_id" example:"2"`
+		OverlapStart time.Time `json:"overlap_start" db:"overlap_start" example:"2022-02-01T00:00:00Z"`
+		OverlapEnd   time.Time `json:"overlap_end" db:"overlap_end" example:"2022-03-01T00:00:00Z"`
+	}
+)
@@ -0,0 +1,13 @@
+package dto
+
+import "time"
+
+type (
+	SubPriceFromDb struct {
+		Id            int       `json:"id" db:"id" example:"1"`
+		SubId         int       `json:"sub_id" db:"sub_id" example:"1"`
+		Price         int       `json:"price" db:"price" example:"500"`
+		EffectiveFrom time.Time `json:"effective_from" db:"effective_from" example:"2022-02-01T00:00:00Z"`
+		CreatedAt     time.Time `json:"created_at" db:"created_at" example:"2022-02-01T00:00:00Z"`
+	}
+)
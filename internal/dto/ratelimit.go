@@ -0,0 +1,14 @@
+package dto
+
+type (
+	RateLimitToDb struct {
+		Key      string  `json:"key" db:"key" example:"ip:203.0.113.7 /get_list"`
+		Capacity float64 `json:"capacity" db:"capacity" example:"30"`
+		Rate     float64 `json:"rate" db:"rate" example:"0.5"`
+	}
+
+	RateLimitFromDb struct {
+		Tokens  float64 `json:"tokens" db:"tokens" example:"12.5"`
+		Allowed bool    `json:"allowed" db:"allowed" example:"true"`
+	}
+)
@@ -0,0 +1,40 @@
+package dto
+
+import "time"
+
+type (
+	DueRemindersToDb struct {
+		Before      time.Time `json:"before" db:"before" example:"2022-02-01T00:00:00Z"`
+		Limit       int       `json:"limit" db:"limit" example:"100"`
+		MaxAttempts int       `json:"max_attempts" db:"max_attempts" example:"3"`
+	}
+
+	SubReminderJob struct {
+		SubId       int       `json:"sub_id" db:"sub_id" example:"1"`
+		ServiceName string    `json:"service_name" db:"service_name" example:"Yandex Plus"`
+		Price       int       `json:"price" db:"price" example:"400"`
+		EndDate     time.Time `json:"end_date" db:"end_date" example:"2022-02-01T00:00:00Z"`
+		AutoRenew   bool      `json:"auto_renew" db:"auto_renew" example:"true"`
+		UserId      string    `json:"user_id" db:"user_id" example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
+		DisplayName string    `json:"display_name" db:"display_name" example:"Ivan Ivanov"`
+		Email       *string   `json:"email" db:"email" example:"ivan@example.com"`
+		Timezone    string    `json:"timezone" db:"timezone" example:"Europe/Moscow"`
+	}
+
+	SubReminderToDb struct {
+		Status string `json:"status" db:"status" example:"sent"`
+		Error  string `json:"error" db:"error" example:"user has no email"`
+	}
+
+	SubReminderFromDb struct {
+		Id        int64     `json:"id" db:"id" example:"1"`
+		SubId     int       `json:"sub_id" db:"sub_id" example:"1"`
+		UserId    string    `json:"user_id" db:"user_id" example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
+		PeriodEnd time.Time `json:"period_end" db:"period_end" example:"2022-02-01T00:00:00Z"`
+		Status    string    `json:"status" db:"status" example:"sent"`
+		Attempts  int       `json:"attempts" db:"attempts" example:"1"`
+		LastError *string   `json:"last_error" db:"last_error" example:"user has no email"`
+		CreatedAt time.Time `json:"created_at" db:"created_at" example:"2022-02-01T00:00:00Z"`
+		UpdatedAt time.Time `json:"updated_at" db:"updated_at" example:"2022-02-01T00:00:00Z"`
+	}
+)
@@ -0,0 +1,18 @@
+package dto
+
+import "time"
+
+type (
+	RenewSubsToDb struct {
+		Before time.Time `json:"before" db:"before" example:"2022-03-01T00:00:00Z"`
+		Limit  int       `json:"limit" db:"limit" example:"100"`
+	}
+
+	SubRenewalFromDb struct {
+		Id          int       `json:"id" db:"id" example:"1"`
+		SubId       int       `json:"sub_id" db:"sub_id" example:"1"`
+		Price       int       `json:"price" db:"price" example:"500"`
+		PeriodStart time.Time `json:"period_start" db:"period_start" example:"2022-03-01T00:00:00Z"`
+		PeriodEnd   time.Time `json:"period_end" db:"period_end" example:"2022-04-01T00:00:00Z"`
+	}
+)
@@ -0,0 +1,28 @@
+package dto
+
+import "time"
+
+type (
+	GetSubListFromWeb struct {
+		Status []string `json:"status" db:"status" example:"active,paused"`
+	}
+
+	UpdateSubStatusToDb struct {
+		Id         int    `json:"id" db:"id" example:"1"`
+		FromStatus string `json:"from_status" db:"from_status" example:"active"`
+		ToStatus   string `json:"to_status" db:"to_status" example:"paused"`
+	}
+
+	ExpireSubsToDb struct {
+		Before time.Time `json:"before" db:"before" example:"2022-03-01T00:00:00Z"`
+		Limit  int       `json:"limit" db:"limit" example:"100"`
+	}
+
+	SubStatusHistoryFromDb struct {
+		Id         int       `json:"id" db:"id" example:"1"`
+		SubId      int       `json:"sub_id" db:"sub_id" example:"1"`
+		FromStatus string    `json:"from_status" db:"from_status" example:"active"`
+		ToStatus   string    `json:"to_status" db:"to_status" example:"paused"`
+		ChangedAt  time.Time `json:"changed_at" db:"changed_at" example:"2022-03-01T00:00:00Z"`
+	}
+)
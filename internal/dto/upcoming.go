@@ -0,0 +1,31 @@
+package dto
+
+import "time"
+
+type (
+	GetUpcomingSubsFromWeb struct {
+		Days        int      `json:"days" db:"days" example:"7"`
+		UserId      string   `json:"user_id" db:"user_id" example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
+		ServiceName string   `json:"service_name" db:"service_name" example:"YandexGold"`
+		Status      []string `json:"status" db:"status" example:"active"`
+		Limit       int      `json:"limit" db:"limit" example:"50"`
+		Offset      int      `json:"offset" db:"offset" example:"0"`
+	}
+
+	GetUpcomingSubsToDb struct {
+		From        time.Time `json:"from" db:"from" example:"2022-02-01T00:00:00Z"`
+		To          time.Time `json:"to" db:"to" example:"2022-02-08T00:00:00Z"`
+		UserId      string    `json:"user_id" db:"user_id" example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
+		ServiceName string    `json:"service_name" db:"service_name" example:"YandexGold"`
+		Status      []string  `json:"status" db:"status" example:"active"`
+		Limit       int       `json:"limit" db:"limit" example:"50"`
+		Offset      int       `json:"offset" db:"offset" example:"0"`
+	}
+
+	GetUpcomingSubsFromDb struct {
+		Items  []GetSubFromDb `json:"items"`
+		Total  int            `json:"total" example:"120"`
+		Limit  int            `json:"limit" example:"50"`
+		Offset int            `json:"offset" example:"0"`
+	}
+)
@@ -0,0 +1,57 @@
+package dto
+
+import "time"
+
+type (
+	AddUserFromWeb struct {
+		DisplayName string `json:"display_name" db:"display_name" example:"Ivan Ivanov"`
+		Email       string `json:"email" db:"email" example:"ivan@example.com"`
+		Timezone    string `json:"timezone" db:"timezone" example:"Europe/Moscow"`
+	}
+
+	AddUserToDb struct {
+		DisplayName string  `json:"display_name" db:"display_name" example:"Ivan Ivanov"`
+		Email       *string `json:"email" db:"email" example:"ivan@example.com"`
+		Timezone    string  `json:"timezone" db:"timezone" example:"Europe/Moscow"`
+		Status      string  `json:"status" db:"status" example:"active"`
+	}
+
+	GetUserFromWeb struct {
+		Id string `json:"id" db:"id" example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
+	}
+
+	GetUserFromDb struct {
+		Id          string    `json:"id" db:"id" example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
+		DisplayName string    `json:"display_name" db:"display_name" example:"Ivan Ivanov"`
+		Email       *string   `json:"email" db:"email" example:"ivan@example.com"`
+		Timezone    string    `json:"timezone" db:"timezone" example:"Europe/Moscow"`
+		Status      string    `json:"status" db:"status" example:"active"`
+		Reminders   bool      `json:"reminders_enabled" db:"reminders_enabled" example:"true"`
+		CreatedAt   time.Time `json:"created_at" db:"created_at" example:"2022-02-01T00:00:00Z"`
+	}
+
+	UpdateUserFromWeb struct {
+		Id          string `json:"id" db:"id" example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
+		DisplayName string `json:"display_name" db:"display_name" example:"Ivan Ivanov"`
+		Email       string `json:"email" db:"email" example:"ivan@example.com"`
+		Timezone    string `json:"timezone" db:"timezone" example:"Europe/Moscow"`
+		Status      string `json:"status" db:"status" example:"active"`
+		Reminders   *bool  `json:"reminders_enabled" db:"reminders_enabled" example:"false"`
+	}
+
+	UpdateUserToDb struct {
+		Id          string `json:"id" db:"id" example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
+		DisplayName string `json:"display_name" db:"display_name" example:"Ivan Ivanov"`
+		Email       string `json:"email" db:"email" example:"ivan@example.com"`
+		Timezone    string `json:"timezone" db:"timezone" example:"Europe/Moscow"`
+		Status      string `json:"status" db:"status" example:"active"`
+		Reminders   *bool  `json:"reminders_enabled" db:"reminders_enabled" example:"false"`
+	}
+
+	GetUserSummaryFromDb struct {
+		User         GetUserFromDb  `json:"user"`
+		ActiveSubs   []GetSubFromDb `json:"active_subs"`
+		MonthlySpend int            `json:"monthly_spend" example:"1500"`
+		NextRenewals []GetSubFromDb `json:"next_renewals"`
+	}
+)
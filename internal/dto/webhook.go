@@ -0,0 +1,103 @@
+package dto
+
+import "time"
+
+type (
+	AddWebhookFromWeb struct {
+		Url        string   `json:"url" db:"url" example:"https://partner.example.com/hooks/subs"`
+		Secret     string   `json:"secret" db:"secret" example:"s3cr3t"`
+		EventTypes []string `json:"event_types" db:"event_types" example:"subscription.created"`
+	}
+
+	AddWebhookToDb struct {
+		Url        string   `json:"url" db:"url" example:"https://partner.example.com/hooks/subs"`
+		Secret     string   `json:"secret" db:"secret" example:"s3cr3t"`
+		EventTypes []string `json:"event_types" db:"event_types" example:"subscription.created"`
+	}
+
+	GetWebhookFromWeb struct {
+		Id int `json:"id" db:"id" example:"1"`
+	}
+
+	GetWebhookFromDb struct {
+		Id         int       `json:"id" db:"id" example:"1"`
+		Url        string    `json:"url" db:"url" example:"https://partner.example.com/hooks/subs"`
+		Secret     string    `json:"secret,omitempty" db:"secret" example:"s3cr3t"`
+		EventTypes []string  `json:"event_types" db:"event_types" example:"subscription.created"`
+		Active     bool      `json:"active" db:"active" example:"true"`
+		CreatedAt  time.Time `json:"created_at" db:"created_at" example:"2022-02-01T00:00:00Z"`
+	}
+
+	UpdateWebhookFromWeb struct {
+		Id         int      `json:"id" db:"id" example:"1"`
+		Url        string   `json:"url" db:"url" example:"https://partner.example.com/hooks/subs"`
+		Secret     string   `json:"secret" db:"secret" example:"s3cr3t"`
+		EventTypes []string `json:"event_types" db:"event_types" example:"subscription.created"`
+		Active     *bool    `json:"active" db:"active" example:"true"`
+	}
+
+	UpdateWebhookToDb struct {
+		Id         int      `json:"id" db:"id" example:"1"`
+		Url        string   `json:"url" db:"url" example:"https://partner.example.com/hooks/subs"`
+		Secret     string   `json:"secret" db:"secret" example:"s3cr3t"`
+		EventTypes []string `json:"event_types" db:"event_types" example:"subscription.created"`
+		Active     *bool    `json:"active" db:"active" example:"true"`
+	}
+
+	EnqueueWebhookToDb struct {
+		EventId   string `json:"event_id" db:"event_id" example:"2b7d1c1e-3f0a-4f55-9a8e-0c9f0d7f4a11"`
+		TenantId  string `json:"tenant_id" db:"tenant_id" example:"default"`
+		EventType string `json:"event_type" db:"event_type" example:"subscription.created"`
+		Payload   []byte `json:"payload" db:"payload"`
+	}
+
+	WebhookDeliveryFromDb struct {
+		Id           int64      `json:"id" db:"id" example:"1"`
+		WebhookId    int        `json:"webhook_id" db:"webhook_id" example:"1"`
+		EventId      string     `json:"event_id" db:"event_id" example:"2b7d1c1e-3f0a-4f55-9a8e-0c9f0d7f4a11"`
+		EventType    string     `json:"event_type" db:"event_type" example:"subscription.created"`
+		Status       string     `json:"status" db:"status" example:"delivered"`
+		Attempts     int        `json:"attempts" db:"attempts" example:"1"`
+		NextAttempt  time.Time  `json:"next_attempt_at" db:"next_attempt_at" example:"2022-02-01T00:00:00Z"`
+		ResponseCode *int       `json:"response_code" db:"response_code" example:"200"`
+		LastError    *string    `json:"last_error" db:"last_error" example:"timeout"`
+		CreatedAt    time.Time  `json:"created_at" db:"created_at" example:"2022-02-01T00:00:00Z"`
+		DeliveredAt  *time.Time `json:"delivered_at" db:"delivered_at" example:"2022-02-01T00:00:00Z"`
+	}
+
+	WebhookDeliveryJob struct {
+		Id        int64  `json:"id" db:"id" example:"1"`
+		Url       string `json:"url" db:"url" example:"https://partner.example.com/hooks/subs"`
+		Secret    string `json:"secret" db:"secret" example:"s3cr3t"`
+		EventType string `json:"event_type" db:"event_type" example:"subscription.created"`
+		Payload   []byte `json:"payload" db:"payload"`
+		Attempts  int    `json:"attempts" db:"attempts" example:"0"`
+	}
+
+	WebhookAttemptToDb struct {
+		DeliveryId   int64         `json:"delivery_id" db:"delivery_id" example:"1"`
+		ResponseCode int           `json:"response_code" db:"response_code" example:"500"`
+		Error        string        `json:"error" db:"error" example:"timeout"`
+		Duration     time.Duration `json:"duration" db:"duration" example:"120ms"`
+		Delivered    bool          `json:"delivered" db:"delivered" example:"false"`
+		NextAttempt  time.Time     `json:"next_attempt_at" db:"next_attempt_at" example:"2022-02-01T00:00:00Z"`
+		GiveUp       bool          `json:"give_up" db:"give_up" example:"false"`
+	}
+
+	WebhookAttemptFromDb struct {
+		Id           int64     `json:"id" db:"id" example:"1"`
+		DeliveryId   int64     `json:"delivery_id" db:"delivery_id" example:"1"`
+		ResponseCode *int      `json:"response_code" db:"response_code" example:"500"`
+		Error        *string   `json:"error" db:"error" example:"webhook responded with status 500"`
+		DurationMs   int       `json:"duration_ms" db:"duration_ms" example:"120"`
+		CreatedAt    time.Time `json:"created_at" db:"created_at" example:"2022-02-01T00:00:00Z"`
+	}
+
+	GetWebhookDeliveriesFromWeb struct {
+		WebhookId int `json:"webhook_id" db:"webhook_id" example:"1"`
+	}
+
+	GetWebhookDeliveryFromWeb struct {
+		Id int64 `json:"id" db:"id" example:"1"`
+	}
+)
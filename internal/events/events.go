@@ -0,0 +1,54 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+const (
+	SpecVersion     = "1.0"
+	DataContentType = "application/json"
+)
+
+type (
+	// Event is a CloudEvents 1.0 envelope in structured JSON mode. Tenant is
+	// carried in the tenantid extension attribute.
+	Event struct {
+		SpecVersion     string          `json:"specversion"`
+		Id              string          `json:"id"`
+		Source          string          `json:"source"`
+		Type            string          `json:"type"`
+		Subject         string          `json:"subject,omitempty"`
+		Tenant          string          `json:"tenantid,omitempty"`
+		Time            time.Time       `json:"time"`
+		DataContentType string          `json:"datacontenttype"`
+		Data            json.RawMessage `json:"data"`
+	}
+
+	Publisher interface {
+		Publish(ctx context.Context, event Event) error
+		Close() error
+	}
+
+	Config interface {
+		GetEventsPublisher() string
+		GetEventsFilePath() string
+		GetEventsWebhookURL() string
+		GetEventsTimeout() time.Duration
+	}
+)
+
+func NewPublisher(cfg Config) (Publisher, error) {
+	switch cfg.GetEventsPublisher() {
+	case "", "stdout":
+		return NewStdoutPublisher(), nil
+	case "file":
+		return NewFilePublisher(cfg.GetEventsFilePath())
+	case "webhook":
+		return NewWebhookPublisher(cfg.GetEventsWebhookURL(), cfg.GetEventsTimeout())
+	default:
+		return nil, fmt.Errorf("unknown events publisher: %s", cfg.GetEventsPublisher())
+	}
+}
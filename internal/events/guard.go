@@ -0,0 +1,61 @@
+package events
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// ErrForbiddenAddress is returned for webhook targets inside the deployment's
+// network: loopback, private, link-local (including the cloud metadata
+// endpoint 169.254.169.254), unspecified and multicast addresses.
+var ErrForbiddenAddress = errors.New("webhook address is not allowed")
+
+// CheckWebhookIP reports whether a tenant webhook may be delivered to ip.
+func CheckWebhookIP(ip net.IP) error {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsUnspecified() || ip.IsMulticast() {
+		return fmt.Errorf("%w: %s", ErrForbiddenAddress, ip)
+	}
+	return nil
+}
+
+// NewWebhookClient returns the client for tenant-registered webhooks. The
+// address is checked after DNS resolution, right before connecting, so a
+// hostname that resolves (or later re-resolves) to an internal address is
+// refused too. Redirects are not followed: a 3xx is a failed delivery.
+func NewWebhookClient(timeout time.Duration) *http.Client {
+	return newWebhookClient(timeout, CheckWebhookIP)
+}
+
+func newWebhookClient(timeout time.Duration, check func(net.IP) error) *http.Client {
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control: func(_, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return fmt.Errorf("%w", err)
+			}
+			ip := net.ParseIP(host)
+			if ip == nil {
+				return fmt.Errorf("%w: %s", ErrForbiddenAddress, host)
+			}
+			return check(ip)
+		},
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// A proxy would make the dialer see the proxy address instead of the target.
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
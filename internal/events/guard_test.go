@@ -0,0 +1,78 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCheckWebhookIP(t *testing.T) {
+	tests := []struct {
+		ip        string
+		forbidden bool
+	}{
+		{ip: "93.184.216.34"},
+		{ip: "2606:2800:220:1:248:1893:25c8:1946"},
+		{ip: "127.0.0.1", forbidden: true},
+		{ip: "::1", forbidden: true},
+		{ip: "10.1.2.3", forbidden: true},
+		{ip: "172.16.0.1", forbidden: true},
+		{ip: "192.168.1.1", forbidden: true},
+		{ip: "fd00::1", forbidden: true},
+		{ip: "169.254.169.254", forbidden: true},
+		{ip: "fe80::1", forbidden: true},
+		{ip: "0.0.0.0", forbidden: true},
+		{ip: "::", forbidden: true},
+		{ip: "224.0.0.1", forbidden: true},
+		{ip: "::ffff:127.0.0.1", forbidden: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ip, func(t *testing.T) {
+			err := CheckWebhookIP(net.ParseIP(tt.ip))
+			if errors.Is(err, ErrForbiddenAddress) != tt.forbidden || (!tt.forbidden && err != nil) {
+				t.Fatalf("CheckWebhookIP(%s) error = %v, forbidden %v", tt.ip, err, tt.forbidden)
+			}
+		})
+	}
+}
+
+func TestWebhookClient(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer target.Close()
+	redirect := httptest.NewServer(http.RedirectHandler(target.URL, http.StatusTemporaryRedirect))
+	defer redirect.Close()
+
+	post := func(client *http.Client, url string) (int, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, nil)
+		if err != nil {
+			t.Fatalf("NewRequest() error = %v", err)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return 0, err
+		}
+		resp.Body.Close()
+		return resp.StatusCode, nil
+	}
+
+	if _, err := post(NewWebhookClient(time.Second), target.URL); !errors.Is(err, ErrForbiddenAddress) {
+		t.Fatalf("loopback delivery error = %v, want %v", err, ErrForbiddenAddress)
+	}
+
+	allow := newWebhookClient(time.Second, func(net.IP) error { return nil })
+	code, err := post(allow, redirect.URL)
+	if err != nil {
+		t.Fatalf("redirect delivery error = %v", err)
+	}
+	if code != http.StatusTemporaryRedirect {
+		t.Fatalf("redirect delivery code = %d, want %d", code, http.StatusTemporaryRedirect)
+	}
+}
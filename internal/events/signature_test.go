@@ -0,0 +1,44 @@
+package events
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSign(t *testing.T) {
+	ts := time.Unix(1700000000, 0)
+	body := []byte(`{"id":"1"}`)
+	tests := []struct {
+		name   string
+		secret string
+		ts     time.Time
+		body   []byte
+		want   string
+	}{
+		{
+			name:   "known vector",
+			secret: "secret",
+			ts:     ts,
+			body:   body,
+			want:   "sha256=086f6aff7bd084c98679825129c5a64dbad88c760016d6d2c0fb123f27951d54",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Sign(tt.secret, tt.ts, tt.body); got != tt.want {
+				t.Fatalf("Sign() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	sig := Sign("secret", ts, body)
+	if Sign("other", ts, body) == sig {
+		t.Fatal("signature does not depend on the secret")
+	}
+	if Sign("secret", ts.Add(time.Second), body) == sig {
+		t.Fatal("signature does not depend on the timestamp")
+	}
+	if Sign("secret", ts, []byte(`{"id":"2"}`)) == sig {
+		t.Fatal("signature does not depend on the body")
+	}
+}
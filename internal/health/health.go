@@ -0,0 +1,139 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	StatusOK   = "ok"
+	StatusFail = "fail"
+)
+
+type (
+	Store interface {
+		Ping(ctx context.Context) error
+		GetSchemaVersion(ctx context.Context) (int64, error)
+	}
+
+	Config interface {
+		GetHealthTimeout() time.Duration
+	}
+
+	Check struct {
+		Status string `json:"status" example:"ok"`
+		Error  string `json:"error,omitempty"`
+	}
+
+	Report struct {
+		Status string           `json:"status" example:"ok"`
+		Checks map[string]Check `json:"checks,omitempty"`
+	}
+
+	// Probe answers liveness and readiness checks. Readiness requires a
+	// reachable database, an up to date schema and every registered worker
+	// running, and fails for good once Drain is called.
+	Probe struct {
+		store         Store
+		schemaVersion int64
+		timeout       time.Duration
+		draining      atomic.Bool
+
+		mu      sync.Mutex
+		workers map[string]bool
+	}
+)
+
+func NewProbe(store Store, schemaVersion int64, cfg Config) *Probe {
+	return &Probe{
+		store:         store,
+		schemaVersion: schemaVersion,
+		timeout:       cfg.GetHealthTimeout(),
+		workers:       make(map[string]bool),
+	}
+}
+
+// Worker registers a background worker as running and returns the function
+// to call once it stops.
+func (p *Probe) Worker(name string) func() {
+	p.setWorker(name, true)
+	return func() {
+		p.setWorker(name, false)
+	}
+}
+
+func (p *Probe) setWorker(name string, running bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.workers[name] = running
+}
+
+// Drain makes readiness fail so load balancers stop routing new traffic to
+// the instance before it shuts down.
+func (p *Probe) Drain() {
+	p.draining.Store(true)
+}
+
+// Live reports that the process is up and serving requests.
+func (p *Probe) Live() Report {
+	return Report{Status: StatusOK}
+}
+
+func (p *Probe) Ready(ctx context.Context) Report {
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	checks := make(map[string]Check)
+	if p.draining.Load() {
+		checks["shutdown"] = failed(fmt.Errorf("server is shutting down"))
+	}
+	checks["database"] = result(p.store.Ping(ctx))
+	checks["migrations"] = result(p.checkSchema(ctx))
+	for name, running := range p.workerStates() {
+		if running {
+			checks["worker:"+name] = Check{Status: StatusOK}
+		} else {
+			checks["worker:"+name] = failed(fmt.Errorf("worker is not running"))
+		}
+	}
+
+	report := Report{Status: StatusOK, Checks: checks}
+	for _, check := range checks {
+		if check.Status != StatusOK {
+			report.Status = StatusFail
+		}
+	}
+	return report
+}
+
+func (p *Probe) checkSchema(ctx context.Context) error {
+	version, err := p.store.GetSchemaVersion(ctx)
+	if err != nil {
+		return err
+	}
+	if version < p.schemaVersion {
+		return fmt.Errorf("schema version %d, want %d", version, p.schemaVersion)
+	}
+	return nil
+}
+
+func (p *Probe) workerStates() map[string]bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return maps.Clone(p.workers)
+}
+
+func result(err error) Check {
+	if err != nil {
+		return failed(err)
+	}
+	return Check{Status: StatusOK}
+}
+
+func failed(err error) Check {
+	return Check{Status: StatusFail, Error: err.Error()}
+}
@@ -0,0 +1,127 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type testStore struct {
+	pingErr    error
+	version    int64
+	versionErr error
+}
+
+func (s testStore) Ping(context.Context) error {
+	return s.pingErr
+}
+
+func (s testStore) GetSchemaVersion(context.Context) (int64, error) {
+	return s.version, s.versionErr
+}
+
+type testConfig struct{}
+
+func (testConfig) GetHealthTimeout() time.Duration {
+	return time.Second
+}
+
+func TestReady(t *testing.T) {
+	const latest = 21
+	tests := []struct {
+		name  string
+		store testStore
+		setup func(p *Probe)
+		want  map[string]string
+	}{
+		{
+			name:  "ready",
+			store: testStore{version: latest},
+			want:  map[string]string{"database": StatusOK, "migrations": StatusOK},
+		},
+		{
+			name:  "schema ahead",
+			store: testStore{version: latest + 1},
+			want:  map[string]string{"database": StatusOK, "migrations": StatusOK},
+		},
+		{
+			name:  "schema behind",
+			store: testStore{version: latest - 1},
+			want:  map[string]string{"database": StatusOK, "migrations": StatusFail},
+		},
+		{
+			name:  "schema version unknown",
+			store: testStore{versionErr: errors.New("no goose_db_version")},
+			want:  map[string]string{"database": StatusOK, "migrations": StatusFail},
+		},
+		{
+			name:  "database down",
+			store: testStore{version: latest, pingErr: errors.New("connection refused")},
+			want:  map[string]string{"database": StatusFail, "migrations": StatusOK},
+		},
+		{
+			name:  "draining",
+			store: testStore{version: latest},
+			setup: func(p *Probe) { p.Drain() },
+			want:  map[string]string{"shutdown": StatusFail, "database": StatusOK, "migrations": StatusOK},
+		},
+		{
+			name:  "worker running",
+			store: testStore{version: latest},
+			setup: func(p *Probe) { p.Worker("renewal") },
+			want:  map[string]string{"database": StatusOK, "migrations": StatusOK, "worker:renewal": StatusOK},
+		},
+		{
+			name:  "worker stopped",
+			store: testStore{version: latest},
+			setup: func(p *Probe) {
+				p.Worker("relay")
+				stop := p.Worker("renewal")
+				stop()
+			},
+			want: map[string]string{
+				"database":       StatusOK,
+				"migrations":     StatusOK,
+				"worker:relay":   StatusOK,
+				"worker:renewal": StatusFail,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewProbe(tt.store, latest, testConfig{})
+			if tt.setup != nil {
+				tt.setup(p)
+			}
+			report := p.Ready(context.Background())
+			wantStatus := StatusOK
+			for name, want := range tt.want {
+				check, ok := report.Checks[name]
+				if !ok || check.Status != want {
+					t.Fatalf("Ready() check %s = %+v, want %s", name, check, want)
+				}
+				if (check.Error != "") != (want == StatusFail) {
+					t.Fatalf("Ready() check %s error = %q, want error %v", name, check.Error, want == StatusFail)
+				}
+				if want != StatusOK {
+					wantStatus = StatusFail
+				}
+			}
+			if len(report.Checks) != len(tt.want) {
+				t.Fatalf("Ready() checks = %+v, want %v", report.Checks, tt.want)
+			}
+			if report.Status != wantStatus {
+				t.Fatalf("Ready() status = %s, want %s", report.Status, wantStatus)
+			}
+		})
+	}
+}
+
+func TestLive(t *testing.T) {
+	p := NewProbe(testStore{pingErr: errors.New("connection refused")}, 1, testConfig{})
+	p.Drain()
+	if report := p.Live(); report.Status != StatusOK {
+		t.Fatalf("Live() status = %s, want %s", report.Status, StatusOK)
+	}
+}
@@ -0,0 +1,95 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"service/internal/dto"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+type testSubsStore struct {
+	counts []dto.SubStatusCountFromDb
+	err    error
+}
+
+func (s testSubsStore) CountSubsByStatus(context.Context) ([]dto.SubStatusCountFromDb, error) {
+	return s.counts, s.err
+}
+
+func TestSubsCollector(t *testing.T) {
+	store := testSubsStore{counts: []dto.SubStatusCountFromDb{
+		{Status: "active", Count: 3},
+		{Status: "trial", Count: 1},
+	}}
+	want := `
+# HELP sub_service_subscriptions Subscriptions by status.
+# TYPE sub_service_subscriptions gauge
+sub_service_subscriptions{status="active"} 3
+sub_service_subscriptions{status="cancelled"} 0
+sub_service_subscriptions{status="expired"} 0
+sub_service_subscriptions{status="paused"} 0
+sub_service_subscriptions{status="trial"} 1
+`
+	c := NewSubsCollector(store, time.Second)
+	if err := testutil.CollectAndCompare(c, strings.NewReader(want)); err != nil {
+		t.Fatalf("CollectAndCompare() error = %v", err)
+	}
+
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(NewSubsCollector(testSubsStore{err: errors.New("connection refused")}, time.Second))
+	if _, err := reg.Gather(); err == nil {
+		t.Fatal("Gather() error = nil, want the store error")
+	}
+}
+
+func TestPoolCollector(t *testing.T) {
+	newPool := func(maxConns int) *pgxpool.Pool {
+		cfg, err := pgxpool.ParseConfig("postgres://localhost:5432/test")
+		if err != nil {
+			t.Fatalf("ParseConfig() error = %v", err)
+		}
+		cfg.MaxConns = int32(maxConns)
+		pool, err := pgxpool.NewWithConfig(context.Background(), cfg)
+		if err != nil {
+			t.Fatalf("NewWithConfig() error = %v", err)
+		}
+		t.Cleanup(pool.Close)
+		return pool
+	}
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(
+		NewPoolCollector("requests", newPool(8)),
+		NewPoolCollector("workers", newPool(2)),
+	)
+	want := `
+# HELP sub_service_db_pool_acquired_connections Connections currently acquired from the pool.
+# TYPE sub_service_db_pool_acquired_connections gauge
+sub_service_db_pool_acquired_connections{pool="requests"} 0
+sub_service_db_pool_acquired_connections{pool="workers"} 0
+# HELP sub_service_db_pool_max_connections Maximum size of the pool.
+# TYPE sub_service_db_pool_max_connections gauge
+sub_service_db_pool_max_connections{pool="requests"} 8
+sub_service_db_pool_max_connections{pool="workers"} 2
+# HELP sub_service_db_pool_acquires_total Successful acquires from the pool.
+# TYPE sub_service_db_pool_acquires_total counter
+sub_service_db_pool_acquires_total{pool="requests"} 0
+sub_service_db_pool_acquires_total{pool="workers"} 0
+`
+	err := testutil.GatherAndCompare(reg, strings.NewReader(want),
+		"sub_service_db_pool_acquired_connections",
+		"sub_service_db_pool_max_connections",
+		"sub_service_db_pool_acquires_total",
+	)
+	if err != nil {
+		t.Fatalf("GatherAndCompare() error = %v", err)
+	}
+	if n := testutil.CollectAndCount(reg); n != 20 {
+		t.Fatalf("CollectAndCount() = %d, want 20", n)
+	}
+}
@@ -0,0 +1,76 @@
+package metrics
+
+import (
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type (
+	Pool interface {
+		Stat() *pgxpool.Stat
+	}
+
+	// PoolCollector exports pgxpool statistics on every scrape, labelled with
+	// the pool name so the request and worker pools can be told apart.
+	PoolCollector struct {
+		pool Pool
+
+		acquired     *prometheus.Desc
+		idle         *prometheus.Desc
+		constructing *prometheus.Desc
+		total        *prometheus.Desc
+		max          *prometheus.Desc
+		acquires     *prometheus.Desc
+		emptyAcq     *prometheus.Desc
+		canceledAcq  *prometheus.Desc
+		acquireTime  *prometheus.Desc
+		waitTime     *prometheus.Desc
+	}
+)
+
+func NewPoolCollector(name string, pool Pool) *PoolCollector {
+	labels := prometheus.Labels{"pool": name}
+	desc := func(metric, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(namespace, "db_pool", metric), help, nil, labels)
+	}
+	return &PoolCollector{
+		pool:         pool,
+		acquired:     desc("acquired_connections", "Connections currently acquired from the pool."),
+		idle:         desc("idle_connections", "Idle connections in the pool."),
+		constructing: desc("constructing_connections", "Connections being established."),
+		total:        desc("total_connections", "Connections in the pool."),
+		max:          desc("max_connections", "Maximum size of the pool."),
+		acquires:     desc("acquires_total", "Successful acquires from the pool."),
+		emptyAcq:     desc("empty_acquires_total", "Acquires that had to wait for a connection."),
+		canceledAcq:  desc("canceled_acquires_total", "Acquires canceled by their context."),
+		acquireTime:  desc("acquire_duration_seconds_total", "Total time spent acquiring connections."),
+		waitTime:     desc("acquire_wait_seconds_total", "Total time acquires waited for a connection to free up."),
+	}
+}
+
+func (c *PoolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.acquired
+	ch <- c.idle
+	ch <- c.constructing
+	ch <- c.total
+	ch <- c.max
+	ch <- c.acquires
+	ch <- c.emptyAcq
+	ch <- c.canceledAcq
+	ch <- c.acquireTime
+	ch <- c.waitTime
+}
+
+func (c *PoolCollector) Collect(ch chan<- prometheus.Metric) {
+	s := c.pool.Stat()
+	ch <- prometheus.MustNewConstMetric(c.acquired, prometheus.GaugeValue, float64(s.AcquiredConns()))
+	ch <- prometheus.MustNewConstMetric(c.idle, prometheus.GaugeValue, float64(s.IdleConns()))
+	ch <- prometheus.MustNewConstMetric(c.constructing, prometheus.GaugeValue, float64(s.ConstructingConns()))
+	ch <- prometheus.MustNewConstMetric(c.total, prometheus.GaugeValue, float64(s.TotalConns()))
+	ch <- prometheus.MustNewConstMetric(c.max, prometheus.GaugeValue, float64(s.MaxConns()))
+	ch <- prometheus.MustNewConstMetric(c.acquires, prometheus.CounterValue, float64(s.AcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.emptyAcq, prometheus.CounterValue, float64(s.EmptyAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.canceledAcq, prometheus.CounterValue, float64(s.CanceledAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.acquireTime, prometheus.CounterValue, s.AcquireDuration().Seconds())
+	ch <- prometheus.MustNewConstMetric(c.waitTime, prometheus.CounterValue, s.EmptyAcquireWaitTime().Seconds())
+}
@@ -0,0 +1,59 @@
+package metrics
+
+import (
+	"context"
+	"service/internal/dto"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// subStatuses are always exported so a status with no subscriptions reads 0
+// instead of disappearing.
+var subStatuses = []string{"trial", "active", "paused", "cancelled", "expired"}
+
+type (
+	SubsStore interface {
+		CountSubsByStatus(ctx context.Context) ([]dto.SubStatusCountFromDb, error)
+	}
+
+	// SubsCollector counts subscriptions per status on every scrape.
+	SubsCollector struct {
+		store   SubsStore
+		timeout time.Duration
+		subs    *prometheus.Desc
+	}
+)
+
+func NewSubsCollector(store SubsStore, timeout time.Duration) *SubsCollector {
+	return &SubsCollector{
+		store:   store,
+		timeout: timeout,
+		subs: prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "subscriptions"),
+			"Subscriptions by status.", []string{"status"}, nil),
+	}
+}
+
+func (c *SubsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.subs
+}
+
+func (c *SubsCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+	counts, err := c.store.CountSubsByStatus(ctx)
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(c.subs, err)
+		return
+	}
+	byStatus := make(map[string]int, len(subStatuses))
+	for _, status := range subStatuses {
+		byStatus[status] = 0
+	}
+	for _, count := range counts {
+		byStatus[count.Status] = count.Count
+	}
+	for status, count := range byStatus {
+		ch <- prometheus.MustNewConstMetric(c.subs, prometheus.GaugeValue, float64(count), status)
+	}
+}
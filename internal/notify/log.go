@@ -0,0 +1,27 @@
+package notify
+
+import (
+	"context"
+	"service/internal/dto"
+
+	"github.com/sirupsen/logrus"
+)
+
+type LogNotifier struct {
+	log *logrus.Logger
+}
+
+func NewLogNotifier(log *logrus.Logger) *LogNotifier {
+	return &LogNotifier{log: log}
+}
+
+func (n *LogNotifier) Notify(ctx context.Context, reminder dto.SubReminderJob) error {
+	n.log.WithFields(logrus.Fields{
+		"sub_id":       reminder.SubId,
+		"user_id":      reminder.UserId,
+		"service_name": reminder.ServiceName,
+		"end_date":     reminder.EndDate,
+		"auto_renew":   reminder.AutoRenew,
+	}).Info("reminder: subscription period ends soon")
+	return nil
+}
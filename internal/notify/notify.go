@@ -0,0 +1,99 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"service/internal/dto"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ErrNoRecipient is returned when a sink has no address for the user, the
+// reminder is then recorded as skipped instead of being retried.
+var ErrNoRecipient = errors.New("no recipient for reminder")
+
+type (
+	Notifier interface {
+		Notify(ctx context.Context, reminder dto.SubReminderJob) error
+	}
+
+	Config interface {
+		GetRemindersNotifiers() []string
+		GetRemindersWebhookURL() string
+		GetRemindersTimeout() time.Duration
+		GetSMTPHost() string
+		GetSMTPPort() string
+		GetSMTPUsername() string
+		GetSMTPPassword() string
+		GetSMTPFrom() string
+	}
+
+	sink struct {
+		name string
+		Notifier
+	}
+
+	// multiNotifier sends a reminder through every sink. A reminder counts as
+	// sent once any sink delivered it, so a failing sink does not make the
+	// others send it again on retry; the failure is only logged.
+	multiNotifier struct {
+		sinks []sink
+		log   *logrus.Logger
+	}
+)
+
+func NewNotifier(cfg Config, log *logrus.Logger) (Notifier, error) {
+	m := multiNotifier{log: log}
+	for _, name := range cfg.GetRemindersNotifiers() {
+		switch name {
+		case "log":
+			m.sinks = append(m.sinks, sink{name, NewLogNotifier(log)})
+		case "smtp":
+			n, err := NewSMTPNotifier(cfg)
+			if err != nil {
+				return nil, err
+			}
+			m.sinks = append(m.sinks, sink{name, n})
+		case "webhook":
+			n, err := NewWebhookNotifier(cfg.GetRemindersWebhookURL(), cfg.GetRemindersTimeout())
+			if err != nil {
+				return nil, err
+			}
+			m.sinks = append(m.sinks, sink{name, n})
+		default:
+			return nil, fmt.Errorf("unknown reminders notifier: %s", name)
+		}
+	}
+	if len(m.sinks) == 0 {
+		return nil, fmt.Errorf("no reminders notifiers configured")
+	}
+	return m, nil
+}
+
+func (m multiNotifier) Notify(ctx context.Context, reminder dto.SubReminderJob) error {
+	sent := false
+	var errs []error
+	for _, s := range m.sinks {
+		err := s.Notify(ctx, reminder)
+		if errors.Is(err, ErrNoRecipient) {
+			continue
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", s.name, err))
+			continue
+		}
+		sent = true
+	}
+	switch {
+	case sent:
+		for _, err := range errs {
+			m.log.Warnf("reminders: sub %d: %v (sent through other sinks)", reminder.SubId, err)
+		}
+		return nil
+	case len(errs) > 0:
+		return errors.Join(errs...)
+	}
+	return ErrNoRecipient
+}
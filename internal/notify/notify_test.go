@@ -0,0 +1,65 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"io"
+	"service/internal/dto"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+)
+
+type testNotifier struct {
+	err   error
+	calls int
+}
+
+func (n *testNotifier) Notify(context.Context, dto.SubReminderJob) error {
+	n.calls++
+	return n.err
+}
+
+func TestMultiNotifier(t *testing.T) {
+	failed := errors.New("connection refused")
+	tests := []struct {
+		name     string
+		errs     []error
+		wantErr  error
+		wantWarn int
+	}{
+		{name: "all sent", errs: []error{nil, nil}},
+		{name: "partial failure is sent", errs: []error{failed, nil}, wantWarn: 1},
+		{name: "partial without recipient", errs: []error{ErrNoRecipient, nil}},
+		{name: "all failed", errs: []error{failed, failed}, wantErr: failed},
+		{name: "failure and no recipient", errs: []error{ErrNoRecipient, failed}, wantErr: failed},
+		{name: "no recipient", errs: []error{ErrNoRecipient, ErrNoRecipient}, wantErr: ErrNoRecipient},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := logrus.New()
+			log.SetOutput(io.Discard)
+			hook := test.NewLocal(log)
+			m := multiNotifier{log: log}
+			var sinks []*testNotifier
+			for i, err := range tt.errs {
+				n := &testNotifier{err: err}
+				sinks = append(sinks, n)
+				m.sinks = append(m.sinks, sink{name: string(rune('a' + i)), Notifier: n})
+			}
+			err := m.Notify(context.Background(), dto.SubReminderJob{SubId: 1})
+			if (tt.wantErr == nil && err != nil) || (tt.wantErr != nil && !errors.Is(err, tt.wantErr)) {
+				t.Fatalf("Notify() error = %v, want %v", err, tt.wantErr)
+			}
+			for i, n := range sinks {
+				if n.calls != 1 {
+					t.Fatalf("sink %d called %d times, want 1", i, n.calls)
+				}
+			}
+			if got := len(hook.AllEntries()); got != tt.wantWarn {
+				t.Fatalf("%d warnings logged, want %d", got, tt.wantWarn)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"service/internal/dto"
+	"strings"
+	"time"
+)
+
+var headerBreaks = strings.NewReplacer("\r", "", "\n", "")
+
+type SMTPNotifier struct {
+	addr    string
+	host    string
+	auth    smtp.Auth
+	from    string
+	timeout time.Duration
+}
+
+func NewSMTPNotifier(cfg Config) (*SMTPNotifier, error) {
+	if cfg.GetSMTPHost() == "" || cfg.GetSMTPFrom() == "" {
+		return nil, fmt.Errorf("smtp host and from address are required")
+	}
+	n := &SMTPNotifier{
+		addr:    net.JoinHostPort(cfg.GetSMTPHost(), cfg.GetSMTPPort()),
+		host:    cfg.GetSMTPHost(),
+		from:    cfg.GetSMTPFrom(),
+		timeout: cfg.GetRemindersTimeout(),
+	}
+	if cfg.GetSMTPUsername() != "" {
+		n.auth = smtp.PlainAuth("", cfg.GetSMTPUsername(), cfg.GetSMTPPassword(), cfg.GetSMTPHost())
+	}
+	return n, nil
+}
+
+func (n *SMTPNotifier) Notify(ctx context.Context, reminder dto.SubReminderJob) error {
+	if reminder.Email == nil || *reminder.Email == "" {
+		return ErrNoRecipient
+	}
+	if err := n.send(ctx, *reminder.Email, n.message(reminder)); err != nil {
+		return fmt.Errorf("failed to send reminder email: %w", err)
+	}
+	return nil
+}
+
+// send does what smtp.SendMail does over a connection with a deadline, so a
+// stalled server cannot hold the reminders worker.
+func (n *SMTPNotifier) send(ctx context.Context, to string, msg []byte) error {
+	deadline := time.Now().Add(n.timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	dialer := net.Dialer{Deadline: deadline}
+	conn, err := dialer.DialContext(ctx, "tcp", n.addr)
+	if err != nil {
+		return err
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		conn.Close()
+		return err
+	}
+	stop := context.AfterFunc(ctx, func() {
+		conn.SetDeadline(time.Now())
+	})
+	defer stop()
+
+	c, err := smtp.NewClient(conn, n.host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: n.host}); err != nil {
+			return err
+		}
+	}
+	if n.auth != nil {
+		if ok, _ := c.Extension("AUTH"); !ok {
+			return errors.New("smtp server does not support AUTH")
+		}
+		if err := c.Auth(n.auth); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(n.from); err != nil {
+		return err
+	}
+	if err := c.Rcpt(to); err != nil {
+		return err
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// message builds the reminder email. Values that end up in headers are
+// stripped of line breaks even though the service rejects them on input.
+func (n *SMTPNotifier) message(reminder dto.SubReminderJob) []byte {
+	loc, err := time.LoadLocation(reminder.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	action := "expires"
+	if reminder.AutoRenew {
+		action = "renews"
+	}
+	subject := fmt.Sprintf("Your %s subscription %s soon", headerBreaks.Replace(reminder.ServiceName), action)
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", n.from)
+	fmt.Fprintf(&b, "To: %s\r\n", headerBreaks.Replace(*reminder.Email))
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&b, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&b, "Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	fmt.Fprintf(&b, "Hello, %s!\r\n\r\n", reminder.DisplayName)
+	fmt.Fprintf(&b, "Your %s subscription %s on %s.\r\n", reminder.ServiceName, action, reminder.EndDate.In(loc).Format("02.01.2006"))
+	if reminder.AutoRenew {
+		fmt.Fprintf(&b, "You will be charged %d.\r\n", reminder.Price)
+	}
+	return b.Bytes()
+}
@@ -0,0 +1,45 @@
+package notify
+
+import (
+	"mime"
+	"net/mail"
+	"service/internal/dto"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSMTPMessageHeaders(t *testing.T) {
+	email := "ivan@example.com\r\nBcc: victim@example.com"
+	n := &SMTPNotifier{from: "noreply@example.com"}
+	msg := n.message(dto.SubReminderJob{
+		ServiceName: "Кинопоиск\r\nBcc: other@example.com",
+		EndDate:     time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC),
+		Email:       &email,
+		Timezone:    "Europe/Moscow",
+		AutoRenew:   true,
+		Price:       400,
+	})
+
+	parsed, err := mail.ReadMessage(strings.NewReader(string(msg)))
+	if err != nil {
+		t.Fatalf("failed to parse message: %v", err)
+	}
+	if bcc := parsed.Header.Get("Bcc"); bcc != "" {
+		t.Fatalf("injected Bcc header: %s", bcc)
+	}
+	if to := parsed.Header.Get("To"); to != "ivan@example.comBcc: victim@example.com" {
+		t.Fatalf("To = %q", to)
+	}
+	raw := parsed.Header.Get("Subject")
+	if !strings.HasPrefix(raw, "=?utf-8?q?") {
+		t.Fatalf("subject is not Q-encoded: %s", raw)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(raw)
+	if err != nil {
+		t.Fatalf("failed to decode subject: %v", err)
+	}
+	if want := "Your КинопоискBcc: other@example.com subscription renews soon"; subject != want {
+		t.Fatalf("Subject = %q, want %q", subject, want)
+	}
+}
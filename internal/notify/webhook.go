@@ -0,0 +1,47 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"service/internal/dto"
+	"time"
+)
+
+type WebhookNotifier struct {
+	url    string
+	client *http.Client
+}
+
+func NewWebhookNotifier(url string, timeout time.Duration) (*WebhookNotifier, error) {
+	if url == "" {
+		return nil, fmt.Errorf("reminders webhook url is empty")
+	}
+	return &WebhookNotifier{
+		url:    url,
+		client: &http.Client{Timeout: timeout},
+	}, nil
+}
+
+func (n *WebhookNotifier) Notify(ctx context.Context, reminder dto.SubReminderJob) error {
+	body, err := json.Marshal(reminder)
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("reminders webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
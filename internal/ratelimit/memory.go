@@ -0,0 +1,77 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const memorySweepInterval = time.Minute
+
+type (
+	// MemoryStore keeps buckets in process memory, limits are therefore
+	// applied per replica.
+	MemoryStore struct {
+		mu      sync.Mutex
+		buckets map[string]*bucket
+		swept   time.Time
+	}
+
+	bucket struct {
+		tokens  float64
+		updated time.Time
+		full    time.Time
+	}
+)
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets: make(map[string]*bucket),
+		swept:   time.Now(),
+	}
+}
+
+func (s *MemoryStore) Take(_ context.Context, key string, limit Limit) (Result, error) {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sweep(now)
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: limit.capacity(), updated: now}
+		s.buckets[key] = b
+	}
+	tokens, allowed := refill(b.tokens, now.Sub(b.updated), limit)
+	res := result(tokens, allowed, limit)
+	b.tokens = tokens
+	b.updated = now
+	b.full = now.Add(res.Reset)
+	return res, nil
+}
+
+func (s *MemoryStore) Peek(_ context.Context, key string, limit Limit) (Result, error) {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tokens := limit.capacity()
+	if b, ok := s.buckets[key]; ok {
+		tokens = available(b.tokens, now.Sub(b.updated), limit)
+	}
+	return result(tokens, tokens >= 1, limit), nil
+}
+
+// sweep drops buckets that have refilled completely, they are
+// indistinguishable from new ones.
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.swept) < memorySweepInterval {
+		return
+	}
+	for key, b := range s.buckets {
+		if now.After(b.full) {
+			delete(s.buckets, key)
+		}
+	}
+	s.swept = now
+}
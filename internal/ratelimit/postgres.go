@@ -0,0 +1,83 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"service/internal/dto"
+	"sync/atomic"
+	"time"
+)
+
+const postgresPurgeInterval = 10 * time.Minute
+
+type (
+	PostgresBackend interface {
+		TakeRateLimit(ctx context.Context, data dto.RateLimitToDb) (dto.RateLimitFromDb, error)
+		PeekRateLimit(ctx context.Context, data dto.RateLimitToDb) (dto.RateLimitFromDb, error)
+		PurgeRateLimits(ctx context.Context) (int, error)
+	}
+
+	// PostgresStore keeps buckets in the rate_limits table so that all
+	// replicas share the same limits.
+	PostgresStore struct {
+		backend   PostgresBackend
+		nextPurge atomic.Int64
+	}
+)
+
+func NewPostgresStore(backend PostgresBackend) *PostgresStore {
+	s := &PostgresStore{backend: backend}
+	s.nextPurge.Store(time.Now().Add(postgresPurgeInterval).UnixNano())
+	return s
+}
+
+func (s *PostgresStore) Take(ctx context.Context, key string, limit Limit) (Result, error) {
+	if err := s.purge(ctx); err != nil {
+		return Result{}, err
+	}
+	out, err := s.backend.TakeRateLimit(ctx, dto.RateLimitToDb{
+		Key:      key,
+		Capacity: limit.capacity(),
+		Rate:     limit.rate(),
+	})
+	if err != nil {
+		return Result{}, fmt.Errorf("%w", err)
+	}
+	return result(out.Tokens, out.Allowed, limit), nil
+}
+
+func (s *PostgresStore) Peek(ctx context.Context, key string, limit Limit) (Result, error) {
+	out, err := s.backend.PeekRateLimit(ctx, dto.RateLimitToDb{
+		Key:      key,
+		Capacity: limit.capacity(),
+		Rate:     limit.rate(),
+	})
+	if err != nil {
+		return Result{}, fmt.Errorf("%w", err)
+	}
+	return result(out.Tokens, out.Allowed, limit), nil
+}
+
+// purge deletes refilled buckets; only one caller per interval does the work.
+func (s *PostgresStore) purge(ctx context.Context) error {
+	next := s.nextPurge.Load()
+	now := time.Now()
+	if now.UnixNano() < next || !s.nextPurge.CompareAndSwap(next, now.Add(postgresPurgeInterval).UnixNano()) {
+		return nil
+	}
+	if _, err := s.backend.PurgeRateLimits(ctx); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+	return nil
+}
+
+func NewStore(cfg Config, backend PostgresBackend) (Store, error) {
+	switch cfg.GetRateLimitStore() {
+	case "", "memory":
+		return NewMemoryStore(), nil
+	case "postgres":
+		return NewPostgresStore(backend), nil
+	default:
+		return nil, fmt.Errorf("unknown rate limit store: %s", cfg.GetRateLimitStore())
+	}
+}
@@ -0,0 +1,151 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"service/internal/config"
+	"time"
+)
+
+type (
+	// Limit allows Requests per Period with bursts of up to Requests: the
+	// bucket holds Requests tokens and refills evenly over Period.
+	Limit struct {
+		Requests int
+		Period   time.Duration
+	}
+
+	Result struct {
+		Allowed    bool
+		Remaining  int
+		Reset      time.Duration
+		RetryAfter time.Duration
+	}
+
+	Store interface {
+		Take(ctx context.Context, key string, limit Limit) (Result, error)
+		// Peek reports whether Take would be allowed without taking a token.
+		Peek(ctx context.Context, key string, limit Limit) (Result, error)
+	}
+
+	Config interface {
+		GetRateLimitStore() string
+		GetRateLimitRequests() int
+		GetRateLimitPeriod() time.Duration
+		GetRateLimitRoutes() map[string]config.RateLimitRoute
+		GetRateLimitAuthRequests() int
+		GetRateLimitAuthPeriod() time.Duration
+	}
+
+	Limiter struct {
+		store  Store
+		def    Limit
+		auth   Limit
+		routes map[string]Limit
+	}
+)
+
+func NewLimiter(store Store, cfg Config) (*Limiter, error) {
+	l := &Limiter{
+		store:  store,
+		def:    Limit{Requests: cfg.GetRateLimitRequests(), Period: cfg.GetRateLimitPeriod()},
+		auth:   Limit{Requests: cfg.GetRateLimitAuthRequests(), Period: cfg.GetRateLimitAuthPeriod()},
+		routes: make(map[string]Limit),
+	}
+	if err := l.def.validate(); err != nil {
+		return nil, fmt.Errorf("rate limit: %w", err)
+	}
+	if err := l.auth.validate(); err != nil {
+		return nil, fmt.Errorf("rate limit for authentication: %w", err)
+	}
+	for route, rl := range cfg.GetRateLimitRoutes() {
+		limit := Limit{Requests: rl.Requests, Period: rl.Period}
+		if err := limit.validate(); err != nil {
+			return nil, fmt.Errorf("rate limit for %s: %w", route, err)
+		}
+		l.routes[route] = limit
+	}
+	return l, nil
+}
+
+// Allow takes a token for client on route. Routes with their own limit get a
+// separate bucket per client, all other routes share the default one.
+func (l *Limiter) Allow(ctx context.Context, client, route string) (Result, Limit, error) {
+	limit, ok := l.routes[route]
+	key := client + " " + route
+	if !ok {
+		limit = l.def
+		key = client
+	}
+	res, err := l.store.Take(ctx, key, limit)
+	if err != nil {
+		return Result{}, limit, err
+	}
+	return res, limit, nil
+}
+
+// CheckAuth reports whether client may still attempt to authenticate. Only
+// failed attempts are charged, see FailAuth, so clients with valid
+// credentials are limited by their own buckets alone.
+func (l *Limiter) CheckAuth(ctx context.Context, client string) (Result, Limit, error) {
+	res, err := l.store.Peek(ctx, client+" auth", l.auth)
+	if err != nil {
+		return Result{}, l.auth, err
+	}
+	return res, l.auth, nil
+}
+
+// FailAuth charges a failed authentication attempt to client.
+func (l *Limiter) FailAuth(ctx context.Context, client string) error {
+	_, err := l.store.Take(ctx, client+" auth", l.auth)
+	return err
+}
+
+func (l Limit) validate() error {
+	if l.Requests <= 0 || l.Period <= 0 {
+		return fmt.Errorf("requests and period must be positive")
+	}
+	return nil
+}
+
+func (l Limit) capacity() float64 {
+	return float64(l.Requests)
+}
+
+// rate is the refill speed in tokens per second.
+func (l Limit) rate() float64 {
+	return float64(l.Requests) / l.Period.Seconds()
+}
+
+// available returns tokens plus the tokens accumulated over elapsed, capped
+// at the capacity of limit.
+func available(tokens float64, elapsed time.Duration, limit Limit) float64 {
+	return math.Min(limit.capacity(), tokens+elapsed.Seconds()*limit.rate())
+}
+
+// refill adds the tokens accumulated over elapsed to tokens and takes one if
+// available. It returns the tokens left in the bucket.
+func refill(tokens float64, elapsed time.Duration, limit Limit) (float64, bool) {
+	tokens = available(tokens, elapsed, limit)
+	if tokens < 1 {
+		return tokens, false
+	}
+	return tokens - 1, true
+}
+
+func result(tokens float64, allowed bool, limit Limit) Result {
+	res := Result{
+		Allowed:   allowed,
+		Remaining: int(math.Floor(tokens)),
+		Reset:     seconds((limit.capacity() - tokens) / limit.rate()),
+	}
+	if !allowed {
+		res.RetryAfter = seconds((1 - tokens) / limit.rate())
+	}
+	return res
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
@@ -0,0 +1,114 @@
+package ratelimit
+
+import (
+	"context"
+	"service/internal/config"
+	"testing"
+	"time"
+)
+
+// testLimit holds 10 tokens and refills one token per second.
+var testLimit = Limit{Requests: 10, Period: 10 * time.Second}
+
+func TestRefill(t *testing.T) {
+	tests := []struct {
+		name        string
+		tokens      float64
+		elapsed     time.Duration
+		wantTokens  float64
+		wantAllowed bool
+	}{
+		{name: "full", tokens: 10, wantTokens: 9, wantAllowed: true},
+		{name: "empty", tokens: 0, wantTokens: 0},
+		{name: "partial token", tokens: 0.5, elapsed: 250 * time.Millisecond, wantTokens: 0.75},
+		{name: "refilled to one", tokens: 0.5, elapsed: 500 * time.Millisecond, wantTokens: 0, wantAllowed: true},
+		{name: "capped at capacity", tokens: 5, elapsed: time.Hour, wantTokens: 9, wantAllowed: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens, allowed := refill(tt.tokens, tt.elapsed, testLimit)
+			if tokens != tt.wantTokens || allowed != tt.wantAllowed {
+				t.Fatalf("refill() = %v, %v, want %v, %v", tokens, allowed, tt.wantTokens, tt.wantAllowed)
+			}
+		})
+	}
+}
+
+func TestResult(t *testing.T) {
+	tests := []struct {
+		name    string
+		tokens  float64
+		allowed bool
+		want    Result
+	}{
+		{name: "full", tokens: 10, allowed: true, want: Result{Allowed: true, Remaining: 10}},
+		{name: "allowed", tokens: 8.5, allowed: true, want: Result{Allowed: true, Remaining: 8, Reset: 1500 * time.Millisecond}},
+		{name: "last token", tokens: 0, allowed: true, want: Result{Allowed: true, Remaining: 0, Reset: 10 * time.Second}},
+		{name: "denied", tokens: 0.25, want: Result{Remaining: 0, Reset: 9750 * time.Millisecond, RetryAfter: 750 * time.Millisecond}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := result(tt.tokens, tt.allowed, testLimit); got != tt.want {
+				t.Fatalf("result() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+type testConfig struct{}
+
+func (testConfig) GetRateLimitStore() string                            { return "memory" }
+func (testConfig) GetRateLimitRequests() int                            { return 10 }
+func (testConfig) GetRateLimitPeriod() time.Duration                    { return time.Minute }
+func (testConfig) GetRateLimitRoutes() map[string]config.RateLimitRoute { return nil }
+func (testConfig) GetRateLimitAuthRequests() int                        { return 2 }
+func (testConfig) GetRateLimitAuthPeriod() time.Duration                { return time.Minute }
+
+func TestAuthBucket(t *testing.T) {
+	l, err := NewLimiter(NewMemoryStore(), testConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	check := func(client string, want bool) {
+		t.Helper()
+		res, _, err := l.CheckAuth(ctx, client)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Allowed != want {
+			t.Fatalf("CheckAuth(%s) allowed = %v, want %v", client, res.Allowed, want)
+		}
+	}
+
+	for range 5 {
+		check("ip:192.0.2.1", true)
+	}
+	for range 2 {
+		if err := l.FailAuth(ctx, "ip:192.0.2.1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	check("ip:192.0.2.1", false)
+	check("ip:192.0.2.2", true)
+	if res, _, _ := l.Allow(ctx, "ip:192.0.2.1", "/get_list"); !res.Allowed {
+		t.Fatal("Allow() shares the authentication bucket")
+	}
+}
+
+func TestMemoryStorePeek(t *testing.T) {
+	s := NewMemoryStore()
+	ctx := context.Background()
+	for range 3 {
+		res, err := s.Peek(ctx, "k", testLimit)
+		if err != nil || !res.Allowed || res.Remaining != 10 {
+			t.Fatalf("Peek() = %+v, %v, want a full bucket", res, err)
+		}
+	}
+	if _, err := s.Take(ctx, "k", testLimit); err != nil {
+		t.Fatal(err)
+	}
+	if res, _ := s.Peek(ctx, "k", testLimit); res.Remaining != 9 {
+		t.Fatalf("Peek() after Take() remaining = %d, want 9", res.Remaining)
+	}
+}
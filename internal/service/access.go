@@ -0,0 +1,86 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"service/internal/auth"
+	"service/internal/dto"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+var ErrForbidden = errors.New("forbidden")
+
+// restricted returns the caller when it is authenticated without the admin
+// role. Admins and requests without a principal (auth disabled) are not
+// restricted. The token subject of a regular user is their user ID.
+func restricted(ctx echo.Context) (auth.Principal, bool) {
+	p, ok := auth.FromContext(ctx.Request().Context())
+	if !ok || p.IsAdmin() {
+		return auth.Principal{}, false
+	}
+	return p, true
+}
+
+func requireAdmin(ctx echo.Context) error {
+	if _, ok := restricted(ctx); ok {
+		return fmt.Errorf("%w: admin role required", ErrForbidden)
+	}
+	return nil
+}
+
+// requireOperator allows only the service operator. Unlike requireAdmin it
+// does not let requests without a principal through.
+func requireOperator(ctx echo.Context) error {
+	p, ok := auth.FromContext(ctx.Request().Context())
+	if !ok || !p.IsOperator() {
+		return fmt.Errorf("%w: operator role required", ErrForbidden)
+	}
+	return nil
+}
+
+func checkOwner(ctx echo.Context, userId string) error {
+	p, ok := restricted(ctx)
+	if ok && !strings.EqualFold(userId, p.Subject) {
+		return fmt.Errorf("%w: no access to user %s", ErrForbidden, userId)
+	}
+	return nil
+}
+
+// scopeUser forces the user filter of a regular user to their own ID.
+func scopeUser(ctx echo.Context, userId string) (string, error) {
+	p, ok := restricted(ctx)
+	if !ok {
+		return userId, nil
+	}
+	if userId == "" {
+		return p.Subject, nil
+	}
+	if err := checkOwner(ctx, userId); err != nil {
+		return "", err
+	}
+	return userId, nil
+}
+
+func (s *ServiceSubs) authorizeSub(ctx echo.Context, id int) error {
+	if _, ok := restricted(ctx); !ok {
+		return nil
+	}
+	sub, err := s.Storage.GetSubById(ctx, dto.GetSubFromWeb{Id: id})
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+	return checkOwner(ctx, sub.UserId)
+}
+
+func (s *ServiceSubs) authorizeBudget(ctx echo.Context, id int) error {
+	if _, ok := restricted(ctx); !ok {
+		return nil
+	}
+	budget, err := s.Storage.GetBudgetById(ctx, dto.GetBudgetFromWeb{Id: id})
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+	return checkOwner(ctx, budget.UserId)
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"service/internal/auth"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	ownUser   = "60601fee-2bf1-4721-ae6f-7636e79a0cba"
+	otherUser = "7d0f5c2a-1e7b-4b8e-9f51-3c2d8a6b9e10"
+)
+
+// newTestContext returns an echo context authenticated as p, or without a
+// principal when p is nil.
+func newTestContext(p *auth.Principal) echo.Context {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if p != nil {
+		req = req.WithContext(auth.WithPrincipal(req.Context(), *p))
+	}
+	return echo.New().NewContext(req, httptest.NewRecorder())
+}
+
+var (
+	anonymous = (*auth.Principal)(nil)
+	admin     = &auth.Principal{Subject: "root", Roles: []string{auth.RoleAdmin}}
+	user      = &auth.Principal{Subject: ownUser, Roles: []string{"reader"}}
+	upperUser = &auth.Principal{Subject: "60601FEE-2BF1-4721-AE6F-7636E79A0CBA"}
+)
+
+func TestCheckOwner(t *testing.T) {
+	tests := []struct {
+		name      string
+		principal *auth.Principal
+		userId    string
+		forbidden bool
+	}{
+		{name: "auth disabled", principal: anonymous, userId: otherUser},
+		{name: "admin", principal: admin, userId: otherUser},
+		{name: "own user", principal: user, userId: ownUser},
+		{name: "own user, other case", principal: upperUser, userId: ownUser},
+		{name: "other user", principal: user, userId: otherUser, forbidden: true},
+		{name: "empty user", principal: user, userId: "", forbidden: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkOwner(newTestContext(tt.principal), tt.userId)
+			if got := errors.Is(err, ErrForbidden); got != tt.forbidden || (!tt.forbidden && err != nil) {
+				t.Fatalf("checkOwner() error = %v, forbidden %v", err, tt.forbidden)
+			}
+		})
+	}
+}
+
+func TestScopeUser(t *testing.T) {
+	tests := []struct {
+		name      string
+		principal *auth.Principal
+		userId    string
+		want      string
+		forbidden bool
+	}{
+		{name: "auth disabled keeps filter", principal: anonymous, userId: otherUser, want: otherUser},
+		{name: "auth disabled without filter", principal: anonymous, userId: "", want: ""},
+		{name: "admin keeps filter", principal: admin, userId: otherUser, want: otherUser},
+		{name: "admin without filter", principal: admin, userId: "", want: ""},
+		{name: "user without filter", principal: user, userId: "", want: ownUser},
+		{name: "user with own filter", principal: user, userId: ownUser, want: ownUser},
+		{name: "user with other filter", principal: user, userId: otherUser, forbidden: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := scopeUser(newTestContext(tt.principal), tt.userId)
+			if tt.forbidden {
+				if !errors.Is(err, ErrForbidden) {
+					t.Fatalf("scopeUser() error = %v, want %v", err, ErrForbidden)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Fatalf("scopeUser() = %q, %v, want %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequireAdmin(t *testing.T) {
+	tests := []struct {
+		name      string
+		principal *auth.Principal
+		forbidden bool
+	}{
+		{name: "auth disabled", principal: anonymous},
+		{name: "admin", principal: admin},
+		{name: "user", principal: user, forbidden: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := requireAdmin(newTestContext(tt.principal))
+			if errors.Is(err, ErrForbidden) != tt.forbidden {
+				t.Fatalf("requireAdmin() error = %v, forbidden %v", err, tt.forbidden)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"fmt"
+	"service/internal/dto"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+func parseMonthRange(start, end string) (time.Time, time.Time, error) {
+	sdate, err := time.Parse("01-2006", start)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w", err)
+	}
+	edate, err := time.Parse("01-2006", end)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w", err)
+	}
+	if edate.Before(sdate) {
+		return time.Time{}, time.Time{}, fmt.Errorf("end date %s is before start date %s", end, start)
+	}
+	return sdate, edate, nil
+}
+
+func (s *ServiceSubs) GetSpendAnalytics(ctx echo.Context, dataIn dto.GetSpendAnalyticsFromWeb) ([]dto.SpendByMonthFromDb, error) {
+	sdate, edate, err := parseMonthRange(dataIn.StartDate, dataIn.EndDate)
+	if err != nil {
+		return nil, err
+	}
+	switch dataIn.GroupBy {
+	case "", "service", "user", "category":
+	default:
+		return nil, fmt.Errorf("invalid group_by: %s", dataIn.GroupBy)
+	}
+	if dataIn.UserId, err = scopeUser(ctx, dataIn.UserId); err != nil {
+		return nil, err
+	}
+	if dataIn.UserId != "" {
+		if err := validateUUID(dataIn.UserId); err != nil {
+			return nil, err
+		}
+	}
+	data := dto.GetSpendAnalyticsToDb{
+		StartDate:   sdate,
+		EndDate:     edate,
+		GroupBy:     dataIn.GroupBy,
+		ServiceName: dataIn.ServiceName,
+		UserId:      dataIn.UserId,
+		Category:    dataIn.Category,
+	}
+	dataOut, err := s.Storage.GetSpendByMonth(ctx, data)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+	return dataOut, nil
+}
+
+const (
+	defaultTopServices = 10
+	maxTopServices     = 100
+)
+
+func (s *ServiceSubs) GetTopServices(ctx echo.Context, dataIn dto.GetTopServicesFromWeb) ([]dto.TopServiceFromDb, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	sdate, edate, err := parseMonthRange(dataIn.StartDate, dataIn.EndDate)
+	if err != nil {
+		return nil, err
+	}
+	switch dataIn.OrderBy {
+	case "", "count", "revenue":
+	default:
+		return nil, fmt.Errorf("invalid order_by: %s", dataIn.OrderBy)
+	}
+	limit := dataIn.Limit
+	if limit <= 0 {
+		limit = defaultTopServices
+	}
+	if limit > maxTopServices {
+		limit = maxTopServices
+	}
+	data := dto.GetTopServicesToDb{
+		StartDate: sdate,
+		EndDate:   edate,
+		Limit:     limit,
+		OrderBy:   dataIn.OrderBy,
+	}
+	dataOut, err := s.Storage.GetTopServices(ctx, data)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+	return dataOut, nil
+}
+
+func (s *ServiceSubs) GetChurn(ctx echo.Context, dataIn dto.GetChurnFromWeb) ([]dto.ChurnByMonthFromDb, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	sdate, edate, err := parseMonthRange(dataIn.StartDate, dataIn.EndDate)
+	if err != nil {
+		return nil, err
+	}
+	dataOut, err := s.Storage.GetChurnByMonth(ctx, dto.GetChurnToDb{StartDate: sdate, EndDate: edate})
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+	for i := range dataOut {
+		if dataOut[i].ActiveAtStart > 0 {
+			dataOut[i].ChurnRate = float64(dataOut[i].EndedSubs) / float64(dataOut[i].ActiveAtStart)
+		}
+	}
+	return dataOut, nil
+}
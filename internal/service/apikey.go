@@ -0,0 +1,109 @@
+package service
+
+import (
+	"fmt"
+	"service/internal/auth"
+	"service/internal/dto"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// validateScopes accepts only the scopes the service checks, so a key cannot
+// be issued with a scope that silently grants nothing.
+func validateScopes(scopes []string) error {
+	if len(scopes) == 0 {
+		return fmt.Errorf("at least one scope is required: %s", strings.Join(auth.Scopes, ", "))
+	}
+	for _, scope := range scopes {
+		if !slices.Contains(auth.Scopes, scope) {
+			return fmt.Errorf("unknown scope: %q", scope)
+		}
+	}
+	return nil
+}
+
+func (s *ServiceSubs) AddAPIKey(ctx echo.Context, data dto.AddAPIKeyFromWeb) (dto.GetAPIKeyFromDb, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return dto.GetAPIKeyFromDb{}, err
+	}
+	if data.Name == "" {
+		return dto.GetAPIKeyFromDb{}, fmt.Errorf("name is required")
+	}
+	if err := validateScopes(data.Scopes); err != nil {
+		return dto.GetAPIKeyFromDb{}, err
+	}
+	if data.UserId == "" && !slices.Contains(data.Scopes, auth.ScopeAdmin) {
+		return dto.GetAPIKeyFromDb{}, fmt.Errorf("user_id is required for keys without the %s scope", auth.ScopeAdmin)
+	}
+	if data.ExpiresAt != nil && !data.ExpiresAt.After(time.Now()) {
+		return dto.GetAPIKeyFromDb{}, fmt.Errorf("expires_at %s is in the past", data.ExpiresAt.Format(time.RFC3339))
+	}
+	var userId *string
+	if data.UserId != "" {
+		if err := s.checkUser(ctx, data.UserId); err != nil {
+			return dto.GetAPIKeyFromDb{}, err
+		}
+		userId = &data.UserId
+	}
+	key, prefix, hash, err := auth.NewAPIKey()
+	if err != nil {
+		return dto.GetAPIKeyFromDb{}, err
+	}
+	dataOut, err := s.Storage.AddAPIKey(ctx, dto.AddAPIKeyToDb{
+		Name:      data.Name,
+		Prefix:    prefix,
+		KeyHash:   hash,
+		UserId:    userId,
+		Scopes:    data.Scopes,
+		ExpiresAt: data.ExpiresAt,
+	})
+	if err != nil {
+		return dto.GetAPIKeyFromDb{}, fmt.Errorf("%w", err)
+	}
+	dataOut.Key = key
+	return dataOut, nil
+}
+
+func (s *ServiceSubs) GetListAPIKeys(ctx echo.Context) ([]dto.GetAPIKeyFromDb, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	dataOut, err := s.Storage.GetListAPIKeys(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+	return dataOut, nil
+}
+
+func (s *ServiceSubs) RevokeAPIKey(ctx echo.Context, data dto.GetAPIKeyFromWeb) error {
+	if err := requireAdmin(ctx); err != nil {
+		return err
+	}
+	if err := s.Storage.RevokeAPIKey(ctx, data); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+	return nil
+}
+
+func (s *ServiceSubs) RotateAPIKey(ctx echo.Context, data dto.GetAPIKeyFromWeb) (dto.GetAPIKeyFromDb, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return dto.GetAPIKeyFromDb{}, err
+	}
+	key, prefix, hash, err := auth.NewAPIKey()
+	if err != nil {
+		return dto.GetAPIKeyFromDb{}, err
+	}
+	dataOut, err := s.Storage.RotateAPIKey(ctx, dto.RotateAPIKeyToDb{
+		Id:      data.Id,
+		Prefix:  prefix,
+		KeyHash: hash,
+	})
+	if err != nil {
+		return dto.GetAPIKeyFromDb{}, fmt.Errorf("%w", err)
+	}
+	dataOut.Key = key
+	return dataOut, nil
+}
@@ -0,0 +1,29 @@
+package service
+
+import (
+	"service/internal/auth"
+	"testing"
+)
+
+func TestValidateScopes(t *testing.T) {
+	tests := []struct {
+		name    string
+		scopes  []string
+		wantErr bool
+	}{
+		{name: "none", wantErr: true},
+		{name: "read", scopes: []string{auth.ScopeRead}},
+		{name: "read and write", scopes: []string{auth.ScopeRead, auth.ScopeWrite}},
+		{name: "admin", scopes: []string{auth.ScopeAdmin}},
+		{name: "operator", scopes: []string{auth.RoleOperator}, wantErr: true},
+		{name: "unknown", scopes: []string{"billing:read"}, wantErr: true},
+		{name: "empty", scopes: []string{""}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateScopes(tt.scopes); (err != nil) != tt.wantErr {
+				t.Fatalf("validateScopes(%q) error = %v, wantErr %v", tt.scopes, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	BillingWeek    = "week"
+	BillingMonth   = "month"
+	BillingQuarter = "quarter"
+	BillingYear    = "year"
+)
+
+func validateBillingPeriod(period string) error {
+	switch period {
+	case "", BillingWeek, BillingMonth, BillingQuarter, BillingYear:
+		return nil
+	}
+	return fmt.Errorf("invalid billing_period: %s", period)
+}
+
+// validateLength rejects the legacy month field for non-monthly periods,
+// where it would silently be read as a number of weeks, quarters or years.
+func validateLength(period string, periods, month int) error {
+	if periods < 0 || month < 0 {
+		return fmt.Errorf("periods and month must not be negative")
+	}
+	if month > 0 && period != BillingMonth {
+		return fmt.Errorf("month requires billing_period %s, use periods", BillingMonth)
+	}
+	return nil
+}
+
+func periodsOrMonth(periods, month int) int {
+	if periods > 0 {
+		return periods
+	}
+	if month > 0 {
+		return month
+	}
+	return 1
+}
+
+func addPeriods(date time.Time, period string, count int) time.Time {
+	switch period {
+	case BillingWeek:
+		return date.AddDate(0, 0, 7*count)
+	case BillingQuarter:
+		return date.AddDate(0, 3*count, 0)
+	case BillingYear:
+		return date.AddDate(count, 0, 0)
+	default:
+		return date.AddDate(0, count, 0)
+	}
+}
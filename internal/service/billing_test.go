@@ -0,0 +1,52 @@
+package service
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAddPeriods(t *testing.T) {
+	start := time.Date(2024, time.January, 31, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		period string
+		count  int
+		want   time.Time
+	}{
+		{period: BillingWeek, count: 2, want: time.Date(2024, time.February, 14, 0, 0, 0, 0, time.UTC)},
+		{period: BillingMonth, count: 1, want: time.Date(2024, time.March, 2, 0, 0, 0, 0, time.UTC)},
+		{period: "", count: 12, want: time.Date(2025, time.January, 31, 0, 0, 0, 0, time.UTC)},
+		{period: BillingQuarter, count: 2, want: time.Date(2024, time.July, 31, 0, 0, 0, 0, time.UTC)},
+		{period: BillingYear, count: 3, want: time.Date(2027, time.January, 31, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.period, func(t *testing.T) {
+			if got := addPeriods(start, tt.period, tt.count); !got.Equal(tt.want) {
+				t.Fatalf("addPeriods(%s, %d) = %s, want %s", tt.period, tt.count, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateLength(t *testing.T) {
+	tests := []struct {
+		name    string
+		period  string
+		periods int
+		month   int
+		wantErr bool
+	}{
+		{name: "month with month", period: BillingMonth, month: 5},
+		{name: "year with periods", period: BillingYear, periods: 2},
+		{name: "defaults", period: BillingWeek},
+		{name: "year with month", period: BillingYear, month: 5, wantErr: true},
+		{name: "negative periods", period: BillingMonth, periods: -1, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateLength(tt.period, tt.periods, tt.month)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("validateLength() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"fmt"
+	"service/internal/dto"
+
+	"github.com/labstack/echo/v4"
+)
+
+func (s *ServiceSubs) AddBudget(ctx echo.Context, data dto.AddBudgetFromWeb) (dto.GetBudgetFromDb, error) {
+	var err error
+	if data.UserId, err = scopeUser(ctx, data.UserId); err != nil {
+		return dto.GetBudgetFromDb{}, err
+	}
+	if err := s.checkUser(ctx, data.UserId); err != nil {
+		return dto.GetBudgetFromDb{}, err
+	}
+	if data.Amount <= 0 {
+		return dto.GetBudgetFromDb{}, fmt.Errorf("invalid budget amount: %d", data.Amount)
+	}
+	dataOut := dto.AddBudgetToDb{
+		UserId:   data.UserId,
+		Category: data.Category,
+		Amount:   data.Amount,
+	}
+	budget, err := s.Storage.AddBudget(ctx, dataOut)
+	if err != nil {
+		return dto.GetBudgetFromDb{}, fmt.Errorf("%w", err)
+	}
+	return budget, nil
+}
+
+func (s *ServiceSubs) GetBudgetsByUser(ctx echo.Context, data dto.GetUserFromWeb) ([]dto.GetBudgetFromDb, error) {
+	if err := validateUUID(data.Id); err != nil {
+		return nil, err
+	}
+	if err := checkOwner(ctx, data.Id); err != nil {
+		return nil, err
+	}
+	dataOut, err := s.Storage.GetBudgetsByUser(ctx, data)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+	return dataOut, nil
+}
+
+func (s *ServiceSubs) UpdateBudgetById(ctx echo.Context, data dto.UpdateBudgetFromWeb) error {
+	if data.Amount <= 0 {
+		return fmt.Errorf("invalid budget amount: %d", data.Amount)
+	}
+	if err := s.authorizeBudget(ctx, data.Id); err != nil {
+		return err
+	}
+	dataOut := dto.UpdateBudgetToDb{
+		Id:     data.Id,
+		Amount: data.Amount,
+	}
+	if err := s.Storage.UpdateBudgetById(ctx, dataOut); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+	return nil
+}
+
+func (s *ServiceSubs) DeleteBudget(ctx echo.Context, data dto.GetBudgetFromWeb) error {
+	if err := s.authorizeBudget(ctx, data.Id); err != nil {
+		return err
+	}
+	if err := s.Storage.DeleteBudget(ctx, data); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+	return nil
+}
+
+func (s *ServiceSubs) GetBudgetAlerts(ctx echo.Context, data dto.GetUserFromWeb) ([]dto.BudgetAlertFromDb, error) {
+	if err := validateUUID(data.Id); err != nil {
+		return nil, err
+	}
+	if err := checkOwner(ctx, data.Id); err != nil {
+		return nil, err
+	}
+	dataOut, err := s.Storage.GetBudgetAlerts(ctx, data)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+	return dataOut, nil
+}
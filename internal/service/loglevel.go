@@ -0,0 +1,36 @@
+package service
+
+import (
+	"fmt"
+	"service/internal/dto"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+// Leveler changes the level of the application logger at runtime.
+type Leveler interface {
+	GetLevel() logrus.Level
+	SetLevel(level logrus.Level)
+}
+
+// The log level is process-wide and shared by all tenants, so it is managed by
+// the service operator rather than by tenant admins.
+func (s *ServiceSubs) GetLogLevel(ctx echo.Context) (dto.LogLevelFromWeb, error) {
+	if err := requireOperator(ctx); err != nil {
+		return dto.LogLevelFromWeb{}, err
+	}
+	return dto.LogLevelFromWeb{Level: s.leveler.GetLevel().String()}, nil
+}
+
+func (s *ServiceSubs) UpdateLogLevel(ctx echo.Context, data dto.LogLevelFromWeb) error {
+	if err := requireOperator(ctx); err != nil {
+		return err
+	}
+	level, err := logrus.ParseLevel(data.Level)
+	if err != nil {
+		return fmt.Errorf("invalid log level: %s", data.Level)
+	}
+	s.leveler.SetLevel(level)
+	return nil
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"errors"
+	"service/internal/auth"
+	"service/internal/dto"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestUpdateLogLevel(t *testing.T) {
+	operator := &auth.Principal{Subject: "ops", Roles: []string{auth.RoleOperator}}
+	tests := []struct {
+		name      string
+		principal *auth.Principal
+		level     string
+		want      logrus.Level
+		forbidden bool
+		wantErr   bool
+	}{
+		{name: "auth disabled", principal: anonymous, level: "debug", want: logrus.InfoLevel, forbidden: true},
+		{name: "regular user", principal: user, level: "debug", want: logrus.InfoLevel, forbidden: true},
+		{name: "tenant admin", principal: admin, level: "debug", want: logrus.InfoLevel, forbidden: true},
+		{name: "operator", principal: operator, level: "debug", want: logrus.DebugLevel},
+		{name: "invalid level", principal: operator, level: "verbose", want: logrus.InfoLevel, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := logrus.New()
+			log.SetLevel(logrus.InfoLevel)
+			s := &ServiceSubs{leveler: log}
+			err := s.UpdateLogLevel(newTestContext(tt.principal), dto.LogLevelFromWeb{Level: tt.level})
+			if errors.Is(err, ErrForbidden) != tt.forbidden {
+				t.Fatalf("UpdateLogLevel() error = %v, forbidden %v", err, tt.forbidden)
+			}
+			if (err != nil) != (tt.forbidden || tt.wantErr) {
+				t.Fatalf("UpdateLogLevel() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if log.GetLevel() != tt.want {
+				t.Fatalf("level = %s, want %s", log.GetLevel(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"fmt"
+	"service/internal/dto"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	OverlapReject = "reject"
+	OverlapWarn   = "warn"
+	OverlapOff    = "off"
+)
+
+func validateOverlapPolicy(policy string) error {
+	switch policy {
+	case OverlapReject, OverlapWarn, OverlapOff:
+		return nil
+	}
+	return fmt.Errorf("invalid subs.overlap_policy: %q", policy)
+}
+
+// checkOverlaps looks for other trial, active or paused subs of the same user
+// and service whose period intersects data. Depending on the overlap policy
+// they are rejected or returned as warnings. The check runs before the write
+// for a readable answer; in reject mode the repository repeats it inside the
+// write transaction with the user's row locked, which closes the race between
+// concurrent writes.
+func (s *ServiceSubs) checkOverlaps(ctx echo.Context, data dto.FindSubOverlapsToDb) ([]dto.GetSubFromDb, error) {
+	if s.overlapPolicy == OverlapOff || data.UserId == "" || data.ServiceName == "" {
+		return nil, nil
+	}
+	overlaps, err := s.Storage.FindSubOverlaps(ctx, data)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+	if len(overlaps) > 0 && s.overlapPolicy == OverlapReject {
+		ids := make([]string, 0, len(overlaps))
+		for _, sub := range overlaps {
+			ids = append(ids, fmt.Sprint(sub.Id))
+		}
+		return nil, fmt.Errorf("sub overlaps with %s sub(s) %s of the same user", data.ServiceName, strings.Join(ids, ", "))
+	}
+	return overlaps, nil
+}
+
+func (s *ServiceSubs) GetSubOverlaps(ctx echo.Context, data dto.GetSubOverlapsFromWeb) ([]dto.SubOverlapFromDb, error) {
+	var err error
+	if data.UserId, err = scopeUser(ctx, data.UserId); err != nil {
+		return nil, err
+	}
+	if data.UserId != "" {
+		if err := validateUUID(data.UserId); err != nil {
+			return nil, err
+		}
+	}
+	dataOut, err := s.Storage.GetSubOverlaps(ctx, data)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+	return dataOut, nil
+}
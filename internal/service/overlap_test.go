@@ -0,0 +1,22 @@
+package service
+
+import "testing"
+
+func TestValidateOverlapPolicy(t *testing.T) {
+	tests := []struct {
+		policy  string
+		wantErr bool
+	}{
+		{policy: OverlapReject},
+		{policy: OverlapWarn},
+		{policy: OverlapOff},
+		{policy: "", wantErr: true},
+		{policy: "Reject", wantErr: true},
+		{policy: "block", wantErr: true},
+	}
+	for _, tt := range tests {
+		if err := validateOverlapPolicy(tt.policy); (err != nil) != tt.wantErr {
+			t.Errorf("validateOverlapPolicy(%q) error = %v, wantErr %v", tt.policy, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"fmt"
+	"service/internal/dto"
+	"slices"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	SubStatusTrial     = "trial"
+	SubStatusActive    = "active"
+	SubStatusPaused    = "paused"
+	SubStatusCancelled = "cancelled"
+	SubStatusExpired   = "expired"
+)
+
+var subTransitions = map[string][]string{
+	SubStatusTrial:     {SubStatusActive, SubStatusCancelled, SubStatusExpired},
+	SubStatusActive:    {SubStatusPaused, SubStatusCancelled, SubStatusExpired},
+	SubStatusPaused:    {SubStatusActive, SubStatusCancelled},
+	SubStatusCancelled: {},
+	SubStatusExpired:   {},
+}
+
+func validateStatuses(statuses []string) error {
+	for _, status := range statuses {
+		if _, ok := subTransitions[status]; !ok {
+			return fmt.Errorf("invalid sub status: %s", status)
+		}
+	}
+	return nil
+}
+
+func checkTransition(from, to string) error {
+	if !slices.Contains(subTransitions[from], to) {
+		return fmt.Errorf("sub cannot go from %s to %s", from, to)
+	}
+	return nil
+}
+
+func (s *ServiceSubs) changeSubStatus(ctx echo.Context, data dto.GetSubFromWeb, from []string, to string) error {
+	sub, err := s.Storage.GetSubById(ctx, data)
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+	if err := checkOwner(ctx, sub.UserId); err != nil {
+		return err
+	}
+	if !slices.Contains(from, sub.Status) {
+		return fmt.Errorf("sub with id %d is %s", sub.Id, sub.Status)
+	}
+	if err := checkTransition(sub.Status, to); err != nil {
+		return err
+	}
+	dataOut := dto.UpdateSubStatusToDb{
+		Id:         sub.Id,
+		FromStatus: sub.Status,
+		ToStatus:   to,
+	}
+	if err := s.Storage.UpdateSubStatus(ctx, dataOut); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+	return nil
+}
+
+func (s *ServiceSubs) PauseSub(ctx echo.Context, data dto.GetSubFromWeb) error {
+	return s.changeSubStatus(ctx, data, []string{SubStatusActive}, SubStatusPaused)
+}
+
+func (s *ServiceSubs) ResumeSub(ctx echo.Context, data dto.GetSubFromWeb) error {
+	return s.changeSubStatus(ctx, data, []string{SubStatusPaused}, SubStatusActive)
+}
+
+func (s *ServiceSubs) CancelSub(ctx echo.Context, data dto.GetSubFromWeb) error {
+	return s.changeSubStatus(ctx, data, []string{SubStatusTrial, SubStatusActive, SubStatusPaused}, SubStatusCancelled)
+}
+
+func (s *ServiceSubs) GetSubStatusHistory(ctx echo.Context, data dto.GetSubFromWeb) ([]dto.SubStatusHistoryFromDb, error) {
+	if err := s.authorizeSub(ctx, data.Id); err != nil {
+		return nil, err
+	}
+	dataOut, err := s.Storage.GetSubStatusHistory(ctx, data)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+	return dataOut, nil
+}
@@ -0,0 +1,56 @@
+package service
+
+import "testing"
+
+func TestCheckTransition(t *testing.T) {
+	statuses := []string{
+		SubStatusTrial,
+		SubStatusActive,
+		SubStatusPaused,
+		SubStatusCancelled,
+		SubStatusExpired,
+		"unknown",
+		"",
+	}
+	allowed := map[[2]string]bool{
+		{SubStatusTrial, SubStatusActive}:     true,
+		{SubStatusTrial, SubStatusCancelled}:  true,
+		{SubStatusTrial, SubStatusExpired}:    true,
+		{SubStatusActive, SubStatusPaused}:    true,
+		{SubStatusActive, SubStatusCancelled}: true,
+		{SubStatusActive, SubStatusExpired}:   true,
+		{SubStatusPaused, SubStatusActive}:    true,
+		{SubStatusPaused, SubStatusCancelled}: true,
+	}
+	for _, from := range statuses {
+		for _, to := range statuses {
+			t.Run(from+"->"+to, func(t *testing.T) {
+				want := allowed[[2]string{from, to}]
+				if err := checkTransition(from, to); (err == nil) != want {
+					t.Fatalf("checkTransition(%q, %q) error = %v, allowed %v", from, to, err, want)
+				}
+			})
+		}
+	}
+}
+
+func TestValidateStatuses(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []string
+		wantErr  bool
+	}{
+		{name: "none"},
+		{name: "all known", statuses: []string{SubStatusTrial, SubStatusActive, SubStatusPaused, SubStatusCancelled, SubStatusExpired}},
+		{name: "unknown", statuses: []string{SubStatusActive, "unknown"}, wantErr: true},
+		{name: "empty", statuses: []string{""}, wantErr: true},
+		{name: "wrong case", statuses: []string{"Active"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateStatuses(tt.statuses); (err != nil) != tt.wantErr {
+				t.Fatalf("validateStatuses(%v) error = %v, wantErr %v", tt.statuses, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"service/internal/datasource/repository"
 	"service/internal/dto"
+	"strings"
 	"time"
 
 	"github.com/labstack/echo/v4"
@@ -11,47 +12,189 @@ import (
 
 type (
 	ServiceSubs struct {
-		Storage repository.Storage
+		Storage       repository.Storage
+		overlapPolicy string
+		leveler       Leveler
+	}
+
+	Config interface {
+		GetSubsOverlapPolicy() string
 	}
 
 	Service interface {
-		AddNewSubs(ctx echo.Context, data dto.AddSubFromWeb) error
+		AddNewSubs(ctx echo.Context, data dto.AddSubFromWeb) ([]dto.GetSubFromDb, error)
 		GetSubById(ctx echo.Context, data dto.GetSubFromWeb) (dto.GetSubFromDb, error)
-		GetListSub(ctx echo.Context) ([]dto.GetSubFromDb, error)
+		GetListSub(ctx echo.Context, data dto.GetSubListFromWeb) ([]dto.GetSubFromDb, error)
 		GetListSubByUser(ctx echo.Context, data dto.GetSubByUserFromWeb) ([]dto.GetSubFromDb, error)
 		GetPriceSubByFilter(ctx echo.Context, data dto.GetSubPriceByFilterFromWeb) (dto.GetSubPriceByFilterFromDb, error)
-		UpdateSubById(ctx echo.Context, data dto.UpdateSubFromWeb) error
+		UpdateSubById(ctx echo.Context, data dto.UpdateSubFromWeb) ([]dto.GetSubFromDb, error)
 		DeleteSub(ctx echo.Context, data dto.GetSubFromWeb) error
+
+		PauseSub(ctx echo.Context, data dto.GetSubFromWeb) error
+		ResumeSub(ctx echo.Context, data dto.GetSubFromWeb) error
+		CancelSub(ctx echo.Context, data dto.GetSubFromWeb) error
+		GetSubStatusHistory(ctx echo.Context, data dto.GetSubFromWeb) ([]dto.SubStatusHistoryFromDb, error)
+		GetSubPrices(ctx echo.Context, data dto.GetSubFromWeb) ([]dto.SubPriceFromDb, error)
+		GetUpcomingSubs(ctx echo.Context, data dto.GetUpcomingSubsFromWeb) (dto.GetUpcomingSubsFromDb, error)
+		GetSubOverlaps(ctx echo.Context, data dto.GetSubOverlapsFromWeb) ([]dto.SubOverlapFromDb, error)
+
+		GetSpendAnalytics(ctx echo.Context, data dto.GetSpendAnalyticsFromWeb) ([]dto.SpendByMonthFromDb, error)
+		GetTopServices(ctx echo.Context, data dto.GetTopServicesFromWeb) ([]dto.TopServiceFromDb, error)
+		GetChurn(ctx echo.Context, data dto.GetChurnFromWeb) ([]dto.ChurnByMonthFromDb, error)
+
+		AddWebhook(ctx echo.Context, data dto.AddWebhookFromWeb) (dto.GetWebhookFromDb, error)
+		GetWebhookById(ctx echo.Context, data dto.GetWebhookFromWeb) (dto.GetWebhookFromDb, error)
+		GetListWebhooks(ctx echo.Context) ([]dto.GetWebhookFromDb, error)
+		UpdateWebhookById(ctx echo.Context, data dto.UpdateWebhookFromWeb) error
+		DeleteWebhook(ctx echo.Context, data dto.GetWebhookFromWeb) error
+		GetWebhookDeliveries(ctx echo.Context, data dto.GetWebhookDeliveriesFromWeb) ([]dto.WebhookDeliveryFromDb, error)
+		GetWebhookAttempts(ctx echo.Context, data dto.GetWebhookDeliveryFromWeb) ([]dto.WebhookAttemptFromDb, error)
+		RedeliverWebhook(ctx echo.Context, data dto.GetWebhookDeliveryFromWeb) error
+
+		AddAPIKey(ctx echo.Context, data dto.AddAPIKeyFromWeb) (dto.GetAPIKeyFromDb, error)
+		GetListAPIKeys(ctx echo.Context) ([]dto.GetAPIKeyFromDb, error)
+		RevokeAPIKey(ctx echo.Context, data dto.GetAPIKeyFromWeb) error
+		RotateAPIKey(ctx echo.Context, data dto.GetAPIKeyFromWeb) (dto.GetAPIKeyFromDb, error)
+
+		AddNewUser(ctx echo.Context, data dto.AddUserFromWeb) (dto.GetUserFromDb, error)
+		GetUserById(ctx echo.Context, data dto.GetUserFromWeb) (dto.GetUserFromDb, error)
+		GetListUsers(ctx echo.Context) ([]dto.GetUserFromDb, error)
+		UpdateUserById(ctx echo.Context, data dto.UpdateUserFromWeb) error
+		DeleteUser(ctx echo.Context, data dto.GetUserFromWeb) error
+		GetUserSummary(ctx echo.Context, data dto.GetUserFromWeb) (dto.GetUserSummaryFromDb, error)
+		GetUserReminders(ctx echo.Context, data dto.GetUserFromWeb) ([]dto.SubReminderFromDb, error)
+
+		AddBudget(ctx echo.Context, data dto.AddBudgetFromWeb) (dto.GetBudgetFromDb, error)
+		GetBudgetsByUser(ctx echo.Context, data dto.GetUserFromWeb) ([]dto.GetBudgetFromDb, error)
+		UpdateBudgetById(ctx echo.Context, data dto.UpdateBudgetFromWeb) error
+		DeleteBudget(ctx echo.Context, data dto.GetBudgetFromWeb) error
+		GetBudgetAlerts(ctx echo.Context, data dto.GetUserFromWeb) ([]dto.BudgetAlertFromDb, error)
+
+		GetLogLevel(ctx echo.Context) (dto.LogLevelFromWeb, error)
+		UpdateLogLevel(ctx echo.Context, data dto.LogLevelFromWeb) error
 	}
 )
 
-func NewService(storage repository.Storage) Service {
-	return &ServiceSubs{
-		Storage: storage,
+const (
+	DiscountPercent = "percent"
+	DiscountFixed   = "fixed"
+)
+
+func valueOr[T any](v *T, def T) T {
+	if v == nil {
+		return def
+	}
+	return *v
+}
+
+// validateServiceName rejects line breaks, the name is used in the subject
+// of reminder emails.
+func validateServiceName(name string) error {
+	if strings.ContainsAny(name, "\r\n") {
+		return fmt.Errorf("service_name must not contain line breaks")
+	}
+	return nil
+}
+
+func validatePromo(trialMonths int, discountType string, discountValue, discountMonths int) error {
+	if trialMonths < 0 {
+		return fmt.Errorf("invalid trial_months: %d", trialMonths)
+	}
+	if discountValue < 0 || discountMonths < 0 {
+		return fmt.Errorf("discount_value and discount_months must not be negative")
+	}
+	switch discountType {
+	case "":
+	case DiscountPercent:
+		if discountValue > 100 {
+			return fmt.Errorf("percent discount cannot exceed 100: %d", discountValue)
+		}
+	case DiscountFixed:
+	default:
+		return fmt.Errorf("invalid discount_type: %s", discountType)
 	}
+	return nil
+}
+
+func NewService(storage repository.Storage, cfg Config, leveler Leveler) (Service, error) {
+	if err := validateOverlapPolicy(cfg.GetSubsOverlapPolicy()); err != nil {
+		return nil, err
+	}
+	return &ServiceSubs{
+		Storage:       storage,
+		overlapPolicy: cfg.GetSubsOverlapPolicy(),
+		leveler:       leveler,
+	}, nil
 }
 
-func (s *ServiceSubs) AddNewSubs(ctx echo.Context, data dto.AddSubFromWeb) error {
+func (s *ServiceSubs) AddNewSubs(ctx echo.Context, data dto.AddSubFromWeb) ([]dto.GetSubFromDb, error) {
+	var err error
+	if data.UserId, err = scopeUser(ctx, data.UserId); err != nil {
+		return nil, err
+	}
+	if err := s.checkUser(ctx, data.UserId); err != nil {
+		return nil, err
+	}
+	if err := validateServiceName(data.ServiceName); err != nil {
+		return nil, err
+	}
+	if err := validatePromo(data.TrialMonths, data.DiscountType, data.DiscountValue, data.DiscountMonths); err != nil {
+		return nil, err
+	}
+	status := data.Status
+	if status == "" {
+		status = SubStatusActive
+		if data.TrialMonths > 0 {
+			status = SubStatusTrial
+		}
+	}
+	if status != SubStatusActive && status != SubStatusTrial {
+		return nil, fmt.Errorf("new sub cannot be %s", status)
+	}
+	if err := validateBillingPeriod(data.BillingPeriod); err != nil {
+		return nil, err
+	}
+	period := data.BillingPeriod
+	if period == "" {
+		period = BillingMonth
+	}
+	if err := validateLength(period, data.Periods, data.Month); err != nil {
+		return nil, err
+	}
 	sdate, err := time.Parse("01-2006", data.StartDate)
 	if err != nil {
-		return fmt.Errorf("%w", err)
-	}
-	month := data.Month
-	if month <= 0 {
-		month = 1
+		return nil, fmt.Errorf("%w", err)
 	}
-	edate := sdate.AddDate(0, month, 0)
+	edate := addPeriods(sdate, period, periodsOrMonth(data.Periods, data.Month))
 	dataOut := dto.AddSubToDb{
-		ServiceName: data.ServiceName,
-		Price:       data.Price,
+		ServiceName:    data.ServiceName,
+		Category:       data.Category,
+		Price:          data.Price,
+		UserId:         data.UserId,
+		StartDate:      sdate,
+		EndDate:        edate,
+		BillingPeriod:  period,
+		AutoRenew:      data.AutoRenew,
+		Status:         status,
+		RejectOverlaps: s.overlapPolicy == OverlapReject,
+		TrialMonths:    data.TrialMonths,
+		DiscountType:   data.DiscountType,
+		DiscountValue:  data.DiscountValue,
+		DiscountMonths: data.DiscountMonths,
+	}
+	overlaps, err := s.checkOverlaps(ctx, dto.FindSubOverlapsToDb{
 		UserId:      data.UserId,
+		ServiceName: data.ServiceName,
 		StartDate:   sdate,
 		EndDate:     edate,
+	})
+	if err != nil {
+		return nil, err
 	}
 	if err := s.Storage.AddNewSubs(ctx, dataOut); err != nil {
-		return fmt.Errorf("%w", err)
+		return nil, fmt.Errorf("%w", err)
 	}
-	return nil
+	return overlaps, nil
 }
 
 func (s *ServiceSubs) GetSubById(ctx echo.Context, data dto.GetSubFromWeb) (dto.GetSubFromDb, error) {
@@ -59,18 +202,34 @@ func (s *ServiceSubs) GetSubById(ctx echo.Context, data dto.GetSubFromWeb) (dto.
 	if err != nil {
 		return dto.GetSubFromDb{}, fmt.Errorf("%w", err)
 	}
+	if err := checkOwner(ctx, dataOut.UserId); err != nil {
+		return dto.GetSubFromDb{}, err
+	}
 	return dataOut, nil
 }
 
-func (s *ServiceSubs) GetListSub(ctx echo.Context) ([]dto.GetSubFromDb, error) {
-	data, err := s.Storage.GetListSub(ctx)
+func (s *ServiceSubs) GetListSub(ctx echo.Context, data dto.GetSubListFromWeb) ([]dto.GetSubFromDb, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if err := validateStatuses(data.Status); err != nil {
+		return nil, err
+	}
+	dataOut, err := s.Storage.GetListSub(ctx, data)
 	if err != nil {
 		return nil, err
 	}
-	return data, nil
+	return dataOut, nil
 }
 
 func (s *ServiceSubs) GetListSubByUser(ctx echo.Context, data dto.GetSubByUserFromWeb) ([]dto.GetSubFromDb, error) {
+	var err error
+	if data.UserId, err = scopeUser(ctx, data.UserId); err != nil {
+		return nil, err
+	}
+	if err := validateStatuses(data.Status); err != nil {
+		return nil, err
+	}
 	dataOut, err := s.Storage.GetListSubByUser(ctx, data)
 	if err != nil {
 		return nil, err
@@ -79,6 +238,10 @@ func (s *ServiceSubs) GetListSubByUser(ctx echo.Context, data dto.GetSubByUserFr
 }
 
 func (s *ServiceSubs) GetPriceSubByFilter(ctx echo.Context, dataIn dto.GetSubPriceByFilterFromWeb) (dto.GetSubPriceByFilterFromDb, error) {
+	userId, err := scopeUser(ctx, dataIn.UserId)
+	if err != nil {
+		return dto.GetSubPriceByFilterFromDb{}, err
+	}
 	sdate, err := time.Parse("01-2006", dataIn.StartDate)
 	if err != nil {
 		return dto.GetSubPriceByFilterFromDb{}, fmt.Errorf("%w", err)
@@ -89,7 +252,7 @@ func (s *ServiceSubs) GetPriceSubByFilter(ctx echo.Context, dataIn dto.GetSubPri
 	}
 	data := dto.GetSubPriceByFilterToDb{
 		ServiceName: dataIn.ServiceName,
-		UserId:      dataIn.UserId,
+		UserId:      userId,
 		StartDate:   sdate,
 		EndDate:     edate,
 	}
@@ -101,38 +264,143 @@ func (s *ServiceSubs) GetPriceSubByFilter(ctx echo.Context, dataIn dto.GetSubPri
 	return dataOut, nil
 }
 
-func (s *ServiceSubs) UpdateSubById(ctx echo.Context, data dto.UpdateSubFromWeb) error {
+func (s *ServiceSubs) UpdateSubById(ctx echo.Context, data dto.UpdateSubFromWeb) ([]dto.GetSubFromDb, error) {
+	if err := s.authorizeSub(ctx, data.Id); err != nil {
+		return nil, err
+	}
+	if data.UserId != "" {
+		if err := checkOwner(ctx, data.UserId); err != nil {
+			return nil, err
+		}
+		if err := s.checkUser(ctx, data.UserId); err != nil {
+			return nil, err
+		}
+	}
+	if err := validateServiceName(data.ServiceName); err != nil {
+		return nil, err
+	}
+	if err := validateBillingPeriod(data.BillingPeriod); err != nil {
+		return nil, err
+	}
+	current, err := s.Storage.GetSubById(ctx, dto.GetSubFromWeb{Id: data.Id})
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+	// Price history rows are stored per period of the sub, switching the
+	// period would reinterpret them.
+	if data.BillingPeriod != "" && data.BillingPeriod != current.BillingPeriod {
+		return nil, fmt.Errorf("billing_period cannot be changed, create a new sub instead")
+	}
 	sdate, edate := time.Time{}, time.Time{}
 	if data.StartDate != "" {
-		var err error
 		sdate, err = time.Parse("01-2006", data.StartDate)
 		if err != nil {
-			return fmt.Errorf("%w", err)
+			return nil, fmt.Errorf("%w", err)
+		}
+		if err := validateLength(current.BillingPeriod, data.Periods, data.Month); err != nil {
+			return nil, err
 		}
-		month := data.Month
-		if month <= 0 {
-			month = 1
+		edate = addPeriods(sdate, current.BillingPeriod, periodsOrMonth(data.Periods, data.Month))
+	}
+	// The PATCH may change a single promo field, validate it against the
+	// stored values of the others.
+	if err := validatePromo(
+		valueOr(data.TrialMonths, current.TrialMonths),
+		valueOr(data.DiscountType, current.DiscountType),
+		valueOr(data.DiscountValue, current.DiscountValue),
+		valueOr(data.DiscountMonths, current.DiscountMonths)); err != nil {
+		return nil, err
+	}
+	priceFrom := time.Time{}
+	if data.PriceFrom != "" {
+		if data.Price == 0 {
+			return nil, fmt.Errorf("price_from requires price")
+		}
+		priceFrom, err = time.Parse("01-2006", data.PriceFrom)
+		if err != nil {
+			return nil, fmt.Errorf("%w", err)
 		}
-		edate = sdate.AddDate(0, month, 0)
+	} else if data.Price != 0 {
+		now := time.Now().UTC()
+		priceFrom = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
 	}
 	dataOut := dto.UpdateSubToDb{
-		Id:          data.Id,
-		ServiceName: data.ServiceName,
-		Price:       data.Price,
-		UserId:      data.UserId,
-		StartDate:   sdate,
-		EndDate:     edate,
+		Id:             data.Id,
+		ServiceName:    data.ServiceName,
+		Category:       data.Category,
+		Price:          data.Price,
+		UserId:         data.UserId,
+		StartDate:      sdate,
+		EndDate:        edate,
+		AutoRenew:      data.AutoRenew,
+		PriceFrom:      priceFrom,
+		RejectOverlaps: s.overlapPolicy == OverlapReject,
+		TrialMonths:    data.TrialMonths,
+		DiscountType:   data.DiscountType,
+		DiscountValue:  data.DiscountValue,
+		DiscountMonths: data.DiscountMonths,
+	}
+	overlaps, err := s.checkUpdateOverlaps(ctx, dataOut)
+	if err != nil {
+		return nil, err
 	}
 	if err := s.Storage.UpdateSubById(ctx, dataOut); err != nil {
-		return fmt.Errorf("%w", err)
+		return nil, fmt.Errorf("%w", err)
 	}
-	return nil
+	return overlaps, nil
+}
+
+func (s *ServiceSubs) checkUpdateOverlaps(ctx echo.Context, data dto.UpdateSubToDb) ([]dto.GetSubFromDb, error) {
+	if s.overlapPolicy == OverlapOff {
+		return nil, nil
+	}
+	if data.UserId == "" && data.ServiceName == "" && data.StartDate.IsZero() {
+		return nil, nil
+	}
+	sub, err := s.Storage.GetSubById(ctx, dto.GetSubFromWeb{Id: data.Id})
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+	if sub.Status != SubStatusTrial && sub.Status != SubStatusActive && sub.Status != SubStatusPaused {
+		return nil, nil
+	}
+	find := dto.FindSubOverlapsToDb{
+		Id:          sub.Id,
+		UserId:      sub.UserId,
+		ServiceName: sub.ServiceName,
+		StartDate:   sub.StartDate,
+		EndDate:     sub.EndDate,
+	}
+	if data.UserId != "" {
+		find.UserId = data.UserId
+	}
+	if data.ServiceName != "" {
+		find.ServiceName = data.ServiceName
+	}
+	if !data.StartDate.IsZero() {
+		find.StartDate, find.EndDate = data.StartDate, data.EndDate
+	}
+	return s.checkOverlaps(ctx, find)
 }
 
 func (s *ServiceSubs) DeleteSub(ctx echo.Context, data dto.GetSubFromWeb) error {
+	if err := requireAdmin(ctx); err != nil {
+		return err
+	}
 	err := s.Storage.DeleteSub(ctx, data)
 	if err != nil {
 		return fmt.Errorf("%w", err)
 	}
 	return nil
 }
+
+func (s *ServiceSubs) GetSubPrices(ctx echo.Context, data dto.GetSubFromWeb) ([]dto.SubPriceFromDb, error) {
+	if err := s.authorizeSub(ctx, data.Id); err != nil {
+		return nil, err
+	}
+	dataOut, err := s.Storage.GetSubPrices(ctx, data)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+	return dataOut, nil
+}
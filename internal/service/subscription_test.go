@@ -0,0 +1,52 @@
+package service
+
+import "testing"
+
+func TestValidatePromo(t *testing.T) {
+	tests := []struct {
+		name           string
+		trialMonths    int
+		discountType   string
+		discountValue  int
+		discountMonths int
+		wantErr        bool
+	}{
+		{name: "empty"},
+		{name: "trial only", trialMonths: 2},
+		{name: "percent", discountType: DiscountPercent, discountValue: 100, discountMonths: 3},
+		{name: "fixed above 100", discountType: DiscountFixed, discountValue: 500, discountMonths: 1},
+		{name: "negative trial", trialMonths: -1, wantErr: true},
+		{name: "negative value", discountType: DiscountFixed, discountValue: -1, wantErr: true},
+		{name: "negative months", discountType: DiscountFixed, discountMonths: -1, wantErr: true},
+		{name: "percent above 100", discountType: DiscountPercent, discountValue: 150, wantErr: true},
+		{name: "unknown type", discountType: "bogus", discountValue: 10, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validatePromo(tt.trialMonths, tt.discountType, tt.discountValue, tt.discountMonths)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("validatePromo() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateServiceName(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		wantErr bool
+	}{
+		{name: "plain", value: "Yandex Plus"},
+		{name: "empty", value: ""},
+		{name: "line feed", value: "Yandex\nBcc: x@example.com", wantErr: true},
+		{name: "carriage return", value: "Yandex\r", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateServiceName(tt.value); (err != nil) != tt.wantErr {
+				t.Fatalf("validateServiceName(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"fmt"
+	"service/internal/dto"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	defaultUpcomingDays  = 7
+	defaultUpcomingLimit = 50
+	maxUpcomingLimit     = 500
+)
+
+func (s *ServiceSubs) GetUpcomingSubs(ctx echo.Context, dataIn dto.GetUpcomingSubsFromWeb) (dto.GetUpcomingSubsFromDb, error) {
+	if dataIn.Days < 0 || dataIn.Offset < 0 || dataIn.Limit < 0 {
+		return dto.GetUpcomingSubsFromDb{}, fmt.Errorf("days, limit and offset must not be negative")
+	}
+	var err error
+	if dataIn.UserId, err = scopeUser(ctx, dataIn.UserId); err != nil {
+		return dto.GetUpcomingSubsFromDb{}, err
+	}
+	if dataIn.UserId != "" {
+		if err := validateUUID(dataIn.UserId); err != nil {
+			return dto.GetUpcomingSubsFromDb{}, err
+		}
+	}
+	if err := validateStatuses(dataIn.Status); err != nil {
+		return dto.GetUpcomingSubsFromDb{}, err
+	}
+	days := dataIn.Days
+	if days == 0 {
+		days = defaultUpcomingDays
+	}
+	limit := dataIn.Limit
+	if limit == 0 {
+		limit = defaultUpcomingLimit
+	}
+	if limit > maxUpcomingLimit {
+		limit = maxUpcomingLimit
+	}
+	status := dataIn.Status
+	if len(status) == 0 {
+		status = []string{SubStatusTrial, SubStatusActive}
+	}
+	now := time.Now()
+	data := dto.GetUpcomingSubsToDb{
+		From:        now,
+		To:          now.AddDate(0, 0, days),
+		UserId:      dataIn.UserId,
+		ServiceName: dataIn.ServiceName,
+		Status:      status,
+		Limit:       limit,
+		Offset:      dataIn.Offset,
+	}
+	dataOut, err := s.Storage.GetUpcomingSubs(ctx, data)
+	if err != nil {
+		return dto.GetUpcomingSubsFromDb{}, fmt.Errorf("%w", err)
+	}
+	return dataOut, nil
+}
@@ -0,0 +1,216 @@
+package service
+
+import (
+	"fmt"
+	"net/mail"
+	"regexp"
+	"service/internal/dto"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	UserStatusActive  = "active"
+	UserStatusBlocked = "blocked"
+
+	nextRenewalsLimit = 5
+)
+
+var uuidRegexp = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+func validateUUID(id string) error {
+	if !uuidRegexp.MatchString(id) {
+		return fmt.Errorf("invalid user ID: %s", id)
+	}
+	return nil
+}
+
+func validateUserFields(email, timezone, status string) error {
+	if email != "" {
+		if strings.ContainsAny(email, "\r\n") {
+			return fmt.Errorf("email must not contain line breaks")
+		}
+		if _, err := mail.ParseAddress(email); err != nil {
+			return fmt.Errorf("invalid email %s: %w", email, err)
+		}
+	}
+	if timezone != "" {
+		if _, err := time.LoadLocation(timezone); err != nil {
+			return fmt.Errorf("invalid timezone %s: %w", timezone, err)
+		}
+	}
+	switch status {
+	case "", UserStatusActive, UserStatusBlocked:
+	default:
+		return fmt.Errorf("invalid user status: %s", status)
+	}
+	return nil
+}
+
+func (s *ServiceSubs) checkUser(ctx echo.Context, userId string) error {
+	if err := validateUUID(userId); err != nil {
+		return err
+	}
+	if _, err := s.Storage.GetUserById(ctx, dto.GetUserFromWeb{Id: userId}); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+	return nil
+}
+
+func (s *ServiceSubs) AddNewUser(ctx echo.Context, data dto.AddUserFromWeb) (dto.GetUserFromDb, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return dto.GetUserFromDb{}, err
+	}
+	if data.DisplayName == "" {
+		return dto.GetUserFromDb{}, fmt.Errorf("display_name is required")
+	}
+	if err := validateUserFields(data.Email, data.Timezone, ""); err != nil {
+		return dto.GetUserFromDb{}, err
+	}
+	dataOut := dto.AddUserToDb{
+		DisplayName: data.DisplayName,
+		Timezone:    data.Timezone,
+		Status:      UserStatusActive,
+	}
+	if data.Email != "" {
+		dataOut.Email = &data.Email
+	}
+	if dataOut.Timezone == "" {
+		dataOut.Timezone = "UTC"
+	}
+	user, err := s.Storage.AddNewUser(ctx, dataOut)
+	if err != nil {
+		return dto.GetUserFromDb{}, fmt.Errorf("%w", err)
+	}
+	return user, nil
+}
+
+func (s *ServiceSubs) GetUserById(ctx echo.Context, data dto.GetUserFromWeb) (dto.GetUserFromDb, error) {
+	if err := validateUUID(data.Id); err != nil {
+		return dto.GetUserFromDb{}, err
+	}
+	if err := checkOwner(ctx, data.Id); err != nil {
+		return dto.GetUserFromDb{}, err
+	}
+	dataOut, err := s.Storage.GetUserById(ctx, data)
+	if err != nil {
+		return dto.GetUserFromDb{}, fmt.Errorf("%w", err)
+	}
+	return dataOut, nil
+}
+
+func (s *ServiceSubs) GetListUsers(ctx echo.Context) ([]dto.GetUserFromDb, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	data, err := s.Storage.GetListUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (s *ServiceSubs) UpdateUserById(ctx echo.Context, data dto.UpdateUserFromWeb) error {
+	if err := validateUUID(data.Id); err != nil {
+		return err
+	}
+	if err := checkOwner(ctx, data.Id); err != nil {
+		return err
+	}
+	if data.Status != "" {
+		if err := requireAdmin(ctx); err != nil {
+			return err
+		}
+	}
+	if err := validateUserFields(data.Email, data.Timezone, data.Status); err != nil {
+		return err
+	}
+	dataOut := dto.UpdateUserToDb{
+		Id:          data.Id,
+		DisplayName: data.DisplayName,
+		Email:       data.Email,
+		Timezone:    data.Timezone,
+		Status:      data.Status,
+		Reminders:   data.Reminders,
+	}
+	if err := s.Storage.UpdateUserById(ctx, dataOut); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+	return nil
+}
+
+func (s *ServiceSubs) DeleteUser(ctx echo.Context, data dto.GetUserFromWeb) error {
+	if err := requireAdmin(ctx); err != nil {
+		return err
+	}
+	if err := validateUUID(data.Id); err != nil {
+		return err
+	}
+	if err := s.Storage.DeleteUser(ctx, data); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+	return nil
+}
+
+func (s *ServiceSubs) GetUserSummary(ctx echo.Context, data dto.GetUserFromWeb) (dto.GetUserSummaryFromDb, error) {
+	user, err := s.GetUserById(ctx, data)
+	if err != nil {
+		return dto.GetUserSummaryFromDb{}, err
+	}
+	subs, err := s.Storage.GetActiveSubsByUser(ctx, dto.GetSubByUserFromWeb{UserId: data.Id})
+	if err != nil {
+		return dto.GetUserSummaryFromDb{}, fmt.Errorf("%w", err)
+	}
+	// The monthly spend comes from the same per-month costs as the cost and
+	// analytics endpoints, so trials and discounts are accounted for.
+	now := time.Now()
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	spend, err := s.Storage.GetSpendByMonth(ctx, dto.GetSpendAnalyticsToDb{
+		StartDate: month,
+		EndDate:   month,
+		UserId:    data.Id,
+	})
+	if err != nil {
+		return dto.GetUserSummaryFromDb{}, fmt.Errorf("%w", err)
+	}
+	dataOut := dto.GetUserSummaryFromDb{
+		User:         user,
+		ActiveSubs:   subs,
+		NextRenewals: nextRenewals(subs, now),
+	}
+	for _, row := range spend {
+		dataOut.MonthlySpend += row.Total
+	}
+	return dataOut, nil
+}
+
+// nextRenewals returns the first nextRenewalsLimit subs that will renew after
+// now. subs is expected to be ordered by end date.
+func nextRenewals(subs []dto.GetSubFromDb, now time.Time) []dto.GetSubFromDb {
+	renewals := make([]dto.GetSubFromDb, 0, nextRenewalsLimit)
+	for _, sub := range subs {
+		if len(renewals) == nextRenewalsLimit {
+			break
+		}
+		if sub.AutoRenew && sub.EndDate.After(now) {
+			renewals = append(renewals, sub)
+		}
+	}
+	return renewals
+}
+
+func (s *ServiceSubs) GetUserReminders(ctx echo.Context, data dto.GetUserFromWeb) ([]dto.SubReminderFromDb, error) {
+	if err := validateUUID(data.Id); err != nil {
+		return nil, err
+	}
+	if err := checkOwner(ctx, data.Id); err != nil {
+		return nil, err
+	}
+	dataOut, err := s.Storage.GetUserReminders(ctx, data)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+	return dataOut, nil
+}
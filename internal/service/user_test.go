@@ -0,0 +1,123 @@
+package service
+
+import (
+	"service/internal/datasource/repository"
+	"service/internal/dto"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// summaryStorage serves the reads GetUserSummary makes; any other call panics
+// on the nil embedded Storage.
+type summaryStorage struct {
+	repository.Storage
+	subs  []dto.GetSubFromDb
+	spend []dto.SpendByMonthFromDb
+	query dto.GetSpendAnalyticsToDb
+}
+
+func (s *summaryStorage) GetUserById(_ echo.Context, data dto.GetUserFromWeb) (dto.GetUserFromDb, error) {
+	return dto.GetUserFromDb{Id: data.Id}, nil
+}
+
+func (s *summaryStorage) GetActiveSubsByUser(_ echo.Context, _ dto.GetSubByUserFromWeb) ([]dto.GetSubFromDb, error) {
+	return s.subs, nil
+}
+
+func (s *summaryStorage) GetSpendByMonth(_ echo.Context, data dto.GetSpendAnalyticsToDb) ([]dto.SpendByMonthFromDb, error) {
+	s.query = data
+	return s.spend, nil
+}
+
+func TestGetUserSummary(t *testing.T) {
+	now := time.Now()
+	sub := func(id int, autoRenew bool, end time.Time) dto.GetSubFromDb {
+		return dto.GetSubFromDb{Id: id, Price: 1000, AutoRenew: autoRenew, EndDate: end}
+	}
+	tests := []struct {
+		name      string
+		subs      []dto.GetSubFromDb
+		spend     []dto.SpendByMonthFromDb
+		renewals  []int
+		wantSpend int
+	}{
+		{name: "no subs"},
+		{
+			name: "skips subs without auto renew",
+			subs: []dto.GetSubFromDb{
+				sub(1, false, now.AddDate(0, 0, 1)),
+				sub(2, true, now.AddDate(0, 0, 2)),
+				sub(3, false, now.AddDate(0, 0, 3)),
+			},
+			renewals: []int{2},
+		},
+		{
+			name: "skips ended subs",
+			subs: []dto.GetSubFromDb{
+				sub(1, true, now.AddDate(0, 0, -1)),
+				sub(2, true, now.AddDate(0, 0, 1)),
+			},
+			renewals: []int{2},
+		},
+		{
+			name: "limits after filtering",
+			subs: []dto.GetSubFromDb{
+				sub(1, false, now.AddDate(0, 0, 1)),
+				sub(2, true, now.AddDate(0, 0, 2)),
+				sub(3, true, now.AddDate(0, 0, 3)),
+				sub(4, true, now.AddDate(0, 0, 4)),
+				sub(5, true, now.AddDate(0, 0, 5)),
+				sub(6, true, now.AddDate(0, 0, 6)),
+				sub(7, true, now.AddDate(0, 0, 7)),
+			},
+			renewals: []int{2, 3, 4, 5, 6},
+		},
+		{
+			name:      "spend from monthly costs",
+			subs:      []dto.GetSubFromDb{sub(1, true, now.AddDate(0, 0, 1))},
+			spend:     []dto.SpendByMonthFromDb{{Total: 500}},
+			renewals:  []int{1},
+			wantSpend: 500,
+		},
+		{
+			name:      "spend sums all rows",
+			spend:     []dto.SpendByMonthFromDb{{Total: 500}, {Total: 250}},
+			wantSpend: 750,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage := &summaryStorage{subs: tt.subs, spend: tt.spend}
+			s := &ServiceSubs{Storage: storage}
+			got, err := s.GetUserSummary(newTestContext(anonymous), dto.GetUserFromWeb{Id: ownUser})
+			if err != nil {
+				t.Fatalf("GetUserSummary() error = %v", err)
+			}
+			if len(got.ActiveSubs) != len(tt.subs) {
+				t.Fatalf("GetUserSummary() active subs = %d, want %d", len(got.ActiveSubs), len(tt.subs))
+			}
+			ids := make([]int, 0, len(got.NextRenewals))
+			for _, sub := range got.NextRenewals {
+				ids = append(ids, sub.Id)
+			}
+			if len(ids) != len(tt.renewals) {
+				t.Fatalf("GetUserSummary() next renewals = %v, want %v", ids, tt.renewals)
+			}
+			for i := range ids {
+				if ids[i] != tt.renewals[i] {
+					t.Fatalf("GetUserSummary() next renewals = %v, want %v", ids, tt.renewals)
+				}
+			}
+			if got.MonthlySpend != tt.wantSpend {
+				t.Fatalf("GetUserSummary() monthly spend = %d, want %d", got.MonthlySpend, tt.wantSpend)
+			}
+			month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+			q := storage.query
+			if q.UserId != ownUser || !q.StartDate.Equal(month) || !q.EndDate.Equal(month) || q.GroupBy != "" {
+				t.Fatalf("GetSpendByMonth() query = %+v, want current month of %s", q, ownUser)
+			}
+		})
+	}
+}
@@ -0,0 +1,184 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/url"
+	"service/internal/datasource/repository"
+	"service/internal/dto"
+	"service/internal/events"
+	"slices"
+
+	"github.com/labstack/echo/v4"
+)
+
+var webhookEventTypes = []string{
+	repository.EventSubCreated,
+	repository.EventSubUpdated,
+	repository.EventSubDeleted,
+	repository.EventSubStatusChanged,
+	repository.EventSubRenewed,
+	repository.EventSubExpired,
+	repository.EventBudgetExceeded,
+}
+
+func validateWebhookURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("invalid webhook url: %w", err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid webhook url: %s", raw)
+	}
+	// Hostnames are checked by the delivery worker once resolved; literal
+	// addresses can be refused right away.
+	if ip := net.ParseIP(u.Hostname()); ip != nil {
+		if err := events.CheckWebhookIP(ip); err != nil {
+			return fmt.Errorf("invalid webhook url: %w", err)
+		}
+	}
+	return nil
+}
+
+func validateEventTypes(types []string) error {
+	for _, t := range types {
+		if !slices.Contains(webhookEventTypes, t) {
+			return fmt.Errorf("invalid event type: %s", t)
+		}
+	}
+	return nil
+}
+
+func newWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("%w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func (s *ServiceSubs) AddWebhook(ctx echo.Context, data dto.AddWebhookFromWeb) (dto.GetWebhookFromDb, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return dto.GetWebhookFromDb{}, err
+	}
+	if err := validateWebhookURL(data.Url); err != nil {
+		return dto.GetWebhookFromDb{}, err
+	}
+	if err := validateEventTypes(data.EventTypes); err != nil {
+		return dto.GetWebhookFromDb{}, err
+	}
+	secret := data.Secret
+	if secret == "" {
+		var err error
+		if secret, err = newWebhookSecret(); err != nil {
+			return dto.GetWebhookFromDb{}, err
+		}
+	}
+	eventTypes := data.EventTypes
+	if eventTypes == nil {
+		eventTypes = []string{}
+	}
+	dataOut, err := s.Storage.AddWebhook(ctx, dto.AddWebhookToDb{
+		Url:        data.Url,
+		Secret:     secret,
+		EventTypes: eventTypes,
+	})
+	if err != nil {
+		return dto.GetWebhookFromDb{}, fmt.Errorf("%w", err)
+	}
+	return dataOut, nil
+}
+
+func (s *ServiceSubs) GetWebhookById(ctx echo.Context, data dto.GetWebhookFromWeb) (dto.GetWebhookFromDb, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return dto.GetWebhookFromDb{}, err
+	}
+	dataOut, err := s.Storage.GetWebhookById(ctx, data)
+	if err != nil {
+		return dto.GetWebhookFromDb{}, fmt.Errorf("%w", err)
+	}
+	return dataOut, nil
+}
+
+func (s *ServiceSubs) GetListWebhooks(ctx echo.Context) ([]dto.GetWebhookFromDb, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	dataOut, err := s.Storage.GetListWebhooks(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+	return dataOut, nil
+}
+
+func (s *ServiceSubs) UpdateWebhookById(ctx echo.Context, data dto.UpdateWebhookFromWeb) error {
+	if err := requireAdmin(ctx); err != nil {
+		return err
+	}
+	if data.Url != "" {
+		if err := validateWebhookURL(data.Url); err != nil {
+			return err
+		}
+	}
+	if err := validateEventTypes(data.EventTypes); err != nil {
+		return err
+	}
+	dataOut := dto.UpdateWebhookToDb{
+		Id:         data.Id,
+		Url:        data.Url,
+		Secret:     data.Secret,
+		EventTypes: data.EventTypes,
+		Active:     data.Active,
+	}
+	if err := s.Storage.UpdateWebhookById(ctx, dataOut); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+	return nil
+}
+
+func (s *ServiceSubs) DeleteWebhook(ctx echo.Context, data dto.GetWebhookFromWeb) error {
+	if err := requireAdmin(ctx); err != nil {
+		return err
+	}
+	if err := s.Storage.DeleteWebhook(ctx, data); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+	return nil
+}
+
+func (s *ServiceSubs) GetWebhookDeliveries(ctx echo.Context, data dto.GetWebhookDeliveriesFromWeb) ([]dto.WebhookDeliveryFromDb, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if _, err := s.Storage.GetWebhookById(ctx, dto.GetWebhookFromWeb{Id: data.WebhookId}); err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+	dataOut, err := s.Storage.GetWebhookDeliveries(ctx, data)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+	return dataOut, nil
+}
+
+func (s *ServiceSubs) GetWebhookAttempts(ctx echo.Context, data dto.GetWebhookDeliveryFromWeb) ([]dto.WebhookAttemptFromDb, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	dataOut, err := s.Storage.GetWebhookAttempts(ctx, data)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+	return dataOut, nil
+}
+
+func (s *ServiceSubs) RedeliverWebhook(ctx echo.Context, data dto.GetWebhookDeliveryFromWeb) error {
+	if err := requireAdmin(ctx); err != nil {
+		return err
+	}
+	if err := s.Storage.RedeliverWebhook(ctx, data); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+	return nil
+}
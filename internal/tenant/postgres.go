@@ -0,0 +1,29 @@
+package tenant
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// BindPool makes every connection of cfg carry the tenant of the context that
+// acquired it in the app.tenant_id session variable used by the row-level
+// security policies. Connections acquired without a tenant see no rows.
+func BindPool(cfg *pgxpool.Config) {
+	cfg.BeforeAcquire = setSession
+	cfg.AfterRelease = resetSession
+}
+
+func setSession(ctx context.Context, conn *pgx.Conn) bool {
+	_, err := conn.Exec(ctx, `SELECT set_config('app.tenant_id', $1, false)`, FromContext(ctx))
+	return err == nil
+}
+
+// resetSession clears app.tenant_id when a connection returns to the pool, so
+// a tenant never outlives the request that set it. Connections that fail to
+// reset are destroyed.
+func resetSession(conn *pgx.Conn) bool {
+	_, err := conn.Exec(context.Background(), `RESET app.tenant_id`)
+	return err == nil
+}
@@ -0,0 +1,33 @@
+package tenant
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+)
+
+// Default is the tenant of requests when multi-tenancy is disabled and of
+// all rows created before it was introduced.
+const Default = "default"
+
+var idRegexp = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+type tenantKey struct{}
+
+func Validate(id string) error {
+	if !idRegexp.MatchString(id) {
+		return fmt.Errorf("invalid tenant: %q", id)
+	}
+	return nil
+}
+
+func WithTenant(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, id)
+}
+
+// FromContext returns the tenant of a request. Background jobs run without
+// a tenant and get an empty string.
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(tenantKey{}).(string)
+	return id
+}
@@ -0,0 +1,289 @@
+package tracing
+
+import (
+	"service/internal/dto"
+	"service/internal/service"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Service opens a span per service call. The span context is set on the
+// request for the duration of the call so repository and query spans nest
+// under it.
+type Service struct {
+	next service.Service
+}
+
+var _ service.Service = (*Service)(nil)
+
+func NewService(next service.Service) service.Service {
+	return &Service{next: next}
+}
+
+func (s *Service) start(ctx echo.Context, method string) func(error) {
+	req := ctx.Request()
+	c, span := tracer().Start(req.Context(), "service."+method)
+	ctx.SetRequest(req.WithContext(c))
+	return func(err error) {
+		ctx.SetRequest(req)
+		end(span, err)
+	}
+}
+
+func (s *Service) AddNewSubs(ctx echo.Context, data dto.AddSubFromWeb) (out []dto.GetSubFromDb, err error) {
+	done := s.start(ctx, "AddNewSubs")
+	defer func() { done(err) }()
+	return s.next.AddNewSubs(ctx, data)
+}
+
+func (s *Service) GetSubById(ctx echo.Context, data dto.GetSubFromWeb) (out dto.GetSubFromDb, err error) {
+	done := s.start(ctx, "GetSubById")
+	defer func() { done(err) }()
+	return s.next.GetSubById(ctx, data)
+}
+
+func (s *Service) GetListSub(ctx echo.Context, data dto.GetSubListFromWeb) (out []dto.GetSubFromDb, err error) {
+	done := s.start(ctx, "GetListSub")
+	defer func() { done(err) }()
+	return s.next.GetListSub(ctx, data)
+}
+
+func (s *Service) GetListSubByUser(ctx echo.Context, data dto.GetSubByUserFromWeb) (out []dto.GetSubFromDb, err error) {
+	done := s.start(ctx, "GetListSubByUser")
+	defer func() { done(err) }()
+	return s.next.GetListSubByUser(ctx, data)
+}
+
+func (s *Service) GetPriceSubByFilter(ctx echo.Context, data dto.GetSubPriceByFilterFromWeb) (out dto.GetSubPriceByFilterFromDb, err error) {
+	done := s.start(ctx, "GetPriceSubByFilter")
+	defer func() { done(err) }()
+	return s.next.GetPriceSubByFilter(ctx, data)
+}
+
+func (s *Service) UpdateSubById(ctx echo.Context, data dto.UpdateSubFromWeb) (out []dto.GetSubFromDb, err error) {
+	done := s.start(ctx, "UpdateSubById")
+	defer func() { done(err) }()
+	return s.next.UpdateSubById(ctx, data)
+}
+
+func (s *Service) DeleteSub(ctx echo.Context, data dto.GetSubFromWeb) (err error) {
+	done := s.start(ctx, "DeleteSub")
+	defer func() { done(err) }()
+	return s.next.DeleteSub(ctx, data)
+}
+
+func (s *Service) PauseSub(ctx echo.Context, data dto.GetSubFromWeb) (err error) {
+	done := s.start(ctx, "PauseSub")
+	defer func() { done(err) }()
+	return s.next.PauseSub(ctx, data)
+}
+
+func (s *Service) ResumeSub(ctx echo.Context, data dto.GetSubFromWeb) (err error) {
+	done := s.start(ctx, "ResumeSub")
+	defer func() { done(err) }()
+	return s.next.ResumeSub(ctx, data)
+}
+
+func (s *Service) CancelSub(ctx echo.Context, data dto.GetSubFromWeb) (err error) {
+	done := s.start(ctx, "CancelSub")
+	defer func() { done(err) }()
+	return s.next.CancelSub(ctx, data)
+}
+
+func (s *Service) GetSubStatusHistory(ctx echo.Context, data dto.GetSubFromWeb) (out []dto.SubStatusHistoryFromDb, err error) {
+	done := s.start(ctx, "GetSubStatusHistory")
+	defer func() { done(err) }()
+	return s.next.GetSubStatusHistory(ctx, data)
+}
+
+func (s *Service) GetSubPrices(ctx echo.Context, data dto.GetSubFromWeb) (out []dto.SubPriceFromDb, err error) {
+	done := s.start(ctx, "GetSubPrices")
+	defer func() { done(err) }()
+	return s.next.GetSubPrices(ctx, data)
+}
+
+func (s *Service) GetUpcomingSubs(ctx echo.Context, data dto.GetUpcomingSubsFromWeb) (out dto.GetUpcomingSubsFromDb, err error) {
+	done := s.start(ctx, "GetUpcomingSubs")
+	defer func() { done(err) }()
+	return s.next.GetUpcomingSubs(ctx, data)
+}
+
+func (s *Service) GetSubOverlaps(ctx echo.Context, data dto.GetSubOverlapsFromWeb) (out []dto.SubOverlapFromDb, err error) {
+	done := s.start(ctx, "GetSubOverlaps")
+	defer func() { done(err) }()
+	return s.next.GetSubOverlaps(ctx, data)
+}
+
+func (s *Service) GetSpendAnalytics(ctx echo.Context, data dto.GetSpendAnalyticsFromWeb) (out []dto.SpendByMonthFromDb, err error) {
+	done := s.start(ctx, "GetSpendAnalytics")
+	defer func() { done(err) }()
+	return s.next.GetSpendAnalytics(ctx, data)
+}
+
+func (s *Service) GetTopServices(ctx echo.Context, data dto.GetTopServicesFromWeb) (out []dto.TopServiceFromDb, err error) {
+	done := s.start(ctx, "GetTopServices")
+	defer func() { done(err) }()
+	return s.next.GetTopServices(ctx, data)
+}
+
+func (s *Service) GetChurn(ctx echo.Context, data dto.GetChurnFromWeb) (out []dto.ChurnByMonthFromDb, err error) {
+	done := s.start(ctx, "GetChurn")
+	defer func() { done(err) }()
+	return s.next.GetChurn(ctx, data)
+}
+
+func (s *Service) AddWebhook(ctx echo.Context, data dto.AddWebhookFromWeb) (out dto.GetWebhookFromDb, err error) {
+	done := s.start(ctx, "AddWebhook")
+	defer func() { done(err) }()
+	return s.next.AddWebhook(ctx, data)
+}
+
+func (s *Service) GetWebhookById(ctx echo.Context, data dto.GetWebhookFromWeb) (out dto.GetWebhookFromDb, err error) {
+	done := s.start(ctx, "GetWebhookById")
+	defer func() { done(err) }()
+	return s.next.GetWebhookById(ctx, data)
+}
+
+func (s *Service) GetListWebhooks(ctx echo.Context) (out []dto.GetWebhookFromDb, err error) {
+	done := s.start(ctx, "GetListWebhooks")
+	defer func() { done(err) }()
+	return s.next.GetListWebhooks(ctx)
+}
+
+func (s *Service) UpdateWebhookById(ctx echo.Context, data dto.UpdateWebhookFromWeb) (err error) {
+	done := s.start(ctx, "UpdateWebhookById")
+	defer func() { done(err) }()
+	return s.next.UpdateWebhookById(ctx, data)
+}
+
+func (s *Service) DeleteWebhook(ctx echo.Context, data dto.GetWebhookFromWeb) (err error) {
+	done := s.start(ctx, "DeleteWebhook")
+	defer func() { done(err) }()
+	return s.next.DeleteWebhook(ctx, data)
+}
+
+func (s *Service) GetWebhookDeliveries(ctx echo.Context, data dto.GetWebhookDeliveriesFromWeb) (out []dto.WebhookDeliveryFromDb, err error) {
+	done := s.start(ctx, "GetWebhookDeliveries")
+	defer func() { done(err) }()
+	return s.next.GetWebhookDeliveries(ctx, data)
+}
+
+func (s *Service) GetWebhookAttempts(ctx echo.Context, data dto.GetWebhookDeliveryFromWeb) (out []dto.WebhookAttemptFromDb, err error) {
+	done := s.start(ctx, "GetWebhookAttempts")
+	defer func() { done(err) }()
+	return s.next.GetWebhookAttempts(ctx, data)
+}
+
+func (s *Service) RedeliverWebhook(ctx echo.Context, data dto.GetWebhookDeliveryFromWeb) (err error) {
+	done := s.start(ctx, "RedeliverWebhook")
+	defer func() { done(err) }()
+	return s.next.RedeliverWebhook(ctx, data)
+}
+
+func (s *Service) AddAPIKey(ctx echo.Context, data dto.AddAPIKeyFromWeb) (out dto.GetAPIKeyFromDb, err error) {
+	done := s.start(ctx, "AddAPIKey")
+	defer func() { done(err) }()
+	return s.next.AddAPIKey(ctx, data)
+}
+
+func (s *Service) GetListAPIKeys(ctx echo.Context) (out []dto.GetAPIKeyFromDb, err error) {
+	done := s.start(ctx, "GetListAPIKeys")
+	defer func() { done(err) }()
+	return s.next.GetListAPIKeys(ctx)
+}
+
+func (s *Service) RevokeAPIKey(ctx echo.Context, data dto.GetAPIKeyFromWeb) (err error) {
+	done := s.start(ctx, "RevokeAPIKey")
+	defer func() { done(err) }()
+	return s.next.RevokeAPIKey(ctx, data)
+}
+
+func (s *Service) RotateAPIKey(ctx echo.Context, data dto.GetAPIKeyFromWeb) (out dto.GetAPIKeyFromDb, err error) {
+	done := s.start(ctx, "RotateAPIKey")
+	defer func() { done(err) }()
+	return s.next.RotateAPIKey(ctx, data)
+}
+
+func (s *Service) AddNewUser(ctx echo.Context, data dto.AddUserFromWeb) (out dto.GetUserFromDb, err error) {
+	done := s.start(ctx, "AddNewUser")
+	defer func() { done(err) }()
+	return s.next.AddNewUser(ctx, data)
+}
+
+func (s *Service) GetUserById(ctx echo.Context, data dto.GetUserFromWeb) (out dto.GetUserFromDb, err error) {
+	done := s.start(ctx, "GetUserById")
+	defer func() { done(err) }()
+	return s.next.GetUserById(ctx, data)
+}
+
+func (s *Service) GetListUsers(ctx echo.Context) (out []dto.GetUserFromDb, err error) {
+	done := s.start(ctx, "GetListUsers")
+	defer func() { done(err) }()
+	return s.next.GetListUsers(ctx)
+}
+
+func (s *Service) UpdateUserById(ctx echo.Context, data dto.UpdateUserFromWeb) (err error) {
+	done := s.start(ctx, "UpdateUserById")
+	defer func() { done(err) }()
+	return s.next.UpdateUserById(ctx, data)
+}
+
+func (s *Service) DeleteUser(ctx echo.Context, data dto.GetUserFromWeb) (err error) {
+	done := s.start(ctx, "DeleteUser")
+	defer func() { done(err) }()
+	return s.next.DeleteUser(ctx, data)
+}
+
+func (s *Service) GetUserSummary(ctx echo.Context, data dto.GetUserFromWeb) (out dto.GetUserSummaryFromDb, err error) {
+	done := s.start(ctx, "GetUserSummary")
+	defer func() { done(err) }()
+	return s.next.GetUserSummary(ctx, data)
+}
+
+func (s *Service) GetUserReminders(ctx echo.Context, data dto.GetUserFromWeb) (out []dto.SubReminderFromDb, err error) {
+	done := s.start(ctx, "GetUserReminders")
+	defer func() { done(err) }()
+	return s.next.GetUserReminders(ctx, data)
+}
+
+func (s *Service) AddBudget(ctx echo.Context, data dto.AddBudgetFromWeb) (out dto.GetBudgetFromDb, err error) {
+	done := s.start(ctx, "AddBudget")
+	defer func() { done(err) }()
+	return s.next.AddBudget(ctx, data)
+}
+
+func (s *Service) GetBudgetsByUser(ctx echo.Context, data dto.GetUserFromWeb) (out []dto.GetBudgetFromDb, err error) {
+	done := s.start(ctx, "GetBudgetsByUser")
+	defer func() { done(err) }()
+	return s.next.GetBudgetsByUser(ctx, data)
+}
+
+func (s *Service) UpdateBudgetById(ctx echo.Context, data dto.UpdateBudgetFromWeb) (err error) {
+	done := s.start(ctx, "UpdateBudgetById")
+	defer func() { done(err) }()
+	return s.next.UpdateBudgetById(ctx, data)
+}
+
+func (s *Service) DeleteBudget(ctx echo.Context, data dto.GetBudgetFromWeb) (err error) {
+	done := s.start(ctx, "DeleteBudget")
+	defer func() { done(err) }()
+	return s.next.DeleteBudget(ctx, data)
+}
+
+func (s *Service) GetBudgetAlerts(ctx echo.Context, data dto.GetUserFromWeb) (out []dto.BudgetAlertFromDb, err error) {
+	done := s.start(ctx, "GetBudgetAlerts")
+	defer func() { done(err) }()
+	return s.next.GetBudgetAlerts(ctx, data)
+}
+
+func (s *Service) GetLogLevel(ctx echo.Context) (out dto.LogLevelFromWeb, err error) {
+	done := s.start(ctx, "GetLogLevel")
+	defer func() { done(err) }()
+	return s.next.GetLogLevel(ctx)
+}
+
+func (s *Service) UpdateLogLevel(ctx echo.Context, data dto.LogLevelFromWeb) (err error) {
+	done := s.start(ctx, "UpdateLogLevel")
+	defer func() { done(err) }()
+	return s.next.UpdateLogLevel(ctx, data)
+}
@@ -0,0 +1,92 @@
+package tracing
+
+import (
+	"context"
+	"net/http/httptest"
+	"reflect"
+	"service/internal/datasource/repository"
+	"service/internal/service"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// panicking implementations embed the interface unset, so every call into
+// them panics after the wrapper has started its span.
+type (
+	panickingService struct{ service.Service }
+	panickingStorage struct{ repository.Storage }
+)
+
+var (
+	echoContextType = reflect.TypeFor[echo.Context]()
+	contextType     = reflect.TypeFor[context.Context]()
+)
+
+func newRecorder(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return recorder
+}
+
+// callAll calls every method of iface on impl with zero arguments, except
+// for contexts, and recovers from the panic of the wrapped implementation.
+func callAll(impl any, iface reflect.Type) {
+	v := reflect.ValueOf(impl)
+	for i := range iface.NumMethod() {
+		method := iface.Method(i)
+		fn := v.MethodByName(method.Name)
+		args := make([]reflect.Value, method.Type.NumIn())
+		for j := range args {
+			switch in := method.Type.In(j); in {
+			case echoContextType:
+				ctx := echo.New().NewContext(httptest.NewRequest("GET", "/", nil), httptest.NewRecorder())
+				args[j] = reflect.ValueOf(&ctx).Elem()
+			case contextType:
+				args[j] = reflect.ValueOf(context.Background())
+			default:
+				args[j] = reflect.Zero(in)
+			}
+		}
+		func() {
+			defer func() { recover() }()
+			fn.Call(args)
+		}()
+	}
+}
+
+// checkSpans fails unless every method of iface ended exactly one span named
+// prefix + method, which also catches wrappers reporting another method.
+func checkSpans(t *testing.T, recorder *tracetest.SpanRecorder, iface reflect.Type, prefix string) {
+	t.Helper()
+	names := make(map[string]int)
+	for _, span := range recorder.Ended() {
+		names[span.Name()]++
+	}
+	for i := range iface.NumMethod() {
+		name := prefix + iface.Method(i).Name
+		if names[name] != 1 {
+			t.Errorf("%s: %d spans, want 1", name, names[name])
+		}
+	}
+}
+
+func TestServiceSpans(t *testing.T) {
+	recorder := newRecorder(t)
+	iface := reflect.TypeFor[service.Service]()
+	callAll(NewService(panickingService{}), iface)
+	checkSpans(t, recorder, iface, "service.")
+}
+
+func TestStorageSpans(t *testing.T) {
+	recorder := newRecorder(t)
+	iface := reflect.TypeFor[repository.Storage]()
+	callAll(repository.NewObserved(panickingStorage{}, StorageHook), iface)
+	checkSpans(t, recorder, iface, "repository.")
+}
@@ -0,0 +1,110 @@
+package web
+
+import (
+	"net/http"
+	"service/internal/dto"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+// @Summary Monthly spend
+// @Description Get total spend per month over a range, optionally grouped by service, user or category
+// @Tags Analytics
+// @Accept  json
+// @Produce  json
+// @Param   sdate query string true "Start date (MM-YYYY)"
+// @Param   edate query string true "End date (MM-YYYY)"
+// @Param   group_by query string false "Group by: service, user or category"
+// @Param   serv query string false "Service name"
+// @Param   uuid query string false "User UUID"
+// @Param   category query string false "Category"
+// @Success 200 {object} Response "Success response"
+// @Failure 400 {object} Response "Bad request"
+// @Router /analytics/spend [get]
+func (r *routing) GetSpendAnalytics(ctx echo.Context) error {
+	logger := requestLogger(ctx)
+	var data dto.GetSpendAnalyticsFromWeb
+	data.StartDate = ctx.QueryParam("sdate")
+	data.EndDate = ctx.QueryParam("edate")
+	data.GroupBy = ctx.QueryParam("group_by")
+	data.ServiceName = ctx.QueryParam("serv")
+	data.UserId = ctx.QueryParam("uuid")
+	data.Category = ctx.QueryParam("category")
+	if data.StartDate == "" || data.EndDate == "" {
+		logger.Info("Not OK")
+		return ctx.JSON(http.StatusBadRequest, Response{Data: "Parameters sdate and edate are required"})
+	}
+	dataOut, err := r.service.GetSpendAnalytics(ctx, data)
+	if err != nil {
+		logger.Info("Not OK")
+		return ctx.JSON(errorStatus(err), Response{Data: err.Error()})
+	}
+	logger.Info("OK")
+	return ctx.JSON(http.StatusOK, Response{Data: dataOut})
+}
+
+// @Summary Top services
+// @Description Get top services by active subscriptions count and revenue over a range
+// @Tags Analytics
+// @Accept  json
+// @Produce  json
+// @Param   sdate query string true "Start date (MM-YYYY)"
+// @Param   edate query string true "End date (MM-YYYY)"
+// @Param   limit query int false "Number of services (default 10)"
+// @Param   order_by query string false "Order by: count or revenue"
+// @Success 200 {object} Response "Success response"
+// @Failure 400 {object} Response "Bad request"
+// @Router /analytics/top_services [get]
+func (r *routing) GetTopServices(ctx echo.Context) (err error) {
+	logger := requestLogger(ctx)
+	var data dto.GetTopServicesFromWeb
+	data.StartDate = ctx.QueryParam("sdate")
+	data.EndDate = ctx.QueryParam("edate")
+	data.OrderBy = ctx.QueryParam("order_by")
+	if data.StartDate == "" || data.EndDate == "" {
+		logger.Info("Not OK")
+		return ctx.JSON(http.StatusBadRequest, Response{Data: "Parameters sdate and edate are required"})
+	}
+	if limit := ctx.QueryParam("limit"); limit != "" {
+		if data.Limit, err = strconv.Atoi(limit); err != nil {
+			logger.Info("Not OK")
+			return ctx.JSON(http.StatusBadRequest, Response{Data: err.Error()})
+		}
+	}
+	dataOut, err := r.service.GetTopServices(ctx, data)
+	if err != nil {
+		logger.Info("Not OK")
+		return ctx.JSON(errorStatus(err), Response{Data: err.Error()})
+	}
+	logger.Info("OK")
+	return ctx.JSON(http.StatusOK, Response{Data: dataOut})
+}
+
+// @Summary Churn statistics
+// @Description Get new and ended subscriptions per month and churn rate (ended / active at month start)
+// @Tags Analytics
+// @Accept  json
+// @Produce  json
+// @Param   sdate query string true "Start date (MM-YYYY)"
+// @Param   edate query string true "End date (MM-YYYY)"
+// @Success 200 {object} Response "Success response"
+// @Failure 400 {object} Response "Bad request"
+// @Router /analytics/churn [get]
+func (r *routing) GetChurn(ctx echo.Context) error {
+	logger := requestLogger(ctx)
+	var data dto.GetChurnFromWeb
+	data.StartDate = ctx.QueryParam("sdate")
+	data.EndDate = ctx.QueryParam("edate")
+	if data.StartDate == "" || data.EndDate == "" {
+		logger.Info("Not OK")
+		return ctx.JSON(http.StatusBadRequest, Response{Data: "Parameters sdate and edate are required"})
+	}
+	dataOut, err := r.service.GetChurn(ctx, data)
+	if err != nil {
+		logger.Info("Not OK")
+		return ctx.JSON(errorStatus(err), Response{Data: err.Error()})
+	}
+	logger.Info("OK")
+	return ctx.JSON(http.StatusOK, Response{Data: dataOut})
+}
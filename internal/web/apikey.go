@@ -0,0 +1,102 @@
+package web
+
+import (
+	"net/http"
+	"service/internal/dto"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+// @Summary Add API key
+// @Description Create an API key for a service client; the key is returned only once
+// @Tags API keys
+// @Accept  json
+// @Produce  json
+// @Param   request body dto.AddAPIKeyFromWeb true "API key data"
+// @Success 200 {object} Response "Success response"
+// @Failure 400 {object} Response "Bad request"
+// @Router /add_api_key [post]
+func (r *routing) AddAPIKey(ctx echo.Context) error {
+	logger := requestLogger(ctx)
+	var data dto.AddAPIKeyFromWeb
+	if err := ctx.Bind(&data); err != nil {
+		logger.Info("Not OK")
+		return ctx.JSON(http.StatusBadRequest, Response{Data: err.Error()})
+	}
+	dataOut, err := r.service.AddAPIKey(ctx, data)
+	if err != nil {
+		logger.Info("add_api_key:Not OK ", data.Name)
+		return ctx.JSON(errorStatus(err), Response{Data: err.Error()})
+	}
+	logger.Info("add_api_key:OK ", data.Name)
+	return ctx.JSON(http.StatusOK, Response{Data: dataOut})
+}
+
+// @Summary Get all API keys
+// @Description Get the list of API keys without their secrets
+// @Tags API keys
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} Response "Success response"
+// @Failure 400 {object} Response "Bad request"
+// @Router /get_list_api_keys [get]
+func (r *routing) GetListAPIKeys(ctx echo.Context) error {
+	logger := requestLogger(ctx)
+	dataOut, err := r.service.GetListAPIKeys(ctx)
+	if err != nil {
+		logger.Info("Not OK")
+		return ctx.JSON(errorStatus(err), Response{Data: err.Error()})
+	}
+	logger.Info("OK")
+	return ctx.JSON(http.StatusOK, Response{Data: dataOut})
+}
+
+// @Summary Revoke API key
+// @Description Revoke an API key; it can no longer be used or rotated
+// @Tags API keys
+// @Accept  json
+// @Produce  json
+// @Param   id path int true "API key ID"
+// @Success 200 {object} Response "Success response"
+// @Failure 400 {object} Response "Bad request"
+// @Router /revoke_api_key/{id} [post]
+func (r *routing) RevokeAPIKey(ctx echo.Context) (err error) {
+	logger := requestLogger(ctx)
+	var data dto.GetAPIKeyFromWeb
+	if data.Id, err = strconv.Atoi(ctx.Param("id")); err != nil {
+		logger.Info("Not OK")
+		return ctx.JSON(http.StatusBadRequest, Response{Data: err.Error()})
+	}
+	if err := r.service.RevokeAPIKey(ctx, data); err != nil {
+		logger.Info("Not OK")
+		return ctx.JSON(errorStatus(err), Response{Data: err.Error()})
+	}
+	logger.Info("OK")
+	return ctx.JSON(http.StatusOK, Response{Data: "OK"})
+}
+
+// @Summary Rotate API key
+// @Description Issue a new secret for an active API key; the old secret stops working immediately
+// @Tags API keys
+// @Accept  json
+// @Produce  json
+// @Param   id path int true "API key ID"
+// @Success 200 {object} Response "Success response"
+// @Failure 400 {object} Response "Bad request"
+// @Router /rotate_api_key/{id} [post]
+func (r *routing) RotateAPIKey(ctx echo.Context) (err error) {
+	logger := requestLogger(ctx)
+	var data dto.GetAPIKeyFromWeb
+	if data.Id, err = strconv.Atoi(ctx.Param("id")); err != nil {
+		logger.Info("Not OK")
+		return ctx.JSON(http.StatusBadRequest, Response{Data: err.Error()})
+	}
+	dataOut, err := r.service.RotateAPIKey(ctx, data)
+	if err != nil {
+		logger.Info("Not OK")
+		return ctx.JSON(errorStatus(err), Response{Data: err.Error()})
+	}
+	logger.Info("OK")
+	return ctx.JSON(http.StatusOK, Response{Data: dataOut})
+}
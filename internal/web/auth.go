@@ -0,0 +1,53 @@
+package web
+
+import (
+	"net/http"
+	"service/internal/auth"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// exempted reports whether the request path matches one of the configured
+// exemptions; a trailing * matches any suffix.
+func (r *routing) exempted(path string) bool {
+	for _, pattern := range r.exempt {
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+			if strings.HasPrefix(path, prefix) {
+				return true
+			}
+		} else if path == pattern {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *routing) authenticate(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		if r.authn == nil || r.exempted(ctx.Request().URL.Path) {
+			return next(ctx)
+		}
+		req := ctx.Request()
+		principal, err := r.authn.Authenticate(req.Context(), req)
+		if err != nil {
+			logger := requestLogger(ctx)
+			logger.Info("auth:Not OK ", err)
+			r.failedAuth(ctx)
+			ctx.Response().Header().Set(echo.HeaderWWWAuthenticate, "Bearer")
+			return ctx.JSON(http.StatusUnauthorized, Response{Data: err.Error()})
+		}
+		// Every route that changes data uses a method other than GET or HEAD,
+		// so read-only API keys are stopped here for all of them.
+		if !safeMethod(req.Method) && !principal.CanWrite() {
+			return ctx.JSON(http.StatusForbidden, Response{Data: "api key has no write scope"})
+		}
+		ctx.SetRequest(req.WithContext(auth.WithPrincipal(req.Context(), principal)))
+		setRequestLogger(ctx, requestLogger(ctx).WithField("user", principal.Subject))
+		return next(ctx)
+	}
+}
+
+func safeMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead
+}
@@ -0,0 +1,62 @@
+package web
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"service/internal/auth"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+// scopeAuthenticator authenticates every request with the scopes named by
+// its X-API-Key header; "jwt" stands for a caller without scopes.
+type scopeAuthenticator struct{}
+
+func (scopeAuthenticator) Authenticate(_ context.Context, r *http.Request) (auth.Principal, error) {
+	p := auth.Principal{Subject: "svc"}
+	if key := r.Header.Get(auth.HeaderAPIKey); key != "jwt" {
+		p.Scopes = []string{key}
+	}
+	return p, nil
+}
+
+func TestAuthenticateScopes(t *testing.T) {
+	r := &routing{authn: scopeAuthenticator{}}
+	e := echo.New()
+	e.Use(r.authenticate)
+	ok := func(ctx echo.Context) error { return ctx.NoContent(http.StatusOK) }
+	e.GET("/", ok)
+	e.HEAD("/", ok)
+	e.POST("/", ok)
+	e.PATCH("/", ok)
+	e.DELETE("/", ok)
+
+	tests := []struct {
+		key    string
+		method string
+		want   int
+	}{
+		{key: auth.ScopeRead, method: http.MethodGet, want: http.StatusOK},
+		{key: auth.ScopeRead, method: http.MethodHead, want: http.StatusOK},
+		{key: auth.ScopeRead, method: http.MethodPost, want: http.StatusForbidden},
+		{key: auth.ScopeRead, method: http.MethodPatch, want: http.StatusForbidden},
+		{key: auth.ScopeRead, method: http.MethodDelete, want: http.StatusForbidden},
+		{key: auth.ScopeWrite, method: http.MethodGet, want: http.StatusOK},
+		{key: auth.ScopeWrite, method: http.MethodPatch, want: http.StatusOK},
+		{key: auth.ScopeAdmin, method: http.MethodDelete, want: http.StatusOK},
+		{key: "jwt", method: http.MethodPost, want: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key+" "+tt.method, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/", nil)
+			req.Header.Set(auth.HeaderAPIKey, tt.key)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Fatalf("%s with %s scope: status %d, want %d", tt.method, tt.key, rec.Code, tt.want)
+			}
+		})
+	}
+}
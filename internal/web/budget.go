@@ -0,0 +1,124 @@
+package web
+
+import (
+	"net/http"
+	"service/internal/dto"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+// @Summary Add budget
+// @Description Set a monthly budget for a user, optionally limited to one category
+// @Tags Budgets
+// @Accept  json
+// @Produce  json
+// @Param   request body dto.AddBudgetFromWeb true "Budget data"
+// @Success 200 {object} Response "Success response"
+// @Failure 400 {object} Response "Bad request"
+// @Router /add_budget [post]
+func (r *routing) AddBudget(ctx echo.Context) error {
+	logger := requestLogger(ctx)
+	var data dto.AddBudgetFromWeb
+	if err := ctx.Bind(&data); err != nil {
+		logger.Info("Not OK")
+		return ctx.JSON(http.StatusBadRequest, Response{Data: err.Error()})
+	}
+	dataOut, err := r.service.AddBudget(ctx, data)
+	if err != nil {
+		logger.Info("add_budget:Not OK ", data)
+		return ctx.JSON(errorStatus(err), Response{Data: err.Error()})
+	}
+	logger.Info("add_budget:OK ", data)
+	return ctx.JSON(http.StatusOK, Response{Data: dataOut})
+}
+
+// @Summary Get user budgets
+// @Description Get budgets of a user with projected spend for the current month
+// @Tags Budgets
+// @Accept  json
+// @Produce  json
+// @Param   uuid path string true "User UUID"
+// @Success 200 {object} Response "Success response"
+// @Failure 400 {object} Response "Bad request"
+// @Router /get_budgets_by_user/{uuid} [get]
+func (r *routing) GetBudgetsByUser(ctx echo.Context) error {
+	logger := requestLogger(ctx)
+	data := dto.GetUserFromWeb{Id: ctx.Param("uuid")}
+	dataOut, err := r.service.GetBudgetsByUser(ctx, data)
+	if err != nil {
+		logger.Info("Not OK")
+		return ctx.JSON(errorStatus(err), Response{Data: err.Error()})
+	}
+	logger.Info("OK")
+	return ctx.JSON(http.StatusOK, Response{Data: dataOut})
+}
+
+// @Summary Update budget
+// @Description Change the monthly amount of a budget
+// @Tags Budgets
+// @Accept  json
+// @Produce  json
+// @Param   request body dto.UpdateBudgetFromWeb true "Budget data to update"
+// @Success 200 {object} Response "Success response"
+// @Failure 400 {object} Response "Bad request"
+// @Router /update_budget [patch]
+func (r *routing) UpdateBudget(ctx echo.Context) error {
+	logger := requestLogger(ctx)
+	var data dto.UpdateBudgetFromWeb
+	if err := ctx.Bind(&data); err != nil {
+		logger.Info("Not OK")
+		return ctx.JSON(http.StatusBadRequest, Response{Data: err.Error()})
+	}
+	if err := r.service.UpdateBudgetById(ctx, data); err != nil {
+		logger.Info("Not OK")
+		return ctx.JSON(errorStatus(err), Response{Data: err.Error()})
+	}
+	logger.Info("OK")
+	return ctx.JSON(http.StatusOK, Response{Data: "OK"})
+}
+
+// @Summary Delete budget
+// @Description Delete budget together with its alerts
+// @Tags Budgets
+// @Accept  json
+// @Produce  json
+// @Param   id path int true "Budget ID"
+// @Success 200 {object} Response "Success response"
+// @Failure 400 {object} Response "Bad request"
+// @Router /delete_budget/{id} [delete]
+func (r *routing) DeleteBudget(ctx echo.Context) (err error) {
+	logger := requestLogger(ctx)
+	var data dto.GetBudgetFromWeb
+	if data.Id, err = strconv.Atoi(ctx.Param("id")); err != nil {
+		logger.Info("Not OK")
+		return ctx.JSON(http.StatusBadRequest, Response{Data: err.Error()})
+	}
+	if err := r.service.DeleteBudget(ctx, data); err != nil {
+		logger.Info("Not OK")
+		return ctx.JSON(errorStatus(err), Response{Data: err.Error()})
+	}
+	logger.Info("OK")
+	return ctx.JSON(http.StatusOK, Response{Data: "OK"})
+}
+
+// @Summary Get budget alerts
+// @Description Get overspend alerts raised for budgets of a user
+// @Tags Budgets
+// @Accept  json
+// @Produce  json
+// @Param   uuid path string true "User UUID"
+// @Success 200 {object} Response "Success response"
+// @Failure 400 {object} Response "Bad request"
+// @Router /get_budget_alerts/{uuid} [get]
+func (r *routing) GetBudgetAlerts(ctx echo.Context) error {
+	logger := requestLogger(ctx)
+	data := dto.GetUserFromWeb{Id: ctx.Param("uuid")}
+	dataOut, err := r.service.GetBudgetAlerts(ctx, data)
+	if err != nil {
+		logger.Info("Not OK")
+		return ctx.JSON(errorStatus(err), Response{Data: err.Error()})
+	}
+	logger.Info("OK")
+	return ctx.JSON(http.StatusOK, Response{Data: dataOut})
+}
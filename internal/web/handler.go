@@ -1,16 +1,47 @@
 package web
 
 import (
+	"errors"
 	"net/http"
 	"service/internal/dto"
+	"service/internal/service"
 	"strconv"
+	"strings"
 
 	"github.com/labstack/echo/v4"
-	"github.com/sirupsen/logrus"
 )
 
 type Response struct {
-	Data any `json:"data"`
+	Data     any `json:"data"`
+	Warnings any `json:"warnings,omitempty"`
+}
+
+// responseStatus returns the status code the error handler will send for err.
+func responseStatus(ctx echo.Context, err error) int {
+	if err == nil {
+		return ctx.Response().Status
+	}
+	var he *echo.HTTPError
+	if errors.As(err, &he) {
+		return he.Code
+	}
+	return http.StatusInternalServerError
+}
+
+func statusParam(ctx echo.Context) []string {
+	param := ctx.QueryParam("status")
+	if param == "" {
+		return nil
+	}
+	return strings.Split(param, ",")
+}
+
+// errorStatus maps a service error to the response status code.
+func errorStatus(err error) int {
+	if errors.Is(err, service.ErrForbidden) {
+		return http.StatusForbidden
+	}
+	return http.StatusBadRequest
 }
 
 // @Summary Test endpoint
@@ -34,16 +65,21 @@ func (r *routing) Hello(ctx echo.Context) error {
 // @Failure 400 {object} Response "Bad request"
 // @Router /add_sub [post]
 func (r *routing) AddSub(ctx echo.Context) error {
-	logger := ctx.Get("logger").(*logrus.Logger)
+	logger := requestLogger(ctx)
 	var data dto.AddSubFromWeb
 	if err := ctx.Bind(&data); err != nil {
 		logger.Info("Not OK")
 		return ctx.JSON(http.StatusBadRequest, Response{Data: err.Error()})
 	}
 	logger.Info(data)
-	if err := r.service.AddNewSubs(ctx, data); err != nil {
+	overlaps, err := r.service.AddNewSubs(ctx, data)
+	if err != nil {
 		logger.Info("add:Not OK ", data)
-		return ctx.JSON(http.StatusBadRequest, Response{Data: err.Error()})
+		return ctx.JSON(errorStatus(err), Response{Data: err.Error()})
+	}
+	if len(overlaps) > 0 {
+		logger.Warn("add:overlapping subs ", overlaps)
+		return ctx.JSON(http.StatusOK, Response{Data: "OK", Warnings: overlaps})
 	}
 	logger.Info("add:OK ", data)
 	return ctx.JSON(http.StatusOK, Response{Data: "OK"})
@@ -59,7 +95,7 @@ func (r *routing) AddSub(ctx echo.Context) error {
 // @Failure 400 {object} Response "Bad request"
 // @Router /get_sub_by_id/{id} [get]с
 func (r *routing) GetSubById(ctx echo.Context) (err error) {
-	logger := ctx.Get("logger").(*logrus.Logger)
+	logger := requestLogger(ctx)
 	var data dto.GetSubFromWeb
 	if data.Id, err = strconv.Atoi(ctx.Param("id")); err != nil {
 		logger.Info("Not OK")
@@ -68,7 +104,7 @@ func (r *routing) GetSubById(ctx echo.Context) (err error) {
 	dataOut, err := r.service.GetSubById(ctx, data)
 	if err != nil {
 		logger.Info("Not OK")
-		return ctx.JSON(http.StatusBadRequest, Response{Data: err.Error()})
+		return ctx.JSON(errorStatus(err), Response{Data: err.Error()})
 	}
 	logger.Info("OK")
 	return ctx.JSON(http.StatusOK, Response{Data: dataOut})
@@ -80,13 +116,15 @@ func (r *routing) GetSubById(ctx echo.Context) (err error) {
 // @Accept  json
 // @Produce  json
 // @Param   uuid path string true "User UUID"
+// @Param   status query string false "Status filter (trial, active, paused, cancelled, expired), comma separated"
 // @Success 200 {object} Response "Success response"
 // @Failure 400 {object} Response "Bad request"
 // @Router /get_list_by_user/{uuid} [get]
 func (r *routing) GetListSubByUser(ctx echo.Context) (err error) {
-	logger := ctx.Get("logger").(*logrus.Logger)
+	logger := requestLogger(ctx)
 	var data dto.GetSubByUserFromWeb
 	data.UserId = ctx.Param("uuid")
+	data.Status = statusParam(ctx)
 	if data.UserId == "" {
 		logger.Info("Not OK")
 		return ctx.JSON(http.StatusBadRequest, Response{Data: "incorrect uuid"})
@@ -94,7 +132,7 @@ func (r *routing) GetListSubByUser(ctx echo.Context) (err error) {
 	dataOut, err := r.service.GetListSubByUser(ctx, data)
 	if err != nil {
 		logger.Info("Not OK")
-		return ctx.JSON(http.StatusBadRequest, Response{Data: err.Error()})
+		return ctx.JSON(errorStatus(err), Response{Data: err.Error()})
 	}
 	logger.Info("OK")
 	return ctx.JSON(http.StatusOK, Response{Data: dataOut})
@@ -105,22 +143,24 @@ func (r *routing) GetListSubByUser(ctx echo.Context) (err error) {
 // @Tags Subscriptions
 // @Accept  json
 // @Produce  json
+// @Param   status query string false "Status filter (trial, active, paused, cancelled, expired), comma separated"
 // @Success 200 {object} Response "Success response"
 // @Failure 400 {object} Response "Bad request"
 // @Router /get_list [get]
 func (r *routing) GetListSub(ctx echo.Context) (err error) {
-	logger := ctx.Get("logger").(*logrus.Logger)
-	dataOut, err := r.service.GetListSub(ctx)
+	logger := requestLogger(ctx)
+	data := dto.GetSubListFromWeb{Status: statusParam(ctx)}
+	dataOut, err := r.service.GetListSub(ctx, data)
 	if err != nil {
 		logger.Info("Not OK")
-		return ctx.JSON(http.StatusBadRequest, Response{Data: err.Error()})
+		return ctx.JSON(errorStatus(err), Response{Data: err.Error()})
 	}
 	logger.Info("OK")
 	return ctx.JSON(http.StatusOK, Response{Data: dataOut})
 }
 
 // @Summary Get subscription price by filter
-// @Description Get subscription price based on filter criteria
+// @Description Get total cost of subscriptions for every month in range, paused months are not billed
 // @Tags Subscriptions
 // @Accept  json
 // @Produce  json
@@ -132,7 +172,7 @@ func (r *routing) GetListSub(ctx echo.Context) (err error) {
 // @Failure 400 {object} Response "Bad request"
 // @Router /get_price_subs [get]
 func (r *routing) GetPriceSubByFilter(ctx echo.Context) (err error) {
-	logger := ctx.Get("logger").(*logrus.Logger)
+	logger := requestLogger(ctx)
 	var data dto.GetSubPriceByFilterFromWeb
 	data.ServiceName = ctx.QueryParam("serv")
 	data.UserId = ctx.QueryParam("uuid")
@@ -145,14 +185,14 @@ func (r *routing) GetPriceSubByFilter(ctx echo.Context) (err error) {
 	dataOut, err := r.service.GetPriceSubByFilter(ctx, data)
 	if err != nil {
 		logger.Info("Not OK")
-		return ctx.JSON(http.StatusBadRequest, Response{Data: err.Error()})
+		return ctx.JSON(errorStatus(err), Response{Data: err.Error()})
 	}
 	logger.Info(dataOut)
 	return ctx.JSON(http.StatusOK, Response{Data: dataOut})
 }
 
 // @Summary Update subscription
-// @Description Update existing subscription
+// @Description Update existing subscription, a new price is appended to the price history from price_from (MM-YYYY, current month by default)
 // @Tags Subscriptions
 // @Accept  json
 // @Produce  json
@@ -161,15 +201,20 @@ func (r *routing) GetPriceSubByFilter(ctx echo.Context) (err error) {
 // @Failure 400 {object} Response "Bad request"
 // @Router /update_sub [patch]
 func (r *routing) UpdateSub(ctx echo.Context) (err error) {
-	logger := ctx.Get("logger").(*logrus.Logger)
+	logger := requestLogger(ctx)
 	var data dto.UpdateSubFromWeb
 	if err := ctx.Bind(&data); err != nil {
 		logger.Info("Not OK")
 		return ctx.JSON(http.StatusBadRequest, Response{Data: err.Error()})
 	}
-	if err := r.service.UpdateSubById(ctx, data); err != nil {
+	overlaps, err := r.service.UpdateSubById(ctx, data)
+	if err != nil {
 		logger.Info("Not OK")
-		return ctx.JSON(http.StatusBadRequest, Response{Data: err.Error()})
+		return ctx.JSON(errorStatus(err), Response{Data: err.Error()})
+	}
+	if len(overlaps) > 0 {
+		logger.Warn("update:overlapping subs ", overlaps)
+		return ctx.JSON(http.StatusOK, Response{Data: "OK", Warnings: overlaps})
 	}
 	logger.Info("OK")
 	return ctx.JSON(http.StatusOK, Response{Data: "OK"})
@@ -185,7 +230,7 @@ func (r *routing) UpdateSub(ctx echo.Context) (err error) {
 // @Failure 400 {object} Response "Bad request"
 // @Router /delete_sub/{id} [delete]
 func (r *routing) Delete(ctx echo.Context) (err error) {
-	logger := ctx.Get("logger").(*logrus.Logger)
+	logger := requestLogger(ctx)
 	var data dto.GetSubFromWeb
 	if data.Id, err = strconv.Atoi(ctx.Param("id")); err != nil {
 		logger.Info("Not OK")
@@ -193,8 +238,33 @@ func (r *routing) Delete(ctx echo.Context) (err error) {
 	}
 	if err := r.service.DeleteSub(ctx, data); err != nil {
 		logger.Info("Not OK")
-		return ctx.JSON(http.StatusBadRequest, Response{Data: err.Error()})
+		return ctx.JSON(errorStatus(err), Response{Data: err.Error()})
 	}
 	logger.Info("OK")
 	return ctx.JSON(http.StatusOK, Response{Data: "OK"})
 }
+
+// @Summary Get subscription price history
+// @Description Get prices of a subscription with the month each price is effective from
+// @Tags Subscriptions
+// @Accept  json
+// @Produce  json
+// @Param   id path int true "Subscription ID"
+// @Success 200 {object} Response "Success response"
+// @Failure 400 {object} Response "Bad request"
+// @Router /get_sub_prices/{id} [get]
+func (r *routing) GetSubPrices(ctx echo.Context) (err error) {
+	logger := requestLogger(ctx)
+	var data dto.GetSubFromWeb
+	if data.Id, err = strconv.Atoi(ctx.Param("id")); err != nil {
+		logger.Info("Not OK")
+		return ctx.JSON(http.StatusBadRequest, Response{Data: err.Error()})
+	}
+	dataOut, err := r.service.GetSubPrices(ctx, data)
+	if err != nil {
+		logger.Info("Not OK")
+		return ctx.JSON(errorStatus(err), Response{Data: err.Error()})
+	}
+	logger.Info("OK")
+	return ctx.JSON(http.StatusOK, Response{Data: dataOut})
+}
@@ -0,0 +1,38 @@
+package web
+
+import (
+	"net/http"
+	"service/internal/health"
+
+	"github.com/labstack/echo/v4"
+)
+
+// probePaths are served to orchestrators and load balancers.
+var probePaths = []string{"/healthz", "/readyz"}
+
+// @Summary Liveness probe
+// @Description Reports that the process is alive
+// @Tags Health
+// @Produce  json
+// @Success 200 {object} health.Report "Alive"
+// @Router /healthz [get]
+func (r *routing) Healthz(ctx echo.Context) error {
+	return ctx.JSON(http.StatusOK, r.probe.Live())
+}
+
+// @Summary Readiness probe
+// @Description Checks the database connection, applied migrations and background workers. Fails while the server is shutting down.
+// @Tags Health
+// @Produce  json
+// @Success 200 {object} health.Report "Ready"
+// @Failure 503 {object} health.Report "Not ready"
+// @Router /readyz [get]
+func (r *routing) Readyz(ctx echo.Context) error {
+	report := r.probe.Ready(ctx.Request().Context())
+	if report.Status != health.StatusOK {
+		logger := requestLogger(ctx)
+		logger.Warn("readyz:Not OK ", report.Checks)
+		return ctx.JSON(http.StatusServiceUnavailable, report)
+	}
+	return ctx.JSON(http.StatusOK, report)
+}
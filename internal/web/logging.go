@@ -0,0 +1,76 @@
+package web
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"regexp"
+	"service/logger"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// requestIdRegexp limits client supplied request IDs to values that are safe
+// to log and echo back.
+var requestIdRegexp = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+func newRequestId() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// requestId returns the X-Request-ID sent by the client when it is valid and
+// a new random ID otherwise.
+func requestId(header string) string {
+	if requestIdRegexp.MatchString(header) {
+		return header
+	}
+	return newRequestId()
+}
+
+// requestLogger returns the logger of the request, see (*routing).logger.
+func requestLogger(ctx echo.Context) *logrus.Entry {
+	return logger.FromContext(ctx.Request().Context())
+}
+
+func setRequestLogger(ctx echo.Context, entry *logrus.Entry) {
+	req := ctx.Request()
+	ctx.SetRequest(req.WithContext(logger.WithEntry(req.Context(), entry)))
+}
+
+// logger assigns the request ID, stores a logger carrying it in the request
+// context and writes one access log line once the request is served.
+func (r *routing) logger(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		req := ctx.Request()
+		id := requestId(req.Header.Get(echo.HeaderXRequestID))
+		ctx.Response().Header().Set(echo.HeaderXRequestID, id)
+		fields := logrus.Fields{
+			"request_id": id,
+			"method":     req.Method,
+			"route":      ctx.Path(),
+		}
+		if span := trace.SpanContextFromContext(req.Context()); span.IsValid() {
+			fields["trace_id"] = span.TraceID().String()
+		}
+		setRequestLogger(ctx, r.log.WithFields(fields))
+
+		start := time.Now()
+		err := next(ctx)
+		status := responseStatus(ctx, err)
+		entry := requestLogger(ctx).WithFields(logrus.Fields{
+			"status":     status,
+			"latency_ms": float64(time.Since(start).Microseconds()) / 1000,
+		})
+		if status >= http.StatusInternalServerError {
+			entry.Error("request")
+		} else {
+			entry.Info("request")
+		}
+		return err
+	}
+}
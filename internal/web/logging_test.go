@@ -0,0 +1,43 @@
+package web
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+var generatedIdRegexp = regexp.MustCompile(`^[0-9a-f]{32}$`)
+
+func TestRequestId(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		keep   bool
+	}{
+		{name: "uuid", header: "60601fee-2bf1-4721-ae6f-7636e79a0cba", keep: true},
+		{name: "punctuation", header: "gw:1.2_3", keep: true},
+		{name: "max length", header: strings.Repeat("a", 128), keep: true},
+		{name: "empty", header: ""},
+		{name: "too long", header: strings.Repeat("a", 129)},
+		{name: "line break", header: "abc\ninjected=1"},
+		{name: "carriage return", header: "abc\r"},
+		{name: "space", header: "abc def"},
+		{name: "quote", header: `abc"`},
+		{name: "non ascii", header: "идентификатор"},
+		{name: "trailing newline", header: "abc\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := requestId(tt.header)
+			if tt.keep {
+				if got != tt.header {
+					t.Fatalf("requestId(%q) = %q, want the header", tt.header, got)
+				}
+				return
+			}
+			if !generatedIdRegexp.MatchString(got) {
+				t.Fatalf("requestId(%q) = %q, want a generated ID", tt.header, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package web
+
+import (
+	"net/http"
+	"service/internal/dto"
+
+	"github.com/labstack/echo/v4"
+)
+
+// @Summary Get log level
+// @Description Get the current level of the application logger
+// @Tags Logging
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} Response "Success response"
+// @Failure 400 {object} Response "Bad request"
+// @Router /get_log_level [get]
+func (r *routing) GetLogLevel(ctx echo.Context) error {
+	logger := requestLogger(ctx)
+	dataOut, err := r.service.GetLogLevel(ctx)
+	if err != nil {
+		logger.Info("Not OK")
+		return ctx.JSON(errorStatus(err), Response{Data: err.Error()})
+	}
+	logger.Info("OK")
+	return ctx.JSON(http.StatusOK, Response{Data: dataOut})
+}
+
+// @Summary Update log level
+// @Description Change the level of the application logger at runtime (trace, debug, info, warn, error, fatal, panic)
+// @Tags Logging
+// @Accept  json
+// @Produce  json
+// @Param   request body dto.LogLevelFromWeb true "Log level"
+// @Success 200 {object} Response "Success response"
+// @Failure 400 {object} Response "Bad request"
+// @Router /update_log_level [patch]
+func (r *routing) UpdateLogLevel(ctx echo.Context) error {
+	logger := requestLogger(ctx)
+	var data dto.LogLevelFromWeb
+	if err := ctx.Bind(&data); err != nil {
+		logger.Info("Not OK")
+		return ctx.JSON(http.StatusBadRequest, Response{Data: err.Error()})
+	}
+	if err := r.service.UpdateLogLevel(ctx, data); err != nil {
+		logger.Info("update_log_level:Not OK ", data.Level)
+		return ctx.JSON(errorStatus(err), Response{Data: err.Error()})
+	}
+	logger.Warn("update_log_level:OK ", data.Level)
+	return ctx.JSON(http.StatusOK, Response{Data: "OK"})
+}
@@ -0,0 +1,23 @@
+package web
+
+import (
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+func (r *routing) instrument(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		if r.metrics == nil {
+			return next(ctx)
+		}
+		start := time.Now()
+		err := next(ctx)
+		route := ctx.Path()
+		if route == "" {
+			route = "unmatched"
+		}
+		r.metrics.ObserveRequest(ctx.Request().Method, route, responseStatus(ctx, err), time.Since(start))
+		return err
+	}
+}
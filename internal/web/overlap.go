@@ -0,0 +1,33 @@
+package web
+
+import (
+	"net/http"
+	"service/internal/dto"
+
+	"github.com/labstack/echo/v4"
+)
+
+// @Summary Get overlapping subscriptions
+// @Description List pairs of trial, active or paused subscriptions of the same user and service with intersecting periods
+// @Tags Subscriptions
+// @Accept  json
+// @Produce  json
+// @Param   uuid query string false "User UUID"
+// @Param   serv query string false "Service name"
+// @Success 200 {object} Response "Success response"
+// @Failure 400 {object} Response "Bad request"
+// @Router /get_sub_overlaps [get]
+func (r *routing) GetSubOverlaps(ctx echo.Context) error {
+	logger := requestLogger(ctx)
+	data := dto.GetSubOverlapsFromWeb{
+		UserId:      ctx.QueryParam("uuid"),
+		ServiceName: ctx.QueryParam("serv"),
+	}
+	dataOut, err := r.service.GetSubOverlaps(ctx, data)
+	if err != nil {
+		logger.Info("Not OK")
+		return ctx.JSON(errorStatus(err), Response{Data: err.Error()})
+	}
+	logger.Info("OK")
+	return ctx.JSON(http.StatusOK, Response{Data: dataOut})
+}
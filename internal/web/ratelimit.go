@@ -0,0 +1,93 @@
+package web
+
+import (
+	"math"
+	"net/http"
+	"service/internal/auth"
+	"service/internal/ratelimit"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// rateLimitClient identifies the caller for rate limiting: by API key, then by
+// authenticated subject, then by client IP. Credentials are only trusted once
+// authenticate has accepted them.
+func rateLimitClient(ctx echo.Context) string {
+	req := ctx.Request()
+	p, ok := auth.FromContext(req.Context())
+	if !ok {
+		return "ip:" + ctx.RealIP()
+	}
+	if key := req.Header.Get(auth.HeaderAPIKey); key != "" {
+		return "apikey:" + auth.HashAPIKey(key)[:16]
+	}
+	return "user:" + p.Subject
+}
+
+func ceilSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
+
+func (r *routing) rateLimit(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		if r.limiter == nil || r.infraRoute(ctx.Path()) {
+			return next(ctx)
+		}
+		res, limit, err := r.limiter.Allow(ctx.Request().Context(), rateLimitClient(ctx), ctx.Path())
+		if err != nil {
+			logger := requestLogger(ctx)
+			logger.Warn("rate_limit:Not OK ", err)
+			return next(ctx)
+		}
+		return limitResponse(ctx, next, res, limit)
+	}
+}
+
+// rateLimitAuth rejects requests from client IPs that ran out of failed
+// authentication attempts before their credentials are checked; authenticate
+// charges the failures, see failedAuth.
+func (r *routing) rateLimitAuth(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		if r.limiter == nil || r.authn == nil || r.exempted(ctx.Request().URL.Path) {
+			return next(ctx)
+		}
+		res, limit, err := r.limiter.CheckAuth(ctx.Request().Context(), "ip:"+ctx.RealIP())
+		if err != nil {
+			logger := requestLogger(ctx)
+			logger.Warn("rate_limit:Not OK ", err)
+			return next(ctx)
+		}
+		if res.Allowed {
+			return next(ctx)
+		}
+		return limitResponse(ctx, next, res, limit)
+	}
+}
+
+// failedAuth charges a rejected authentication attempt to the client IP.
+func (r *routing) failedAuth(ctx echo.Context) {
+	if r.limiter == nil {
+		return
+	}
+	if err := r.limiter.FailAuth(ctx.Request().Context(), "ip:"+ctx.RealIP()); err != nil {
+		logger := requestLogger(ctx)
+		logger.Warn("rate_limit:Not OK ", err)
+	}
+}
+
+// limitResponse sets the RateLimit headers and rejects the request with 429
+// when res is not allowed.
+func limitResponse(ctx echo.Context, next echo.HandlerFunc, res ratelimit.Result, limit ratelimit.Limit) error {
+	header := ctx.Response().Header()
+	header.Set("RateLimit-Policy", strconv.Itoa(limit.Requests)+";w="+ceilSeconds(limit.Period))
+	header.Set("RateLimit-Limit", strconv.Itoa(limit.Requests))
+	header.Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+	header.Set("RateLimit-Reset", ceilSeconds(res.Reset))
+	if !res.Allowed {
+		header.Set(echo.HeaderRetryAfter, ceilSeconds(res.RetryAfter))
+		return ctx.JSON(http.StatusTooManyRequests, Response{Data: "rate limit exceeded"})
+	}
+	return next(ctx)
+}
@@ -0,0 +1,75 @@
+package web
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"service/internal/auth"
+	"service/internal/config"
+	"service/internal/ratelimit"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+type testLimitConfig struct{}
+
+func (testLimitConfig) GetRateLimitStore() string                            { return "memory" }
+func (testLimitConfig) GetRateLimitRequests() int                            { return 100 }
+func (testLimitConfig) GetRateLimitPeriod() time.Duration                    { return time.Minute }
+func (testLimitConfig) GetRateLimitRoutes() map[string]config.RateLimitRoute { return nil }
+func (testLimitConfig) GetRateLimitAuthRequests() int                        { return 2 }
+func (testLimitConfig) GetRateLimitAuthPeriod() time.Duration                { return time.Minute }
+
+// testAuthenticator accepts the single key "valid".
+type testAuthenticator struct{}
+
+func (testAuthenticator) Authenticate(_ context.Context, r *http.Request) (auth.Principal, error) {
+	switch r.Header.Get(auth.HeaderAPIKey) {
+	case "":
+		return auth.Principal{}, auth.ErrNoCredentials
+	case "valid":
+		return auth.Principal{Subject: "svc"}, nil
+	default:
+		return auth.Principal{}, auth.ErrInvalidToken
+	}
+}
+
+func TestRateLimitAuth(t *testing.T) {
+	limiter, err := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), testLimitConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := &routing{authn: testAuthenticator{}, limiter: limiter}
+	e := echo.New()
+	e.IPExtractor = echo.ExtractIPDirect()
+	e.Use(r.rateLimitAuth, r.authenticate)
+	e.GET("/", func(ctx echo.Context) error { return ctx.NoContent(http.StatusOK) })
+
+	do := func(key string) int {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = "192.0.2.1:4000"
+		req.Header.Set(auth.HeaderAPIKey, key)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	for i := range 10 {
+		if code := do("valid"); code != http.StatusOK {
+			t.Fatalf("valid request %d: status %d, want 200", i+1, code)
+		}
+	}
+	for i := range 2 {
+		if code := do("guess"); code != http.StatusUnauthorized {
+			t.Fatalf("failed attempt %d: status %d, want 401", i+1, code)
+		}
+	}
+	if code := do("guess"); code != http.StatusTooManyRequests {
+		t.Fatalf("attempt after the limit: status %d, want 429", code)
+	}
+	if code := do("valid"); code != http.StatusTooManyRequests {
+		t.Fatalf("valid key from a blocked IP: status %d, want 429", code)
+	}
+}
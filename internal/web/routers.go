@@ -1,17 +1,44 @@
 package web
 
 import (
+	"service/internal/auth"
+	"service/internal/health"
+	"service/internal/metrics"
+	"service/internal/ratelimit"
 	"service/internal/service"
+	"slices"
 
 	"github.com/labstack/echo/v4"
 	"github.com/sirupsen/logrus"
 	echoSwagger "github.com/swaggo/echo-swagger"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/labstack/echo/otelecho"
 )
 
 type (
 	routing struct {
 		service service.Service
+		authn   auth.Authenticator
+		limiter *ratelimit.Limiter
+		probe   *health.Probe
+		metrics *metrics.Metrics
+		exempt  []string
 		log     *logrus.Logger
+
+		tenancy      bool
+		tenantHeader string
+		metricsPath  string
+
+		tracing     bool
+		serviceName string
+	}
+
+	RoutingConfig interface {
+		GetAuthExempt() []string
+		GetTenancyEnabled() bool
+		GetTenancyHeader() string
+		GetMetricsPath() string
+		GetTracingEnabled() bool
+		GetTracingServiceName() string
 	}
 
 	Routing interface {
@@ -19,17 +46,47 @@ type (
 	}
 )
 
-func NewRouting(service service.Service, log *logrus.Logger) Routing {
+// NewRouting builds the HTTP routes. A nil authn leaves every route open, a
+// nil limiter disables rate limiting and nil metrics disable /metrics.
+func NewRouting(service service.Service, authn auth.Authenticator, limiter *ratelimit.Limiter, probe *health.Probe, metrics *metrics.Metrics, cfg RoutingConfig, log *logrus.Logger) Routing {
 	return &routing{
 		service: service,
+		authn:   authn,
+		limiter: limiter,
+		probe:   probe,
+		metrics: metrics,
+		exempt:  cfg.GetAuthExempt(),
 		log:     log,
+
+		tenancy:      cfg.GetTenancyEnabled(),
+		tenantHeader: cfg.GetTenancyHeader(),
+		metricsPath:  cfg.GetMetricsPath(),
+
+		tracing:     cfg.GetTracingEnabled(),
+		serviceName: cfg.GetTracingServiceName(),
 	}
 }
 
 func (r *routing) RegisterRoutes(e *echo.Echo) {
+	if r.tracing {
+		e.Use(otelecho.Middleware(r.serviceName, otelecho.WithSkipper(func(ctx echo.Context) bool {
+			return r.infraRoute(ctx.Path())
+		})))
+	}
 	e.Use(r.logger)
+	e.Use(r.instrument)
+	e.Use(r.rateLimitAuth)
+	e.Use(r.authenticate)
+	e.Use(r.resolveTenant)
+	e.Use(r.rateLimit)
 
 	e.GET("/", r.Hello)
+	e.GET("/healthz", r.Healthz)
+	e.GET("/readyz", r.Readyz)
+	if r.metrics != nil {
+		e.GET(r.metricsPath, echo.WrapHandler(r.metrics.Handler()))
+	}
+
 	e.POST("/add_sub", r.AddSub)
 	e.GET("/get_sub_by_id/:id", r.GetSubById)
 	e.GET("/get_list", r.GetListSub)
@@ -37,13 +94,54 @@ func (r *routing) RegisterRoutes(e *echo.Echo) {
 	e.GET("/get_price_subs", r.GetPriceSubByFilter)
 	e.PATCH("/update_sub", r.UpdateSub)
 	e.DELETE("/delete_sub/:id", r.Delete)
+	e.PATCH("/pause_sub/:id", r.PauseSub)
+	e.PATCH("/resume_sub/:id", r.ResumeSub)
+	e.PATCH("/cancel_sub/:id", r.CancelSub)
+	e.GET("/get_sub_history/:id", r.GetSubStatusHistory)
+	e.GET("/get_sub_prices/:id", r.GetSubPrices)
+	e.GET("/get_upcoming_subs", r.GetUpcomingSubs)
+	e.GET("/get_sub_overlaps", r.GetSubOverlaps)
+
+	e.POST("/add_user", r.AddUser)
+	e.GET("/get_user_by_id/:uuid", r.GetUserById)
+	e.GET("/get_list_users", r.GetListUsers)
+	e.GET("/get_user_summary/:uuid", r.GetUserSummary)
+	e.GET("/get_user_reminders/:uuid", r.GetUserReminders)
+	e.PATCH("/update_user", r.UpdateUser)
+	e.DELETE("/delete_user/:uuid", r.DeleteUser)
+
+	e.POST("/add_budget", r.AddBudget)
+	e.GET("/get_budgets_by_user/:uuid", r.GetBudgetsByUser)
+	e.PATCH("/update_budget", r.UpdateBudget)
+	e.DELETE("/delete_budget/:id", r.DeleteBudget)
+	e.GET("/get_budget_alerts/:uuid", r.GetBudgetAlerts)
+
+	e.GET("/analytics/spend", r.GetSpendAnalytics)
+	e.GET("/analytics/top_services", r.GetTopServices)
+	e.GET("/analytics/churn", r.GetChurn)
+
+	e.POST("/add_webhook", r.AddWebhook)
+	e.GET("/get_webhook_by_id/:id", r.GetWebhookById)
+	e.GET("/get_list_webhooks", r.GetListWebhooks)
+	e.PATCH("/update_webhook", r.UpdateWebhook)
+	e.DELETE("/delete_webhook/:id", r.DeleteWebhook)
+	e.GET("/get_webhook_deliveries/:id", r.GetWebhookDeliveries)
+	e.GET("/get_webhook_attempts/:id", r.GetWebhookAttempts)
+	e.POST("/redeliver_webhook/:id", r.RedeliverWebhook)
+
+	e.POST("/add_api_key", r.AddAPIKey)
+	e.GET("/get_list_api_keys", r.GetListAPIKeys)
+	e.POST("/revoke_api_key/:id", r.RevokeAPIKey)
+	e.POST("/rotate_api_key/:id", r.RotateAPIKey)
+
+	e.GET("/get_log_level", r.GetLogLevel)
+	e.PATCH("/update_log_level", r.UpdateLogLevel)
 
 	e.GET("/swagger/*", echoSwagger.WrapHandler)
 }
 
-func (r *routing) logger(next echo.HandlerFunc) echo.HandlerFunc {
-	return func(ctx echo.Context) error {
-		ctx.Set("logger", r.log)
-		return next(ctx)
-	}
+// infraRoute reports whether route is probed or scraped by infrastructure.
+// Such routes are neither rate limited nor traced.
+func (r *routing) infraRoute(route string) bool {
+	return slices.Contains(probePaths, route) || (r.metrics != nil && route == r.metricsPath)
 }
@@ -2,9 +2,12 @@ package web
 
 import (
 	"context"
+	"fmt"
 	"log"
+	"net"
 	"net/http"
 	"service/internal/config"
+	"service/internal/health"
 	"time"
 
 	"github.com/labstack/echo/v4"
@@ -14,6 +17,8 @@ type (
 	ServerHTTP struct {
 		address     string
 		idleTimeout time.Duration
+		drainDelay  time.Duration
+		probe       *health.Probe
 	}
 
 	Server interface {
@@ -22,10 +27,14 @@ type (
 	}
 )
 
-func NewServer(cfg config.Config) Server {
+// NewServer builds the HTTP server. On shutdown it first flips probe to not
+// ready and waits the drain delay so load balancers stop sending traffic.
+func NewServer(cfg config.Config, probe *health.Probe) Server {
 	return &ServerHTTP{
 		address:     cfg.GetAddress(),
 		idleTimeout: cfg.GetIdleTime(),
+		drainDelay:  cfg.GetHealthDrainDelay(),
+		probe:       probe,
 	}
 }
 
@@ -37,6 +46,11 @@ func (s *ServerHTTP) Start(e *echo.Echo) {
 }
 
 func (s *ServerHTTP) Shutdown(e *echo.Echo) {
+	if s.probe != nil {
+		s.probe.Drain()
+		log.Printf("Server draining for %s...", s.drainDelay)
+		time.Sleep(s.drainDelay)
+	}
 	ctx, cancel := context.WithTimeout(context.Background(), s.idleTimeout)
 	defer cancel()
 	log.Printf("Server shutting down...")
@@ -45,3 +59,25 @@ func (s *ServerHTTP) Shutdown(e *echo.Echo) {
 	}
 	log.Println("Server by ended")
 }
+
+// NewIPExtractor returns how the client IP is taken for logs and rate limits.
+// Without trusted proxies the peer address is used and X-Forwarded-For is
+// ignored, otherwise the header is read past the listed proxy networks.
+func NewIPExtractor(trustedProxies []string) (echo.IPExtractor, error) {
+	if len(trustedProxies) == 0 {
+		return echo.ExtractIPDirect(), nil
+	}
+	options := []echo.TrustOption{
+		echo.TrustLoopback(false),
+		echo.TrustLinkLocal(false),
+		echo.TrustPrivateNet(false),
+	}
+	for _, proxy := range trustedProxies {
+		_, network, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("trusted proxy %q: %w", proxy, err)
+		}
+		options = append(options, echo.TrustIPRange(network))
+	}
+	return echo.ExtractIPFromXFFHeader(options...), nil
+}
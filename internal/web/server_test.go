@@ -0,0 +1,39 @@
+package web
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNewIPExtractor(t *testing.T) {
+	tests := []struct {
+		name    string
+		trusted []string
+		remote  string
+		xff     string
+		want    string
+	}{
+		{name: "direct ignores header", remote: "10.0.0.5:4000", xff: "203.0.113.7", want: "10.0.0.5"},
+		{name: "trusted proxy", trusted: []string{"10.0.0.0/8"}, remote: "10.0.0.5:4000", xff: "203.0.113.7", want: "203.0.113.7"},
+		{name: "untrusted peer", trusted: []string{"10.0.0.0/8"}, remote: "198.51.100.9:4000", xff: "203.0.113.7", want: "198.51.100.9"},
+		{name: "spoofed chain", trusted: []string{"10.0.0.0/8"}, remote: "10.0.0.5:4000", xff: "1.1.1.1, 203.0.113.7", want: "203.0.113.7"},
+		{name: "private not trusted by default", trusted: []string{"10.0.0.0/8"}, remote: "192.168.1.2:4000", xff: "203.0.113.7", want: "192.168.1.2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			extract, err := NewIPExtractor(tt.trusted)
+			if err != nil {
+				t.Fatal(err)
+			}
+			r := httptest.NewRequest("GET", "/", nil)
+			r.RemoteAddr = tt.remote
+			r.Header.Set("X-Forwarded-For", tt.xff)
+			if got := extract(r); got != tt.want {
+				t.Fatalf("extract() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+	if _, err := NewIPExtractor([]string{"10.0.0.1"}); err == nil {
+		t.Fatal("NewIPExtractor() accepted an address without a prefix length")
+	}
+}
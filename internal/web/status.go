@@ -0,0 +1,88 @@
+package web
+
+import (
+	"net/http"
+	"service/internal/dto"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+func (r *routing) changeSubStatus(ctx echo.Context, change func(echo.Context, dto.GetSubFromWeb) error) (err error) {
+	logger := requestLogger(ctx)
+	var data dto.GetSubFromWeb
+	if data.Id, err = strconv.Atoi(ctx.Param("id")); err != nil {
+		logger.Info("Not OK")
+		return ctx.JSON(http.StatusBadRequest, Response{Data: err.Error()})
+	}
+	if err := change(ctx, data); err != nil {
+		logger.Info("Not OK")
+		return ctx.JSON(errorStatus(err), Response{Data: err.Error()})
+	}
+	logger.Info("OK")
+	return ctx.JSON(http.StatusOK, Response{Data: "OK"})
+}
+
+// @Summary Pause subscription
+// @Description Move an active subscription to paused
+// @Tags Subscriptions
+// @Accept  json
+// @Produce  json
+// @Param   id path int true "Subscription ID"
+// @Success 200 {object} Response "Success response"
+// @Failure 400 {object} Response "Bad request"
+// @Router /pause_sub/{id} [patch]
+func (r *routing) PauseSub(ctx echo.Context) error {
+	return r.changeSubStatus(ctx, r.service.PauseSub)
+}
+
+// @Summary Resume subscription
+// @Description Move a paused subscription back to active
+// @Tags Subscriptions
+// @Accept  json
+// @Produce  json
+// @Param   id path int true "Subscription ID"
+// @Success 200 {object} Response "Success response"
+// @Failure 400 {object} Response "Bad request"
+// @Router /resume_sub/{id} [patch]
+func (r *routing) ResumeSub(ctx echo.Context) error {
+	return r.changeSubStatus(ctx, r.service.ResumeSub)
+}
+
+// @Summary Cancel subscription
+// @Description Cancel a trial, active or paused subscription
+// @Tags Subscriptions
+// @Accept  json
+// @Produce  json
+// @Param   id path int true "Subscription ID"
+// @Success 200 {object} Response "Success response"
+// @Failure 400 {object} Response "Bad request"
+// @Router /cancel_sub/{id} [patch]
+func (r *routing) CancelSub(ctx echo.Context) error {
+	return r.changeSubStatus(ctx, r.service.CancelSub)
+}
+
+// @Summary Get subscription status history
+// @Description Get status transitions of a subscription with timestamps
+// @Tags Subscriptions
+// @Accept  json
+// @Produce  json
+// @Param   id path int true "Subscription ID"
+// @Success 200 {object} Response "Success response"
+// @Failure 400 {object} Response "Bad request"
+// @Router /get_sub_history/{id} [get]
+func (r *routing) GetSubStatusHistory(ctx echo.Context) (err error) {
+	logger := requestLogger(ctx)
+	var data dto.GetSubFromWeb
+	if data.Id, err = strconv.Atoi(ctx.Param("id")); err != nil {
+		logger.Info("Not OK")
+		return ctx.JSON(http.StatusBadRequest, Response{Data: err.Error()})
+	}
+	dataOut, err := r.service.GetSubStatusHistory(ctx, data)
+	if err != nil {
+		logger.Info("Not OK")
+		return ctx.JSON(errorStatus(err), Response{Data: err.Error()})
+	}
+	logger.Info("OK")
+	return ctx.JSON(http.StatusOK, Response{Data: dataOut})
+}
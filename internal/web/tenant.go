@@ -0,0 +1,54 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"service/internal/auth"
+	"service/internal/tenant"
+
+	"github.com/labstack/echo/v4"
+)
+
+// requestTenant resolves the tenant of a request. Authenticated callers
+// belong to the tenant named by their credentials and the header may only
+// repeat it; without authentication the header selects the tenant.
+func (r *routing) requestTenant(ctx echo.Context) (string, int, error) {
+	if !r.tenancy {
+		return tenant.Default, http.StatusOK, nil
+	}
+	header := ctx.Request().Header.Get(r.tenantHeader)
+	id := header
+	if p, ok := auth.FromContext(ctx.Request().Context()); ok {
+		if p.Tenant == "" {
+			return "", http.StatusForbidden, fmt.Errorf("credentials are not bound to a tenant")
+		}
+		if header != "" && header != p.Tenant {
+			return "", http.StatusForbidden, fmt.Errorf("no access to tenant %s", header)
+		}
+		id = p.Tenant
+	}
+	if id == "" {
+		if r.exempted(ctx.Request().URL.Path) {
+			return tenant.Default, http.StatusOK, nil
+		}
+		return "", http.StatusBadRequest, fmt.Errorf("%s header is required", r.tenantHeader)
+	}
+	if err := tenant.Validate(id); err != nil {
+		return "", http.StatusBadRequest, err
+	}
+	return id, http.StatusOK, nil
+}
+
+func (r *routing) resolveTenant(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		id, status, err := r.requestTenant(ctx)
+		if err != nil {
+			logger := requestLogger(ctx)
+			logger.Info("tenant:Not OK ", err)
+			return ctx.JSON(status, Response{Data: err.Error()})
+		}
+		req := ctx.Request()
+		ctx.SetRequest(req.WithContext(tenant.WithTenant(req.Context(), id)))
+		return next(ctx)
+	}
+}
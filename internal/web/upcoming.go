@@ -0,0 +1,51 @@
+package web
+
+import (
+	"net/http"
+	"service/internal/dto"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+// @Summary Upcoming renewals and expirations
+// @Description Get subscriptions whose end date falls within the next N days, ordered by end date
+// @Tags Subscriptions
+// @Accept  json
+// @Produce  json
+// @Param   days query int false "Window in days (default 7)"
+// @Param   uuid query string false "User UUID"
+// @Param   serv query string false "Service name"
+// @Param   status query string false "Status filter, comma separated (default trial,active)"
+// @Param   limit query int false "Page size (default 50)"
+// @Param   offset query int false "Page offset"
+// @Success 200 {object} Response "Success response"
+// @Failure 400 {object} Response "Bad request"
+// @Router /get_upcoming_subs [get]
+func (r *routing) GetUpcomingSubs(ctx echo.Context) (err error) {
+	logger := requestLogger(ctx)
+	var data dto.GetUpcomingSubsFromWeb
+	data.UserId = ctx.QueryParam("uuid")
+	data.ServiceName = ctx.QueryParam("serv")
+	data.Status = statusParam(ctx)
+	for param, value := range map[string]*int{
+		"days":   &data.Days,
+		"limit":  &data.Limit,
+		"offset": &data.Offset,
+	} {
+		if ctx.QueryParam(param) == "" {
+			continue
+		}
+		if *value, err = strconv.Atoi(ctx.QueryParam(param)); err != nil {
+			logger.Info("Not OK")
+			return ctx.JSON(http.StatusBadRequest, Response{Data: err.Error()})
+		}
+	}
+	dataOut, err := r.service.GetUpcomingSubs(ctx, data)
+	if err != nil {
+		logger.Info("Not OK")
+		return ctx.JSON(errorStatus(err), Response{Data: err.Error()})
+	}
+	logger.Info("OK")
+	return ctx.JSON(http.StatusOK, Response{Data: dataOut})
+}
@@ -0,0 +1,159 @@
+package web
+
+import (
+	"net/http"
+	"service/internal/dto"
+
+	"github.com/labstack/echo/v4"
+)
+
+// @Summary Add user
+// @Description Register a new user
+// @Tags Users
+// @Accept  json
+// @Produce  json
+// @Param   request body dto.AddUserFromWeb true "User data"
+// @Success 200 {object} Response "Success response"
+// @Failure 400 {object} Response "Bad request"
+// @Router /add_user [post]
+func (r *routing) AddUser(ctx echo.Context) error {
+	logger := requestLogger(ctx)
+	var data dto.AddUserFromWeb
+	if err := ctx.Bind(&data); err != nil {
+		logger.Info("Not OK")
+		return ctx.JSON(http.StatusBadRequest, Response{Data: err.Error()})
+	}
+	dataOut, err := r.service.AddNewUser(ctx, data)
+	if err != nil {
+		logger.Info("add_user:Not OK ", data)
+		return ctx.JSON(errorStatus(err), Response{Data: err.Error()})
+	}
+	logger.Info("add_user:OK ", data)
+	return ctx.JSON(http.StatusOK, Response{Data: dataOut})
+}
+
+// @Summary Get user by ID
+// @Description Get user details by UUID
+// @Tags Users
+// @Accept  json
+// @Produce  json
+// @Param   uuid path string true "User UUID"
+// @Success 200 {object} Response "Success response"
+// @Failure 400 {object} Response "Bad request"
+// @Router /get_user_by_id/{uuid} [get]
+func (r *routing) GetUserById(ctx echo.Context) error {
+	logger := requestLogger(ctx)
+	data := dto.GetUserFromWeb{Id: ctx.Param("uuid")}
+	dataOut, err := r.service.GetUserById(ctx, data)
+	if err != nil {
+		logger.Info("Not OK")
+		return ctx.JSON(errorStatus(err), Response{Data: err.Error()})
+	}
+	logger.Info("OK")
+	return ctx.JSON(http.StatusOK, Response{Data: dataOut})
+}
+
+// @Summary Get all users
+// @Description Get list of all registered users
+// @Tags Users
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} Response "Success response"
+// @Failure 400 {object} Response "Bad request"
+// @Router /get_list_users [get]
+func (r *routing) GetListUsers(ctx echo.Context) error {
+	logger := requestLogger(ctx)
+	dataOut, err := r.service.GetListUsers(ctx)
+	if err != nil {
+		logger.Info("Not OK")
+		return ctx.JSON(errorStatus(err), Response{Data: err.Error()})
+	}
+	logger.Info("OK")
+	return ctx.JSON(http.StatusOK, Response{Data: dataOut})
+}
+
+// @Summary Update user
+// @Description Update existing user
+// @Tags Users
+// @Accept  json
+// @Produce  json
+// @Param   request body dto.UpdateUserFromWeb true "User data to update"
+// @Success 200 {object} Response "Success response"
+// @Failure 400 {object} Response "Bad request"
+// @Router /update_user [patch]
+func (r *routing) UpdateUser(ctx echo.Context) error {
+	logger := requestLogger(ctx)
+	var data dto.UpdateUserFromWeb
+	if err := ctx.Bind(&data); err != nil {
+		logger.Info("Not OK")
+		return ctx.JSON(http.StatusBadRequest, Response{Data: err.Error()})
+	}
+	if err := r.service.UpdateUserById(ctx, data); err != nil {
+		logger.Info("Not OK")
+		return ctx.JSON(errorStatus(err), Response{Data: err.Error()})
+	}
+	logger.Info("OK")
+	return ctx.JSON(http.StatusOK, Response{Data: "OK"})
+}
+
+// @Summary Delete user
+// @Description Delete user without subscriptions by UUID
+// @Tags Users
+// @Accept  json
+// @Produce  json
+// @Param   uuid path string true "User UUID"
+// @Success 200 {object} Response "Success response"
+// @Failure 400 {object} Response "Bad request"
+// @Router /delete_user/{uuid} [delete]
+func (r *routing) DeleteUser(ctx echo.Context) error {
+	logger := requestLogger(ctx)
+	data := dto.GetUserFromWeb{Id: ctx.Param("uuid")}
+	if err := r.service.DeleteUser(ctx, data); err != nil {
+		logger.Info("Not OK")
+		return ctx.JSON(errorStatus(err), Response{Data: err.Error()})
+	}
+	logger.Info("OK")
+	return ctx.JSON(http.StatusOK, Response{Data: "OK"})
+}
+
+// @Summary Get user summary
+// @Description Get active subscriptions, monthly spend and next renewals of a user
+// @Tags Users
+// @Accept  json
+// @Produce  json
+// @Param   uuid path string true "User UUID"
+// @Success 200 {object} Response "Success response"
+// @Failure 400 {object} Response "Bad request"
+// @Router /get_user_summary/{uuid} [get]
+func (r *routing) GetUserSummary(ctx echo.Context) error {
+	logger := requestLogger(ctx)
+	data := dto.GetUserFromWeb{Id: ctx.Param("uuid")}
+	dataOut, err := r.service.GetUserSummary(ctx, data)
+	if err != nil {
+		logger.Info("Not OK")
+		return ctx.JSON(errorStatus(err), Response{Data: err.Error()})
+	}
+	logger.Info("OK")
+	return ctx.JSON(http.StatusOK, Response{Data: dataOut})
+}
+
+// @Summary Get user reminders
+// @Description Get renewal reminders sent to a user with their delivery status
+// @Tags Users
+// @Accept  json
+// @Produce  json
+// @Param   uuid path string true "User UUID"
+// @Success 200 {object} Response "Success response"
+// @Failure 400 {object} Response "Bad request"
+// @Router /get_user_reminders/{uuid} [get]
+func (r *routing) GetUserReminders(ctx echo.Context) error {
+	logger := requestLogger(ctx)
+	data := dto.GetUserFromWeb{Id: ctx.Param("uuid")}
+	dataOut, err := r.service.GetUserReminders(ctx, data)
+	if err != nil {
+		logger.Info("Not OK")
+		return ctx.JSON(errorStatus(err), Response{Data: err.Error()})
+	}
+	logger.Info("OK")
+	return ctx.JSON(http.StatusOK, Response{Data: dataOut})
+}
@@ -0,0 +1,200 @@
+package web
+
+import (
+	"net/http"
+	"service/internal/dto"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+// @Summary Add webhook
+// @Description Register an outgoing webhook; the signing secret is returned only once
+// @Tags Webhooks
+// @Accept  json
+// @Produce  json
+// @Param   request body dto.AddWebhookFromWeb true "Webhook data"
+// @Success 200 {object} Response "Success response"
+// @Failure 400 {object} Response "Bad request"
+// @Router /add_webhook [post]
+func (r *routing) AddWebhook(ctx echo.Context) error {
+	logger := requestLogger(ctx)
+	var data dto.AddWebhookFromWeb
+	if err := ctx.Bind(&data); err != nil {
+		logger.Info("Not OK")
+		return ctx.JSON(http.StatusBadRequest, Response{Data: err.Error()})
+	}
+	dataOut, err := r.service.AddWebhook(ctx, data)
+	if err != nil {
+		logger.Info("add_webhook:Not OK ", data.Url)
+		return ctx.JSON(errorStatus(err), Response{Data: err.Error()})
+	}
+	logger.Info("add_webhook:OK ", data.Url)
+	return ctx.JSON(http.StatusOK, Response{Data: dataOut})
+}
+
+// @Summary Get webhook by ID
+// @Description Get webhook details by ID
+// @Tags Webhooks
+// @Accept  json
+// @Produce  json
+// @Param   id path int true "Webhook ID"
+// @Success 200 {object} Response "Success response"
+// @Failure 400 {object} Response "Bad request"
+// @Router /get_webhook_by_id/{id} [get]
+func (r *routing) GetWebhookById(ctx echo.Context) (err error) {
+	logger := requestLogger(ctx)
+	var data dto.GetWebhookFromWeb
+	if data.Id, err = strconv.Atoi(ctx.Param("id")); err != nil {
+		logger.Info("Not OK")
+		return ctx.JSON(http.StatusBadRequest, Response{Data: err.Error()})
+	}
+	dataOut, err := r.service.GetWebhookById(ctx, data)
+	if err != nil {
+		logger.Info("Not OK")
+		return ctx.JSON(errorStatus(err), Response{Data: err.Error()})
+	}
+	logger.Info("OK")
+	return ctx.JSON(http.StatusOK, Response{Data: dataOut})
+}
+
+// @Summary Get all webhooks
+// @Description Get list of all registered webhooks
+// @Tags Webhooks
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} Response "Success response"
+// @Failure 400 {object} Response "Bad request"
+// @Router /get_list_webhooks [get]
+func (r *routing) GetListWebhooks(ctx echo.Context) error {
+	logger := requestLogger(ctx)
+	dataOut, err := r.service.GetListWebhooks(ctx)
+	if err != nil {
+		logger.Info("Not OK")
+		return ctx.JSON(errorStatus(err), Response{Data: err.Error()})
+	}
+	logger.Info("OK")
+	return ctx.JSON(http.StatusOK, Response{Data: dataOut})
+}
+
+// @Summary Update webhook
+// @Description Update url, secret, event types or active flag of a webhook
+// @Tags Webhooks
+// @Accept  json
+// @Produce  json
+// @Param   request body dto.UpdateWebhookFromWeb true "Webhook data to update"
+// @Success 200 {object} Response "Success response"
+// @Failure 400 {object} Response "Bad request"
+// @Router /update_webhook [patch]
+func (r *routing) UpdateWebhook(ctx echo.Context) error {
+	logger := requestLogger(ctx)
+	var data dto.UpdateWebhookFromWeb
+	if err := ctx.Bind(&data); err != nil {
+		logger.Info("Not OK")
+		return ctx.JSON(http.StatusBadRequest, Response{Data: err.Error()})
+	}
+	if err := r.service.UpdateWebhookById(ctx, data); err != nil {
+		logger.Info("Not OK")
+		return ctx.JSON(errorStatus(err), Response{Data: err.Error()})
+	}
+	logger.Info("OK")
+	return ctx.JSON(http.StatusOK, Response{Data: "OK"})
+}
+
+// @Summary Delete webhook
+// @Description Delete webhook together with its delivery log
+// @Tags Webhooks
+// @Accept  json
+// @Produce  json
+// @Param   id path int true "Webhook ID"
+// @Success 200 {object} Response "Success response"
+// @Failure 400 {object} Response "Bad request"
+// @Router /delete_webhook/{id} [delete]
+func (r *routing) DeleteWebhook(ctx echo.Context) (err error) {
+	logger := requestLogger(ctx)
+	var data dto.GetWebhookFromWeb
+	if data.Id, err = strconv.Atoi(ctx.Param("id")); err != nil {
+		logger.Info("Not OK")
+		return ctx.JSON(http.StatusBadRequest, Response{Data: err.Error()})
+	}
+	if err := r.service.DeleteWebhook(ctx, data); err != nil {
+		logger.Info("Not OK")
+		return ctx.JSON(errorStatus(err), Response{Data: err.Error()})
+	}
+	logger.Info("OK")
+	return ctx.JSON(http.StatusOK, Response{Data: "OK"})
+}
+
+// @Summary Get webhook deliveries
+// @Description Get delivery log of a webhook with statuses and last response codes
+// @Tags Webhooks
+// @Accept  json
+// @Produce  json
+// @Param   id path int true "Webhook ID"
+// @Success 200 {object} Response "Success response"
+// @Failure 400 {object} Response "Bad request"
+// @Router /get_webhook_deliveries/{id} [get]
+func (r *routing) GetWebhookDeliveries(ctx echo.Context) (err error) {
+	logger := requestLogger(ctx)
+	var data dto.GetWebhookDeliveriesFromWeb
+	if data.WebhookId, err = strconv.Atoi(ctx.Param("id")); err != nil {
+		logger.Info("Not OK")
+		return ctx.JSON(http.StatusBadRequest, Response{Data: err.Error()})
+	}
+	dataOut, err := r.service.GetWebhookDeliveries(ctx, data)
+	if err != nil {
+		logger.Info("Not OK")
+		return ctx.JSON(errorStatus(err), Response{Data: err.Error()})
+	}
+	logger.Info("OK")
+	return ctx.JSON(http.StatusOK, Response{Data: dataOut})
+}
+
+// @Summary Get webhook delivery attempts
+// @Description Get every attempt of a delivery with response code, error and duration
+// @Tags Webhooks
+// @Accept  json
+// @Produce  json
+// @Param   id path int true "Delivery ID"
+// @Success 200 {object} Response "Success response"
+// @Failure 400 {object} Response "Bad request"
+// @Router /get_webhook_attempts/{id} [get]
+func (r *routing) GetWebhookAttempts(ctx echo.Context) (err error) {
+	logger := requestLogger(ctx)
+	var data dto.GetWebhookDeliveryFromWeb
+	if data.Id, err = strconv.ParseInt(ctx.Param("id"), 10, 64); err != nil {
+		logger.Info("Not OK")
+		return ctx.JSON(http.StatusBadRequest, Response{Data: err.Error()})
+	}
+	dataOut, err := r.service.GetWebhookAttempts(ctx, data)
+	if err != nil {
+		logger.Info("Not OK")
+		return ctx.JSON(errorStatus(err), Response{Data: err.Error()})
+	}
+	logger.Info("OK")
+	return ctx.JSON(http.StatusOK, Response{Data: dataOut})
+}
+
+// @Summary Redeliver webhook
+// @Description Schedule a delivery to be sent again right away with a fresh retry budget
+// @Tags Webhooks
+// @Accept  json
+// @Produce  json
+// @Param   id path int true "Delivery ID"
+// @Success 200 {object} Response "Success response"
+// @Failure 400 {object} Response "Bad request"
+// @Router /redeliver_webhook/{id} [post]
+func (r *routing) RedeliverWebhook(ctx echo.Context) (err error) {
+	logger := requestLogger(ctx)
+	var data dto.GetWebhookDeliveryFromWeb
+	if data.Id, err = strconv.ParseInt(ctx.Param("id"), 10, 64); err != nil {
+		logger.Info("Not OK")
+		return ctx.JSON(http.StatusBadRequest, Response{Data: err.Error()})
+	}
+	if err := r.service.RedeliverWebhook(ctx, data); err != nil {
+		logger.Info("Not OK")
+		return ctx.JSON(errorStatus(err), Response{Data: err.Error()})
+	}
+	logger.Info("OK")
+	return ctx.JSON(http.StatusOK, Response{Data: "OK"})
+}
@@ -0,0 +1,12 @@
+package worker
+
+import "time"
+
+// backoff doubles base after every failed attempt, capped at limit.
+func backoff(base, limit time.Duration, attempts int) time.Duration {
+	delay := base
+	for i := 1; i < attempts && delay < limit; i++ {
+		delay *= 2
+	}
+	return min(delay, limit)
+}
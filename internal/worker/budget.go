@@ -0,0 +1,88 @@
+package worker
+
+import (
+	"context"
+	"service/internal/datasource/repository"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+type (
+	BudgetsConfig interface {
+		GetBudgetsInterval() time.Duration
+		GetBudgetsDirtyInterval() time.Duration
+		GetBudgetsBatchSize() int
+	}
+
+	// Budgets evaluates the budgets of users whose subs changed shortly
+	// after the change, and re-evaluates all budgets periodically so that
+	// overspend caused by a month rollover or background renewals is
+	// reported too.
+	Budgets struct {
+		storage       repository.Storage
+		log           *logrus.Logger
+		interval      time.Duration
+		dirtyInterval time.Duration
+		batchSize     int
+	}
+)
+
+func NewBudgets(storage repository.Storage, cfg BudgetsConfig, log *logrus.Logger) *Budgets {
+	return &Budgets{
+		storage:       storage,
+		log:           log,
+		interval:      cfg.GetBudgetsInterval(),
+		dirtyInterval: cfg.GetBudgetsDirtyInterval(),
+		batchSize:     cfg.GetBudgetsBatchSize(),
+	}
+}
+
+func (w *Budgets) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	dirty := time.NewTicker(w.dirtyInterval)
+	defer dirty.Stop()
+	w.evaluate(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.evaluate(ctx)
+		case <-dirty.C:
+			w.evaluateDirty(ctx)
+		}
+	}
+}
+
+func (w *Budgets) evaluate(ctx context.Context) {
+	alerts, err := w.storage.EvaluateBudgets(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			w.log.Errorf("budgets: %v", err)
+		}
+		return
+	}
+	if alerts > 0 {
+		w.log.Infof("budgets: %d budgets exceeded", alerts)
+	}
+}
+
+func (w *Budgets) evaluateDirty(ctx context.Context) {
+	for {
+		users, err := w.storage.EvaluateDirtyBudgets(ctx, w.batchSize)
+		if err != nil {
+			if ctx.Err() == nil {
+				w.log.Errorf("budgets: %v", err)
+			}
+			return
+		}
+		if users > 0 {
+			w.log.Debugf("budgets: budgets of %d users evaluated", users)
+		}
+		if users < w.batchSize || ctx.Err() != nil {
+			return
+		}
+	}
+}
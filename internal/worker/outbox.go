@@ -0,0 +1,138 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"service/internal/datasource/repository"
+	"service/internal/dto"
+	"service/internal/events"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+type (
+	RelayConfig interface {
+		GetEventsSource() string
+		GetEventsInterval() time.Duration
+		GetEventsBatchSize() int
+		GetEventsMaxAttempts() int
+		GetEventsBackoffBase() time.Duration
+		GetEventsBackoffMax() time.Duration
+	}
+
+	// Relay publishes outbox events and fans them out to the subscribed
+	// webhooks.
+	Relay struct {
+		storage     repository.Storage
+		publisher   events.Publisher
+		log         *logrus.Logger
+		source      string
+		interval    time.Duration
+		batchSize   int
+		maxAttempts int
+		backoffBase time.Duration
+		backoffMax  time.Duration
+	}
+)
+
+func NewRelay(storage repository.Storage, publisher events.Publisher, cfg RelayConfig, log *logrus.Logger) *Relay {
+	return &Relay{
+		storage:     storage,
+		publisher:   publisher,
+		log:         log,
+		source:      cfg.GetEventsSource(),
+		interval:    cfg.GetEventsInterval(),
+		batchSize:   cfg.GetEventsBatchSize(),
+		maxAttempts: cfg.GetEventsMaxAttempts(),
+		backoffBase: cfg.GetEventsBackoffBase(),
+		backoffMax:  cfg.GetEventsBackoffMax(),
+	}
+}
+
+func (w *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		w.relay(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *Relay) relay(ctx context.Context) {
+	for {
+		done, err := w.storage.RelayOutbox(ctx, w.batchSize, func(data dto.OutboxEventFromDb) dto.OutboxAttemptToDb {
+			return w.publish(ctx, data)
+		})
+		if done > 0 {
+			w.log.Debugf("outbox: %d events relayed", done)
+		}
+		if err != nil {
+			if ctx.Err() == nil {
+				w.log.Errorf("outbox: %v", err)
+			}
+			return
+		}
+		if done < w.batchSize || ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+func (w *Relay) publish(ctx context.Context, data dto.OutboxEventFromDb) dto.OutboxAttemptToDb {
+	err := w.send(ctx, data)
+	if err == nil {
+		return dto.OutboxAttemptToDb{Published: true}
+	}
+	attempt := dto.OutboxAttemptToDb{Error: err.Error()}
+	attempts := data.Attempts + 1
+	if attempts >= w.maxAttempts {
+		attempt.GiveUp = true
+		w.log.Errorf("outbox: event %s dead-lettered after %d attempts: %v", data.EventId, attempts, err)
+		return attempt
+	}
+	w.log.Warnf("outbox: failed to publish event %s: %v", data.EventId, err)
+	attempt.NextAttempt = time.Now().Add(backoff(w.backoffBase, w.backoffMax, attempts))
+	return attempt
+}
+
+func (w *Relay) send(ctx context.Context, data dto.OutboxEventFromDb) error {
+	event := events.Event{
+		SpecVersion:     events.SpecVersion,
+		Id:              data.EventId,
+		Source:          w.source,
+		Type:            data.EventType,
+		Subject:         data.Subject,
+		Tenant:          data.TenantId,
+		Time:            data.CreatedAt,
+		DataContentType: events.DataContentType,
+		Data:            data.Data,
+	}
+	if data.FannedOutAt == nil {
+		if err := w.fanout(ctx, event); err != nil {
+			return err
+		}
+	}
+	return w.publisher.Publish(ctx, event)
+}
+
+// fanout enqueues the webhook deliveries of event. The repository does it
+// at most once per event id, whatever happens to the publish that follows.
+func (w *Relay) fanout(ctx context.Context, event events.Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+	_, err = w.storage.EnqueueWebhookDeliveries(ctx, dto.EnqueueWebhookToDb{
+		EventId:   event.Id,
+		TenantId:  event.Tenant,
+		EventType: event.Type,
+		Payload:   payload,
+	})
+	return err
+}
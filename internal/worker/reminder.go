@@ -0,0 +1,93 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"service/internal/datasource/repository"
+	"service/internal/dto"
+	"service/internal/notify"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+type (
+	RemindersConfig interface {
+		GetRemindersInterval() time.Duration
+		GetRemindersDaysBefore() int
+		GetRemindersBatchSize() int
+		GetRemindersMaxAttempts() int
+	}
+
+	Reminders struct {
+		storage     repository.Storage
+		notifier    notify.Notifier
+		log         *logrus.Logger
+		interval    time.Duration
+		daysBefore  int
+		batchSize   int
+		maxAttempts int
+	}
+)
+
+func NewReminders(storage repository.Storage, notifier notify.Notifier, cfg RemindersConfig, log *logrus.Logger) *Reminders {
+	return &Reminders{
+		storage:     storage,
+		notifier:    notifier,
+		log:         log,
+		interval:    cfg.GetRemindersInterval(),
+		daysBefore:  cfg.GetRemindersDaysBefore(),
+		batchSize:   cfg.GetRemindersBatchSize(),
+		maxAttempts: cfg.GetRemindersMaxAttempts(),
+	}
+}
+
+func (w *Reminders) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		w.remind(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *Reminders) remind(ctx context.Context) {
+	for {
+		done, err := w.storage.SendReminders(ctx, dto.DueRemindersToDb{
+			Before:      time.Now().AddDate(0, 0, w.daysBefore),
+			Limit:       w.batchSize,
+			MaxAttempts: w.maxAttempts,
+		}, func(job dto.SubReminderJob) dto.SubReminderToDb {
+			return w.notify(ctx, job)
+		})
+		if err != nil {
+			if ctx.Err() == nil {
+				w.log.Errorf("reminders: %v", err)
+			}
+			return
+		}
+		if done > 0 {
+			w.log.Infof("reminders: %d subs processed", done)
+		}
+		if done < w.batchSize || ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+func (w *Reminders) notify(ctx context.Context, job dto.SubReminderJob) dto.SubReminderToDb {
+	err := w.notifier.Notify(ctx, job)
+	switch {
+	case err == nil:
+		return dto.SubReminderToDb{Status: repository.ReminderSent}
+	case errors.Is(err, notify.ErrNoRecipient):
+		return dto.SubReminderToDb{Status: repository.ReminderSkipped, Error: err.Error()}
+	default:
+		w.log.Warnf("reminders: sub %d: %v", job.SubId, err)
+		return dto.SubReminderToDb{Status: repository.ReminderFailed, Error: err.Error()}
+	}
+}
@@ -0,0 +1,84 @@
+package worker
+
+import (
+	"context"
+	"service/internal/datasource/repository"
+	"service/internal/dto"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+type (
+	RenewalConfig interface {
+		GetRenewalInterval() time.Duration
+		GetRenewalWindow() time.Duration
+		GetRenewalBatchSize() int
+	}
+
+	Renewal struct {
+		storage   repository.Storage
+		log       *logrus.Logger
+		interval  time.Duration
+		window    time.Duration
+		batchSize int
+	}
+)
+
+func NewRenewal(storage repository.Storage, cfg RenewalConfig, log *logrus.Logger) *Renewal {
+	return &Renewal{
+		storage:   storage,
+		log:       log,
+		interval:  cfg.GetRenewalInterval(),
+		window:    cfg.GetRenewalWindow(),
+		batchSize: cfg.GetRenewalBatchSize(),
+	}
+}
+
+func (w *Renewal) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		w.batch(ctx, "renewal", func(ctx context.Context) (int, error) {
+			return w.storage.RenewSubs(ctx, dto.RenewSubsToDb{
+				Before: time.Now().Add(w.window),
+				Limit:  w.batchSize,
+			})
+		})
+		w.batch(ctx, "trial", func(ctx context.Context) (int, error) {
+			return w.storage.EndTrials(ctx, dto.ExpireSubsToDb{
+				Before: time.Now(),
+				Limit:  w.batchSize,
+			})
+		})
+		w.batch(ctx, "expire", func(ctx context.Context) (int, error) {
+			return w.storage.ExpireSubs(ctx, dto.ExpireSubsToDb{
+				Before: time.Now(),
+				Limit:  w.batchSize,
+			})
+		})
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *Renewal) batch(ctx context.Context, name string, step func(ctx context.Context) (int, error)) {
+	for {
+		done, err := step(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				w.log.Errorf("%s: %v", name, err)
+			}
+			return
+		}
+		if done > 0 {
+			w.log.Infof("%s: %d subs processed", name, done)
+		}
+		if done < w.batchSize {
+			return
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package worker
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"service/internal/datasource/repository"
+	"service/internal/dto"
+	"service/internal/events"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+type (
+	WebhooksConfig interface {
+		GetWebhooksInterval() time.Duration
+		GetWebhooksBatchSize() int
+		GetWebhooksTimeout() time.Duration
+		GetWebhooksMaxAttempts() int
+		GetWebhooksBackoffBase() time.Duration
+		GetWebhooksBackoffMax() time.Duration
+	}
+
+	Webhooks struct {
+		storage     repository.Storage
+		client      *http.Client
+		log         *logrus.Logger
+		interval    time.Duration
+		batchSize   int
+		maxAttempts int
+		backoffBase time.Duration
+		backoffMax  time.Duration
+	}
+)
+
+func NewWebhooks(storage repository.Storage, cfg WebhooksConfig, log *logrus.Logger) *Webhooks {
+	return &Webhooks{
+		storage:     storage,
+		client:      events.NewWebhookClient(cfg.GetWebhooksTimeout()),
+		log:         log,
+		interval:    cfg.GetWebhooksInterval(),
+		batchSize:   cfg.GetWebhooksBatchSize(),
+		maxAttempts: cfg.GetWebhooksMaxAttempts(),
+		backoffBase: cfg.GetWebhooksBackoffBase(),
+		backoffMax:  cfg.GetWebhooksBackoffMax(),
+	}
+}
+
+func (w *Webhooks) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		w.dispatch(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *Webhooks) dispatch(ctx context.Context) {
+	for {
+		done, err := w.storage.DeliverWebhooks(ctx, w.batchSize, func(job dto.WebhookDeliveryJob) dto.WebhookAttemptToDb {
+			return w.deliver(ctx, job)
+		})
+		if err != nil {
+			if ctx.Err() == nil {
+				w.log.Errorf("webhooks: %v", err)
+			}
+			return
+		}
+		if done > 0 {
+			w.log.Debugf("webhooks: %d deliveries attempted", done)
+		}
+		if done < w.batchSize || ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+func (w *Webhooks) deliver(ctx context.Context, job dto.WebhookDeliveryJob) dto.WebhookAttemptToDb {
+	start := time.Now()
+	code, err := w.send(ctx, job, start)
+	attempt := dto.WebhookAttemptToDb{
+		ResponseCode: code,
+		Duration:     time.Since(start),
+		Delivered:    err == nil,
+		NextAttempt:  time.Now(),
+	}
+	if err == nil {
+		return attempt
+	}
+	attempt.Error = err.Error()
+	attempts := job.Attempts + 1
+	if attempts >= w.maxAttempts {
+		attempt.GiveUp = true
+		w.log.Warnf("webhooks: delivery %d failed after %d attempts: %v", job.Id, attempts, err)
+		return attempt
+	}
+	attempt.NextAttempt = time.Now().Add(backoff(w.backoffBase, w.backoffMax, attempts))
+	return attempt
+}
+
+func (w *Webhooks) send(ctx context.Context, job dto.WebhookDeliveryJob, ts time.Time) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, job.Url, bytes.NewReader(job.Payload))
+	if err != nil {
+		return 0, fmt.Errorf("%w", err)
+	}
+	req.Header.Set("Content-Type", "application/cloudevents+json")
+	req.Header.Set(events.HeaderWebhookId, strconv.FormatInt(job.Id, 10))
+	req.Header.Set(events.HeaderWebhookEvent, job.EventType)
+	req.Header.Set(events.HeaderWebhookTimestamp, strconv.FormatInt(ts.Unix(), 10))
+	req.Header.Set(events.HeaderWebhookSignature, events.Sign(job.Secret, ts, job.Payload))
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("%w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,125 @@
+package worker
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"service/internal/dto"
+	"service/internal/events"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+func newTestWebhooks() *Webhooks {
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	return &Webhooks{
+		client:      &http.Client{Timeout: time.Second},
+		log:         log,
+		maxAttempts: 3,
+		backoffBase: time.Minute,
+		backoffMax:  time.Hour,
+	}
+}
+
+func TestWebhooksDeliverSigned(t *testing.T) {
+	payload := []byte(`{"id":"2b7d1c1e"}`)
+	var got *http.Request
+	var body []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	job := dto.WebhookDeliveryJob{
+		Id:        7,
+		Url:       srv.URL,
+		Secret:    "s3cr3t",
+		EventType: "subscription.created",
+		Payload:   payload,
+	}
+	attempt := newTestWebhooks().deliver(t.Context(), job)
+	if !attempt.Delivered || attempt.GiveUp || attempt.Error != "" {
+		t.Fatalf("unexpected attempt: %+v", attempt)
+	}
+	if attempt.ResponseCode != http.StatusNoContent {
+		t.Fatalf("response code = %d, want %d", attempt.ResponseCode, http.StatusNoContent)
+	}
+	if string(body) != string(payload) {
+		t.Fatalf("body = %s, want %s", body, payload)
+	}
+	if id := got.Header.Get(events.HeaderWebhookId); id != "7" {
+		t.Fatalf("%s = %s, want 7", events.HeaderWebhookId, id)
+	}
+	if event := got.Header.Get(events.HeaderWebhookEvent); event != job.EventType {
+		t.Fatalf("%s = %s, want %s", events.HeaderWebhookEvent, event, job.EventType)
+	}
+	unix, err := strconv.ParseInt(got.Header.Get(events.HeaderWebhookTimestamp), 10, 64)
+	if err != nil {
+		t.Fatalf("invalid timestamp: %v", err)
+	}
+	want := events.Sign(job.Secret, time.Unix(unix, 0), payload)
+	if sig := got.Header.Get(events.HeaderWebhookSignature); sig != want {
+		t.Fatalf("%s = %s, want %s", events.HeaderWebhookSignature, sig, want)
+	}
+}
+
+func TestWebhooksDeliverFailure(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name      string
+		attempts  int
+		wantDelay time.Duration
+		giveUp    bool
+	}{
+		{name: "first failure", attempts: 0, wantDelay: time.Minute},
+		{name: "second failure", attempts: 1, wantDelay: 2 * time.Minute},
+		{name: "last attempt", attempts: 2, giveUp: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := time.Now()
+			attempt := newTestWebhooks().deliver(t.Context(), dto.WebhookDeliveryJob{
+				Id:       1,
+				Url:      srv.URL,
+				Payload:  []byte(`{}`),
+				Attempts: tt.attempts,
+			})
+			if attempt.Delivered || attempt.Error == "" {
+				t.Fatalf("unexpected attempt: %+v", attempt)
+			}
+			if attempt.ResponseCode != http.StatusBadGateway {
+				t.Fatalf("response code = %d, want %d", attempt.ResponseCode, http.StatusBadGateway)
+			}
+			if attempt.GiveUp != tt.giveUp {
+				t.Fatalf("give up = %v, want %v", attempt.GiveUp, tt.giveUp)
+			}
+			if tt.giveUp {
+				return
+			}
+			if delay := attempt.NextAttempt.Sub(before); delay < tt.wantDelay || delay > tt.wantDelay+time.Second {
+				t.Fatalf("next attempt in %s, want %s", delay, tt.wantDelay)
+			}
+		})
+	}
+}
+
+func TestWebhooksDeliverUnreachable(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	url := srv.URL
+	srv.Close()
+
+	attempt := newTestWebhooks().deliver(t.Context(), dto.WebhookDeliveryJob{Id: 1, Url: url, Payload: []byte(`{}`)})
+	if attempt.Delivered || attempt.ResponseCode != 0 || attempt.Error == "" {
+		t.Fatalf("unexpected attempt: %+v", attempt)
+	}
+}
@@ -1,43 +1,107 @@
 package logger
 
 import (
+	"fmt"
+	"io"
+	"log/syslog"
 	"os"
 	"service/internal/config"
 
 	"github.com/sirupsen/logrus"
+	lsyslog "github.com/sirupsen/logrus/hooks/syslog"
+	"gopkg.in/natefinch/lumberjack.v2"
 )
 
-func Init(cfg config.Config) *logrus.Logger {
+const (
+	OutStdout = "stdout"
+	OutStderr = "stderr"
+	OutSyslog = "syslog"
+
+	FormatJSON        = "json"
+	FormatJSONCompact = "json_compact"
+	FormatText        = "text"
+
+	timestampFormat = "2006-01-02 15:04:05"
+)
+
+// Init builds the application logger. log_out is stdout, stderr, syslog or
+// the path of a file rotated by size and age.
+func Init(cfg config.Config) (*logrus.Logger, error) {
 	log := logrus.New()
-	log.SetFormatter(&logrus.JSONFormatter{
-		TimestampFormat: "2006-01-02 15:04:05",
-		PrettyPrint:     true,
-	})
-
-	switch cfg.GetLogLevel() {
-	case "trace":
-		log.SetLevel(logrus.TraceLevel)
-	case "debug":
-		log.SetLevel(logrus.DebugLevel)
-	case "warn":
-		log.SetLevel(logrus.WarnLevel)
-	case "error":
-		log.SetLevel(logrus.ErrorLevel)
-	case "fatal":
-		log.SetLevel(logrus.FatalLevel)
-	case "panic":
-		log.SetLevel(logrus.PanicLevel)
+
+	formatter, err := newFormatter(cfg.GetLogFormat())
+	if err != nil {
+		return nil, err
+	}
+	log.SetFormatter(formatter)
+
+	level, err := parseLevel(cfg.GetLogLevel())
+	if err != nil {
+		return nil, err
+	}
+	log.SetLevel(level)
+
+	switch out := cfg.GetLogOut(); out {
+	case "", OutStdout:
+		log.SetOutput(os.Stdout)
+	case OutStderr:
+		log.SetOutput(os.Stderr)
+	case OutSyslog:
+		hook, err := lsyslog.NewSyslogHook(
+			cfg.GetLogSyslogNetwork(),
+			cfg.GetLogSyslogAddress(),
+			syslog.LOG_INFO|syslog.LOG_DAEMON,
+			cfg.GetLogSyslogTag(),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("syslog: %w", err)
+		}
+		log.AddHook(hook)
+		log.SetOutput(io.Discard)
 	default:
-		log.SetLevel(logrus.InfoLevel)
+		log.SetOutput(&lumberjack.Logger{
+			Filename:   out,
+			MaxSize:    cfg.GetLogMaxSize(),
+			MaxAge:     cfg.GetLogMaxAge(),
+			MaxBackups: cfg.GetLogMaxBackups(),
+			Compress:   cfg.GetLogCompress(),
+			LocalTime:  true,
+		})
 	}
 
-	log.SetOutput(os.Stdout)
-	// switch cfg.GetLogOut() {
-	// case "":
-	// 	log.SetOutput(os.Stdout)
-	// default:
-	// 	log.SetOutput(os.Stdout) //TODO: not yet
-	// }
+	return log, nil
+}
+
+// parseLevel defaults an empty log_level to info and rejects unknown ones,
+// so a typo does not silently change what gets logged.
+func parseLevel(level string) (logrus.Level, error) {
+	if level == "" {
+		return logrus.InfoLevel, nil
+	}
+	parsed, err := logrus.ParseLevel(level)
+	if err != nil {
+		return 0, fmt.Errorf("unknown log level: %s", level)
+	}
+	return parsed, nil
+}
 
-	return log
+func newFormatter(format string) (logrus.Formatter, error) {
+	switch format {
+	case FormatJSON:
+		return &logrus.JSONFormatter{
+			TimestampFormat: timestampFormat,
+			PrettyPrint:     true,
+		}, nil
+	case "", FormatJSONCompact:
+		return &logrus.JSONFormatter{
+			TimestampFormat: timestampFormat,
+		}, nil
+	case FormatText:
+		return &logrus.TextFormatter{
+			TimestampFormat: timestampFormat,
+			FullTimestamp:   true,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown log format: %s", format)
+	}
 }
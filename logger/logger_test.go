@@ -0,0 +1,34 @@
+package logger
+
+import (
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		level   string
+		want    logrus.Level
+		wantErr bool
+	}{
+		{level: "", want: logrus.InfoLevel},
+		{level: "debug", want: logrus.DebugLevel},
+		{level: "WARN", want: logrus.WarnLevel},
+		{level: "warning", want: logrus.WarnLevel},
+		{level: "trace", want: logrus.TraceLevel},
+		{level: "verbose", wantErr: true},
+		{level: "inf", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.level, func(t *testing.T) {
+			got, err := parseLevel(tt.level)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseLevel(%q) error = %v, wantErr %v", tt.level, err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Fatalf("parseLevel(%q) = %s, want %s", tt.level, got, tt.want)
+			}
+		})
+	}
+}
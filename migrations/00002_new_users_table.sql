@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE users (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  display_name TEXT NOT NULL,
+  email TEXT UNIQUE,
+  timezone TEXT NOT NULL DEFAULT 'UTC',
+  status TEXT NOT NULL DEFAULT 'active',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+INSERT INTO users (id, display_name)
+SELECT DISTINCT user_id, user_id::TEXT
+FROM subs
+WHERE user_id IS NOT NULL;
+
+ALTER TABLE subs
+  ADD CONSTRAINT subs_user_id_fkey FOREIGN KEY (user_id) REFERENCES users (id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE subs DROP CONSTRAINT subs_user_id_fkey;
+DROP TABLE users;
+-- +goose StatementEnd
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE subs ADD COLUMN auto_renew BOOLEAN NOT NULL DEFAULT false;
+
+CREATE TABLE sub_renewals (
+  id SERIAL PRIMARY KEY,
+  sub_id INTEGER NOT NULL REFERENCES subs (id) ON DELETE CASCADE,
+  price NUMERIC,
+  period_start TIMESTAMPTZ NOT NULL,
+  period_end TIMESTAMPTZ NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX subs_auto_renew_end_date_idx ON subs (end_date) WHERE auto_renew;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE sub_renewals;
+ALTER TABLE subs DROP COLUMN auto_renew;
+-- +goose StatementEnd
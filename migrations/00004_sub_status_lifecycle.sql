@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE subs
+  ADD COLUMN status TEXT NOT NULL DEFAULT 'active'
+    CHECK (status IN ('trial', 'active', 'paused', 'cancelled', 'expired')),
+  ADD COLUMN status_changed_at TIMESTAMPTZ NOT NULL DEFAULT now();
+
+UPDATE subs SET status = 'expired' WHERE end_date <= now() AND NOT auto_renew;
+
+CREATE TABLE sub_status_history (
+  id SERIAL PRIMARY KEY,
+  sub_id INTEGER NOT NULL REFERENCES subs (id) ON DELETE CASCADE,
+  from_status TEXT NOT NULL,
+  to_status TEXT NOT NULL,
+  changed_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX sub_status_history_sub_id_idx ON sub_status_history (sub_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE sub_status_history;
+ALTER TABLE subs DROP COLUMN status_changed_at, DROP COLUMN status;
+-- +goose StatementEnd
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE sub_pauses (
+  id SERIAL PRIMARY KEY,
+  sub_id INTEGER NOT NULL REFERENCES subs (id) ON DELETE CASCADE,
+  paused_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  resumed_at TIMESTAMPTZ
+);
+
+CREATE INDEX sub_pauses_sub_id_idx ON sub_pauses (sub_id);
+
+INSERT INTO sub_pauses (sub_id, paused_at)
+SELECT id, status_changed_at
+FROM subs
+WHERE status = 'paused';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE sub_pauses;
+-- +goose StatementEnd
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE subscription_prices (
+  id SERIAL PRIMARY KEY,
+  sub_id INTEGER NOT NULL REFERENCES subs (id) ON DELETE CASCADE,
+  price NUMERIC NOT NULL,
+  effective_from TIMESTAMPTZ NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX subscription_prices_sub_id_effective_from_idx ON subscription_prices (sub_id, effective_from);
+
+INSERT INTO subscription_prices (sub_id, price, effective_from)
+SELECT id, COALESCE(price, 0), date_trunc('month', start_date)
+FROM subs;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE subscription_prices;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE subs
+  ADD COLUMN trial_months INTEGER NOT NULL DEFAULT 0 CHECK (trial_months >= 0),
+  ADD COLUMN discount_type TEXT NOT NULL DEFAULT '' CHECK (discount_type IN ('', 'percent', 'fixed')),
+  ADD COLUMN discount_value NUMERIC NOT NULL DEFAULT 0 CHECK (discount_value >= 0),
+  ADD COLUMN discount_months INTEGER NOT NULL DEFAULT 0 CHECK (discount_months >= 0);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE subs
+  DROP COLUMN discount_months,
+  DROP COLUMN discount_value,
+  DROP COLUMN discount_type,
+  DROP COLUMN trial_months;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE subs
+  ADD COLUMN billing_period TEXT NOT NULL DEFAULT 'month'
+    CHECK (billing_period IN ('week', 'month', 'quarter', 'year'));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE subs DROP COLUMN billing_period;
+-- +goose StatementEnd
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users
+  ADD COLUMN reminders_enabled BOOLEAN NOT NULL DEFAULT true;
+
+CREATE TABLE sub_reminders (
+  id BIGSERIAL PRIMARY KEY,
+  sub_id INTEGER NOT NULL REFERENCES subs (id) ON DELETE CASCADE,
+  user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+  period_end TIMESTAMPTZ NOT NULL,
+  status TEXT NOT NULL CHECK (status IN ('sent', 'skipped', 'failed')),
+  attempts INTEGER NOT NULL DEFAULT 1,
+  last_error TEXT,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  UNIQUE (sub_id, period_end)
+);
+
+CREATE INDEX sub_reminders_user_id_idx ON sub_reminders (user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE sub_reminders;
+ALTER TABLE users DROP COLUMN reminders_enabled;
+-- +goose StatementEnd
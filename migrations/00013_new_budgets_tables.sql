@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE budgets (
+  id SERIAL PRIMARY KEY,
+  user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+  category TEXT NOT NULL DEFAULT '',
+  amount INTEGER NOT NULL CHECK (amount > 0),
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  UNIQUE (user_id, category)
+);
+
+CREATE TABLE budget_alerts (
+  id BIGSERIAL PRIMARY KEY,
+  budget_id INTEGER NOT NULL REFERENCES budgets (id) ON DELETE CASCADE,
+  month TIMESTAMPTZ NOT NULL,
+  amount INTEGER NOT NULL,
+  spend BIGINT NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  UNIQUE (budget_id, month, amount)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE budget_alerts;
+DROP TABLE budgets;
+-- +goose StatementEnd
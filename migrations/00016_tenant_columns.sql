@@ -0,0 +1,37 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users ADD COLUMN tenant_id TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE subs ADD COLUMN tenant_id TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE budgets ADD COLUMN tenant_id TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE webhooks ADD COLUMN tenant_id TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE api_keys ADD COLUMN tenant_id TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE outbox ADD COLUMN tenant_id TEXT NOT NULL DEFAULT 'default';
+
+ALTER TABLE users
+  DROP CONSTRAINT users_email_key,
+  ADD CONSTRAINT users_tenant_id_email_key UNIQUE (tenant_id, email);
+
+CREATE INDEX users_tenant_id_idx ON users (tenant_id);
+CREATE INDEX subs_tenant_id_user_id_idx ON subs (tenant_id, user_id);
+CREATE INDEX budgets_tenant_id_idx ON budgets (tenant_id);
+CREATE INDEX webhooks_tenant_id_idx ON webhooks (tenant_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX webhooks_tenant_id_idx;
+DROP INDEX budgets_tenant_id_idx;
+DROP INDEX subs_tenant_id_user_id_idx;
+DROP INDEX users_tenant_id_idx;
+
+ALTER TABLE users
+  DROP CONSTRAINT users_tenant_id_email_key,
+  ADD CONSTRAINT users_email_key UNIQUE (email);
+
+ALTER TABLE outbox DROP COLUMN tenant_id;
+ALTER TABLE api_keys DROP COLUMN tenant_id;
+ALTER TABLE webhooks DROP COLUMN tenant_id;
+ALTER TABLE budgets DROP COLUMN tenant_id;
+ALTER TABLE subs DROP COLUMN tenant_id;
+ALTER TABLE users DROP COLUMN tenant_id;
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE outbox
+  ADD COLUMN next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  ADD COLUMN fanned_out_at TIMESTAMPTZ,
+  ADD COLUMN dead_at TIMESTAMPTZ;
+
+DROP INDEX outbox_unpublished_idx;
+CREATE INDEX outbox_pending_idx ON outbox (next_attempt_at) WHERE published_at IS NULL AND dead_at IS NULL;
+CREATE INDEX outbox_event_id_idx ON outbox (event_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX outbox_event_id_idx;
+DROP INDEX outbox_pending_idx;
+CREATE INDEX outbox_unpublished_idx ON outbox (id) WHERE published_at IS NULL;
+
+ALTER TABLE outbox
+  DROP COLUMN dead_at,
+  DROP COLUMN fanned_out_at,
+  DROP COLUMN next_attempt_at;
+-- +goose StatementEnd
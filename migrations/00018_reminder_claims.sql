@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE sub_reminders
+  DROP CONSTRAINT sub_reminders_status_check,
+  ADD CONSTRAINT sub_reminders_status_check CHECK (status IN ('sending', 'sent', 'skipped', 'failed'));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+UPDATE sub_reminders SET status = 'failed' WHERE status = 'sending';
+
+ALTER TABLE sub_reminders
+  DROP CONSTRAINT sub_reminders_status_check,
+  ADD CONSTRAINT sub_reminders_status_check CHECK (status IN ('sent', 'skipped', 'failed'));
+-- +goose StatementEnd
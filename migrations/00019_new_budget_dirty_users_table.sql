@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE budget_dirty_users (
+  user_id UUID PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+  marked_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX budget_dirty_users_marked_at_idx ON budget_dirty_users (marked_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE budget_dirty_users;
+-- +goose StatementEnd
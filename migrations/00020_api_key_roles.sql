@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE api_keys RENAME COLUMN scopes TO roles;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE api_keys RENAME COLUMN roles TO scopes;
+-- +goose StatementEnd
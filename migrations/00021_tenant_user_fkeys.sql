@@ -0,0 +1,71 @@
+-- +goose Up
+-- +goose StatementBegin
+-- References to users include the tenant, so a row can only point at a user
+-- of its own tenant. sub_reminders and budget_dirty_users get a tenant_id for
+-- this; it has no default and is taken from the parent sub or budget.
+ALTER TABLE users ADD CONSTRAINT users_tenant_id_id_key UNIQUE (tenant_id, id);
+
+ALTER TABLE sub_reminders ADD COLUMN tenant_id TEXT;
+UPDATE sub_reminders r SET tenant_id = s.tenant_id FROM subs s WHERE s.id = r.sub_id;
+ALTER TABLE sub_reminders ALTER COLUMN tenant_id SET NOT NULL;
+
+ALTER TABLE budget_dirty_users ADD COLUMN tenant_id TEXT;
+UPDATE budget_dirty_users d SET tenant_id = u.tenant_id FROM users u WHERE u.id = d.user_id;
+ALTER TABLE budget_dirty_users ALTER COLUMN tenant_id SET NOT NULL;
+
+ALTER TABLE subs
+  DROP CONSTRAINT subs_user_id_fkey,
+  ADD CONSTRAINT subs_tenant_id_user_id_fkey FOREIGN KEY (tenant_id, user_id)
+    REFERENCES users (tenant_id, id);
+
+ALTER TABLE sub_reminders
+  DROP CONSTRAINT sub_reminders_user_id_fkey,
+  ADD CONSTRAINT sub_reminders_tenant_id_user_id_fkey FOREIGN KEY (tenant_id, user_id)
+    REFERENCES users (tenant_id, id) ON DELETE CASCADE;
+
+ALTER TABLE budgets
+  DROP CONSTRAINT budgets_user_id_fkey,
+  ADD CONSTRAINT budgets_tenant_id_user_id_fkey FOREIGN KEY (tenant_id, user_id)
+    REFERENCES users (tenant_id, id) ON DELETE CASCADE;
+
+ALTER TABLE api_keys
+  DROP CONSTRAINT api_keys_user_id_fkey,
+  ADD CONSTRAINT api_keys_tenant_id_user_id_fkey FOREIGN KEY (tenant_id, user_id)
+    REFERENCES users (tenant_id, id) ON DELETE CASCADE;
+
+ALTER TABLE budget_dirty_users
+  DROP CONSTRAINT budget_dirty_users_user_id_fkey,
+  ADD CONSTRAINT budget_dirty_users_tenant_id_user_id_fkey FOREIGN KEY (tenant_id, user_id)
+    REFERENCES users (tenant_id, id) ON DELETE CASCADE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE budget_dirty_users
+  DROP CONSTRAINT budget_dirty_users_tenant_id_user_id_fkey,
+  ADD CONSTRAINT budget_dirty_users_user_id_fkey FOREIGN KEY (user_id)
+    REFERENCES users (id) ON DELETE CASCADE;
+
+ALTER TABLE api_keys
+  DROP CONSTRAINT api_keys_tenant_id_user_id_fkey,
+  ADD CONSTRAINT api_keys_user_id_fkey FOREIGN KEY (user_id)
+    REFERENCES users (id) ON DELETE CASCADE;
+
+ALTER TABLE budgets
+  DROP CONSTRAINT budgets_tenant_id_user_id_fkey,
+  ADD CONSTRAINT budgets_user_id_fkey FOREIGN KEY (user_id)
+    REFERENCES users (id) ON DELETE CASCADE;
+
+ALTER TABLE sub_reminders
+  DROP CONSTRAINT sub_reminders_tenant_id_user_id_fkey,
+  ADD CONSTRAINT sub_reminders_user_id_fkey FOREIGN KEY (user_id)
+    REFERENCES users (id) ON DELETE CASCADE;
+
+ALTER TABLE subs
+  DROP CONSTRAINT subs_tenant_id_user_id_fkey,
+  ADD CONSTRAINT subs_user_id_fkey FOREIGN KEY (user_id) REFERENCES users (id);
+
+ALTER TABLE budget_dirty_users DROP COLUMN tenant_id;
+ALTER TABLE sub_reminders DROP COLUMN tenant_id;
+ALTER TABLE users DROP CONSTRAINT users_tenant_id_id_key;
+-- +goose StatementEnd
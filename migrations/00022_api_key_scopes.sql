@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Keys carry scopes again: read, write and admin. Existing keys without the
+-- admin role could read and write, so they get the write scope.
+ALTER TABLE api_keys RENAME COLUMN roles TO scopes;
+UPDATE api_keys SET scopes = ARRAY['write'] WHERE scopes = '{}';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+UPDATE api_keys SET scopes = array_remove(array_remove(scopes, 'read'), 'write');
+ALTER TABLE api_keys RENAME COLUMN scopes TO roles;
+-- +goose StatementEnd
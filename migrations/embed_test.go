@@ -0,0 +1,25 @@
+package migrations
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+)
+
+func TestLatestVersion(t *testing.T) {
+	got, err := LatestVersion()
+	if err != nil {
+		t.Fatal(err)
+	}
+	files, err := filepath.Glob("*.sql")
+	if err != nil || len(files) == 0 {
+		t.Fatalf("failed to list migrations: %v", err)
+	}
+	var want int64
+	if _, err := fmt.Sscanf(files[len(files)-1], "%d_", &want); err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Fatalf("LatestVersion() = %d, want %d", got, want)
+	}
+}
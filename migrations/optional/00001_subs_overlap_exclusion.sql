@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
+ALTER TABLE subs
+  ADD CONSTRAINT subs_no_overlap EXCLUDE USING gist (
+    user_id WITH =,
+    lower(service_name) WITH =,
+    tstzrange(start_date, end_date) WITH &&
+  ) WHERE (status IN ('trial', 'active', 'paused'));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE subs DROP CONSTRAINT subs_no_overlap;
+-- +goose StatementEnd
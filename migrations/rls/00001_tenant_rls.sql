@@ -0,0 +1,155 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Rows are visible when app.tenant_id is unset or empty (background jobs)
+-- or equal to their tenant. The service sets app.tenant_id for every
+-- request when tenancy.rls is enabled.
+CREATE FUNCTION app_tenant_visible(tenant TEXT) RETURNS BOOLEAN
+LANGUAGE SQL STABLE AS $$
+  SELECT COALESCE(current_setting('app.tenant_id', true), '') IN ('', tenant)
+$$;
+
+ALTER TABLE users ENABLE ROW LEVEL SECURITY;
+ALTER TABLE users FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON users
+  USING (app_tenant_visible(tenant_id))
+  WITH CHECK (app_tenant_visible(tenant_id));
+
+ALTER TABLE subs ENABLE ROW LEVEL SECURITY;
+ALTER TABLE subs FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON subs
+  USING (app_tenant_visible(tenant_id))
+  WITH CHECK (app_tenant_visible(tenant_id));
+
+ALTER TABLE budgets ENABLE ROW LEVEL SECURITY;
+ALTER TABLE budgets FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON budgets
+  USING (app_tenant_visible(tenant_id))
+  WITH CHECK (app_tenant_visible(tenant_id));
+
+ALTER TABLE webhooks ENABLE ROW LEVEL SECURITY;
+ALTER TABLE webhooks FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON webhooks
+  USING (app_tenant_visible(tenant_id))
+  WITH CHECK (app_tenant_visible(tenant_id));
+
+ALTER TABLE api_keys ENABLE ROW LEVEL SECURITY;
+ALTER TABLE api_keys FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON api_keys
+  USING (app_tenant_visible(tenant_id))
+  WITH CHECK (app_tenant_visible(tenant_id));
+
+ALTER TABLE outbox ENABLE ROW LEVEL SECURITY;
+ALTER TABLE outbox FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON outbox
+  USING (app_tenant_visible(tenant_id))
+  WITH CHECK (app_tenant_visible(tenant_id));
+
+ALTER TABLE sub_renewals ENABLE ROW LEVEL SECURITY;
+ALTER TABLE sub_renewals FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON sub_renewals
+  USING (EXISTS (SELECT 1 FROM subs WHERE subs.id = sub_renewals.sub_id))
+  WITH CHECK (EXISTS (SELECT 1 FROM subs WHERE subs.id = sub_renewals.sub_id));
+
+ALTER TABLE sub_status_history ENABLE ROW LEVEL SECURITY;
+ALTER TABLE sub_status_history FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON sub_status_history
+  USING (EXISTS (SELECT 1 FROM subs WHERE subs.id = sub_status_history.sub_id))
+  WITH CHECK (EXISTS (SELECT 1 FROM subs WHERE subs.id = sub_status_history.sub_id));
+
+ALTER TABLE sub_pauses ENABLE ROW LEVEL SECURITY;
+ALTER TABLE sub_pauses FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON sub_pauses
+  USING (EXISTS (SELECT 1 FROM subs WHERE subs.id = sub_pauses.sub_id))
+  WITH CHECK (EXISTS (SELECT 1 FROM subs WHERE subs.id = sub_pauses.sub_id));
+
+ALTER TABLE subscription_prices ENABLE ROW LEVEL SECURITY;
+ALTER TABLE subscription_prices FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON subscription_prices
+  USING (EXISTS (SELECT 1 FROM subs WHERE subs.id = subscription_prices.sub_id))
+  WITH CHECK (EXISTS (SELECT 1 FROM subs WHERE subs.id = subscription_prices.sub_id));
+
+ALTER TABLE sub_reminders ENABLE ROW LEVEL SECURITY;
+ALTER TABLE sub_reminders FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON sub_reminders
+  USING (EXISTS (SELECT 1 FROM subs WHERE subs.id = sub_reminders.sub_id))
+  WITH CHECK (EXISTS (SELECT 1 FROM subs WHERE subs.id = sub_reminders.sub_id));
+
+ALTER TABLE budget_alerts ENABLE ROW LEVEL SECURITY;
+ALTER TABLE budget_alerts FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON budget_alerts
+  USING (EXISTS (SELECT 1 FROM budgets WHERE budgets.id = budget_alerts.budget_id))
+  WITH CHECK (EXISTS (SELECT 1 FROM budgets WHERE budgets.id = budget_alerts.budget_id));
+
+ALTER TABLE webhook_deliveries ENABLE ROW LEVEL SECURITY;
+ALTER TABLE webhook_deliveries FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON webhook_deliveries
+  USING (EXISTS (SELECT 1 FROM webhooks WHERE webhooks.id = webhook_deliveries.webhook_id))
+  WITH CHECK (EXISTS (SELECT 1 FROM webhooks WHERE webhooks.id = webhook_deliveries.webhook_id));
+
+ALTER TABLE webhook_delivery_attempts ENABLE ROW LEVEL SECURITY;
+ALTER TABLE webhook_delivery_attempts FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON webhook_delivery_attempts
+  USING (EXISTS (SELECT 1 FROM webhook_deliveries WHERE webhook_deliveries.id = webhook_delivery_attempts.delivery_id))
+  WITH CHECK (EXISTS (SELECT 1 FROM webhook_deliveries WHERE webhook_deliveries.id = webhook_delivery_attempts.delivery_id));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP POLICY tenant_isolation ON webhook_delivery_attempts;
+ALTER TABLE webhook_delivery_attempts NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE webhook_delivery_attempts DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY tenant_isolation ON webhook_deliveries;
+ALTER TABLE webhook_deliveries NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE webhook_deliveries DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY tenant_isolation ON budget_alerts;
+ALTER TABLE budget_alerts NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE budget_alerts DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY tenant_isolation ON sub_reminders;
+ALTER TABLE sub_reminders NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE sub_reminders DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY tenant_isolation ON subscription_prices;
+ALTER TABLE subscription_prices NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE subscription_prices DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY tenant_isolation ON sub_pauses;
+ALTER TABLE sub_pauses NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE sub_pauses DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY tenant_isolation ON sub_status_history;
+ALTER TABLE sub_status_history NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE sub_status_history DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY tenant_isolation ON sub_renewals;
+ALTER TABLE sub_renewals NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE sub_renewals DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY tenant_isolation ON outbox;
+ALTER TABLE outbox NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE outbox DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY tenant_isolation ON api_keys;
+ALTER TABLE api_keys NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE api_keys DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY tenant_isolation ON webhooks;
+ALTER TABLE webhooks NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE webhooks DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY tenant_isolation ON budgets;
+ALTER TABLE budgets NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE budgets DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY tenant_isolation ON subs;
+ALTER TABLE subs NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE subs DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY tenant_isolation ON users;
+ALTER TABLE users NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE users DISABLE ROW LEVEL SECURITY;
+
+DROP FUNCTION app_tenant_visible(TEXT);
+-- +goose StatementEnd
@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Rows are visible only when app.tenant_id equals their tenant; an unset or
+-- empty value hides every row. Background jobs connect as a role with
+-- BYPASSRLS instead of relying on an empty tenant.
+CREATE OR REPLACE FUNCTION app_tenant_visible(tenant TEXT) RETURNS BOOLEAN
+LANGUAGE SQL STABLE AS $$
+  SELECT COALESCE(current_setting('app.tenant_id', true) = tenant, false)
+$$;
+
+ALTER TABLE budget_dirty_users ENABLE ROW LEVEL SECURITY;
+ALTER TABLE budget_dirty_users FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON budget_dirty_users
+  USING (EXISTS (SELECT 1 FROM users WHERE users.id = budget_dirty_users.user_id))
+  WITH CHECK (EXISTS (SELECT 1 FROM users WHERE users.id = budget_dirty_users.user_id));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP POLICY tenant_isolation ON budget_dirty_users;
+ALTER TABLE budget_dirty_users NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE budget_dirty_users DISABLE ROW LEVEL SECURITY;
+
+CREATE OR REPLACE FUNCTION app_tenant_visible(tenant TEXT) RETURNS BOOLEAN
+LANGUAGE SQL STABLE AS $$
+  SELECT COALESCE(current_setting('app.tenant_id', true), '') IN ('', tenant)
+$$;
+-- +goose StatementEnd